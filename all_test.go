@@ -0,0 +1,91 @@
+// Package gotencore_test imports every package of the module, so that a
+// package that does not compile, or that imports another module path,
+// fails go test ./... even when nothing else imports it.
+package gotencore_test
+
+import (
+	"go/parser"
+	"go/token"
+	"io/fs"
+	"path"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+
+	_ "github.com/ssgohq/goten-core/app"
+	_ "github.com/ssgohq/goten-core/conf"
+	_ "github.com/ssgohq/goten-core/grpcweb"
+	_ "github.com/ssgohq/goten-core/internal/appname"
+	_ "github.com/ssgohq/goten-core/internal/ctxkeys"
+	_ "github.com/ssgohq/goten-core/lifecycle"
+	_ "github.com/ssgohq/goten-core/logx"
+	_ "github.com/ssgohq/goten-core/metric"
+	_ "github.com/ssgohq/goten-core/middleware"
+	_ "github.com/ssgohq/goten-core/srpc"
+	_ "github.com/ssgohq/goten-core/srpc/errors"
+	_ "github.com/ssgohq/goten-core/srpc/middleware"
+	_ "github.com/ssgohq/goten-core/stores/mysql"
+	_ "github.com/ssgohq/goten-core/stores/postgres"
+	_ "github.com/ssgohq/goten-core/stores/redis"
+	_ "github.com/ssgohq/goten-core/stores/sqlc"
+	_ "github.com/ssgohq/goten-core/trace"
+)
+
+const modulePath = "github.com/ssgohq/goten-core"
+
+// TestImportsEveryPackage fails when a package is added without being
+// imported above, and when any file imports a package of the module under
+// another path.
+func TestImportsEveryPackage(t *testing.T) {
+	fset := token.NewFileSet()
+	self, err := parser.ParseFile(fset, "all_test.go", nil, parser.ImportsOnly)
+	if err != nil {
+		t.Fatal(err)
+	}
+	imported := make(map[string]bool)
+	for _, spec := range self.Imports {
+		p, _ := strconv.Unquote(spec.Path.Value)
+		imported[p] = true
+	}
+
+	packages := make(map[string]bool)
+	err = filepath.WalkDir(".", func(file string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			if name := d.Name(); file != "." && (strings.HasPrefix(name, ".") || name == "testdata") {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if !strings.HasSuffix(file, ".go") {
+			return nil
+		}
+
+		f, err := parser.ParseFile(fset, file, nil, parser.ImportsOnly)
+		if err != nil {
+			return err
+		}
+		for _, spec := range f.Imports {
+			p, _ := strconv.Unquote(spec.Path.Value)
+			if strings.Contains(p, "/goten-core") && p != modulePath && !strings.HasPrefix(p, modulePath+"/") {
+				t.Errorf("%s imports %s, outside module path %s", file, p, modulePath)
+			}
+		}
+		if dir := filepath.Dir(file); dir != "." && !strings.HasSuffix(file, "_test.go") {
+			packages[path.Join(modulePath, filepath.ToSlash(dir))] = true
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for p := range packages {
+		if !imported[p] {
+			t.Errorf("package %s is not imported by all_test.go", p)
+		}
+	}
+}
@@ -5,6 +5,7 @@ package app
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"os"
 	"os/signal"
@@ -17,8 +18,12 @@ import (
 	kitexserver "github.com/cloudwego/kitex/server"
 	hertztracing "github.com/hertz-contrib/obs-opentelemetry/tracing"
 
+	"github.com/ssgohq/goten-core/grpcweb"
+	"github.com/ssgohq/goten-core/internal/appname"
 	"github.com/ssgohq/goten-core/lifecycle"
 	"github.com/ssgohq/goten-core/logx"
+	"github.com/ssgohq/goten-core/metric"
+	"github.com/ssgohq/goten-core/middleware"
 	"github.com/ssgohq/goten-core/trace"
 )
 
@@ -79,11 +84,58 @@ func (c *Config) SetDefaults() {
 	}
 }
 
+// Validate checks the configuration for invalid values.
+func (c *Config) Validate() error {
+	if c.Name == "" {
+		return errors.New("app: name is required")
+	}
+	if c.GracePeriod < 0 || c.StopTimeout < 0 {
+		return errors.New("app: gracePeriod and stopTimeout must be >= 0")
+	}
+	if c.EnableTracing && c.Trace.IsEnabled() {
+		if err := c.Trace.Validate(); err != nil {
+			return fmt.Errorf("app: %w", err)
+		}
+	}
+	return nil
+}
+
+// Shutdown errors returned by Run.
+var (
+	// ErrStopFailed indicates that one or more services failed to stop cleanly.
+	ErrStopFailed = errors.New("app: services failed to stop cleanly")
+	// ErrForcedShutdown indicates that shutdown was interrupted by a second signal.
+	ErrForcedShutdown = errors.New("app: forced shutdown")
+)
+
+// ExitCode maps the error returned by Run to a process exit code:
+// 0 for a clean shutdown, 2 for a forced shutdown and 1 otherwise.
+//
+// Example:
+//
+//	os.Exit(app.ExitCode(application.Run(ctx)))
+func ExitCode(err error) int {
+	switch {
+	case err == nil:
+		return 0
+	case errors.Is(err, ErrForcedShutdown):
+		return 2
+	default:
+		return 1
+	}
+}
+
 // App represents a goten application with integrated services.
+//
+// Its methods are safe for concurrent use: services, hooks and validators
+// may be added while Run is starting or running. A service added once Run
+// has started its services is not started, but is stopped on shutdown.
 type App struct {
 	config         Config
 	manager        *lifecycle.Manager
 	services       []lifecycle.Service
+	validators     []func(ctx context.Context) error
+	health         *lifecycle.HealthManager
 	tracingEnabled bool
 	traceShutdown  func(context.Context) error
 	mu             sync.Mutex
@@ -92,8 +144,10 @@ type App struct {
 // New creates a new App with the given configuration.
 func New(cfg Config) *App {
 	cfg.SetDefaults()
+	appname.Set(cfg.Name)
 
 	lc := lifecycle.LifecycleConfig{
+		Name:            cfg.Name,
 		ShutdownTimeout: cfg.StopTimeout,
 		GracePeriod:     cfg.GracePeriod,
 	}
@@ -123,6 +177,33 @@ func (a *App) AddService(svc lifecycle.Service) *App {
 	return a
 }
 
+// WithHealth attaches a health manager to the application.
+//
+// Once all services have started, the metric server's readiness endpoint
+// reports ready only while the aggregate health is not down: register
+// service-level checks (databases, caches, downstream RPCs) on h and any
+// check reporting HealthStatusDown takes the instance out of rotation,
+// while HealthStatusDegraded keeps it serving.
+//
+// Example:
+//
+//	health := lifecycle.NewHealthManager()
+//	health.Register("postgres", pgCheck)
+//	app.New(cfg).WithHealth(health).AddRPC("rpc", svr).MustRun(ctx)
+func (a *App) WithHealth(h *lifecycle.HealthManager) *App {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.health = h
+	return a
+}
+
+// Health returns the health manager set with WithHealth, or nil.
+func (a *App) Health() *lifecycle.HealthManager {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return a.health
+}
+
 // AddHook adds a lifecycle hook.
 func (a *App) AddHook(name HookName, fn func(ctx context.Context) error) *App {
 	a.manager.AddHook(lifecycle.Hook{
@@ -133,6 +214,21 @@ func (a *App) AddHook(name HookName, fn func(ctx context.Context) error) *App {
 	return a
 }
 
+// AddHTTP adds a Hertz HTTP server to the application.
+// The server will be started and stopped as part of the application lifecycle.
+// Hertz binds the address it was created with (see NewHertzServer), so addr
+// must be empty or equal to it; AddHTTP panics on a mismatch rather than
+// serving on an address other than the one the caller asked for.
+func (a *App) AddHTTP(name string, h *server.Hertz, addr string) *App {
+	if serverAddr := h.GetOptions().Addr; addr != "" && addr != serverAddr {
+		panic(fmt.Sprintf("app: HTTP server %s: addr %q does not match the server address %q",
+			name, addr, serverAddr))
+	}
+	logx.Debugw("HTTP server added", "name", name, "addr", h.GetOptions().Addr)
+	adapter := lifecycle.NewHertzAdapter(name, h)
+	return a.AddService(adapter)
+}
+
 // AddRPC adds a Kitex RPC server to the application.
 // The server will be started and stopped as part of the application lifecycle.
 func (a *App) AddRPC(name string, server kitexserver.Server) *App {
@@ -160,7 +256,62 @@ func (a *App) OnStop(name HookName, fn func(ctx context.Context) error) *App {
 	return a
 }
 
+// OnValidate registers a function to run during Validate.
+// Use it to construct (but not start) dependencies such as clients or pools
+// so that configuration errors surface in a dry run.
+func (a *App) OnValidate(fn func(ctx context.Context) error) *App {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.validators = append(a.validators, fn)
+	return a
+}
+
+// Validate performs a dry run of the application: it validates the
+// configuration, calls Validate on every service implementing
+// lifecycle.Validator and runs the functions registered with OnValidate.
+// No service is started. All failures are returned joined together.
+//
+// Example:
+//
+//	if err := application.Validate(ctx); err != nil {
+//	    log.Fatal(err)
+//	}
+func (a *App) Validate(ctx context.Context) error {
+	a.mu.Lock()
+	services := append([]lifecycle.Service(nil), a.services...)
+	validators := append([]func(context.Context) error(nil), a.validators...)
+	a.mu.Unlock()
+
+	var errs []error
+	if err := a.config.Validate(); err != nil {
+		errs = append(errs, err)
+	}
+	for _, svc := range services {
+		if v, ok := svc.(lifecycle.Validator); ok {
+			if err := v.Validate(ctx); err != nil {
+				errs = append(errs, fmt.Errorf("service %s: %w", svc.Name(), err))
+			}
+		}
+	}
+	for _, fn := range validators {
+		if err := fn(ctx); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
 // Run starts all services and blocks until shutdown.
+//
+// Once all services have started the metric server is marked ready (see
+// WithHealth); it is marked not ready as soon as shutdown begins.
+// While it runs, the lifecycle state and component health are exported as
+// metrics (see lifecycle.MetricsCollector).
+// Shutdown starts on SIGINT, SIGTERM or when ctx is done.
+// It returns nil after a clean shutdown. If any service fails to stop the
+// returned error wraps ErrStopFailed; if a second signal arrives while
+// services are stopping, Run returns ErrForcedShutdown immediately.
+// Use ExitCode to map the result to a process exit status.
 func (a *App) Run(ctx context.Context) error {
 	logx.Infow("Starting application",
 		"name", a.config.Name,
@@ -170,47 +321,119 @@ func (a *App) Run(ctx context.Context) error {
 
 	// Initialize tracing if enabled
 	if a.config.EnableTracing && a.config.Trace.IsEnabled() {
-		shutdown, err := trace.StartAgent(a.config.Trace)
+		traceCfg := a.config.Trace
+		if traceCfg.Version == "" {
+			traceCfg.Version = a.config.Version
+		}
+		if traceCfg.Environment == "" {
+			traceCfg.Environment = a.config.Env
+		}
+		shutdown, err := trace.StartAgent(traceCfg)
 		if err != nil {
 			return fmt.Errorf("failed to start trace agent: %w", err)
 		}
+		a.mu.Lock()
 		a.traceShutdown = shutdown
 		a.tracingEnabled = true
+		a.mu.Unlock()
 		logx.Infow("Tracing enabled", "endpoint", a.config.Trace.Endpoint)
 	}
 
+	// Export lifecycle state and component health
+	collector := lifecycle.NewMetricsCollector(a.manager, a.Health(), &lifecycle.MetricsConfig{
+		Name: a.config.Name,
+	})
+	collector.Start()
+	defer collector.Stop()
+
 	// Start all services
 	if err := a.manager.Start(ctx); err != nil {
 		return fmt.Errorf("failed to start services: %w", err)
 	}
+	a.markReady()
 
-	// Wait for shutdown signal
+	// Wait for a shutdown signal or for ctx to be done
 	quit := make(chan os.Signal, 1)
 	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
-	<-quit
-
-	logx.Infow("Shutdown signal received, stopping application...")
+	defer signal.Stop(quit)
+	select {
+	case <-quit:
+		logx.Infow("Shutdown signal received, stopping application...")
+	case <-ctx.Done():
+		logx.Infow("Context done, stopping application...", "error", ctx.Err())
+	}
+	metric.SetReady(false)
+
+	// Stop all services; a second signal forces an immediate exit.
+	// The stop context keeps ctx values but not its cancellation, so that a
+	// cancelled ctx still gets an orderly, time-bounded shutdown.
+	stopped := make(chan error, 1)
+	go func() {
+		stopped <- a.manager.Stop(context.WithoutCancel(ctx))
+	}()
+
+	var stopErr error
+	select {
+	case stopErr = <-stopped:
+	case sig := <-quit:
+		logx.Warnw("Second shutdown signal received, forcing exit", "signal", sig.String())
+		return ErrForcedShutdown
+	}
+	if stopErr != nil {
+		logx.Errorw("Error stopping services", "error", stopErr)
+		stopErr = fmt.Errorf("%w: %w", ErrStopFailed, stopErr)
+	}
 
-	// Stop all services
-	if err := a.manager.Stop(ctx); err != nil {
-		logx.Errorw("Error stopping services", "error", err)
+	// Stop the metric server, pushing final metrics when configured
+	metricCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := metric.StopAgent(metricCtx); err != nil {
+		logx.Warnw("Metric server shutdown error", "error", err)
 	}
 
 	// Shutdown tracing if it was enabled
-	if a.tracingEnabled && a.traceShutdown != nil {
-		shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
-		defer cancel()
-		if traceErr := a.traceShutdown(shutdownCtx); traceErr != nil {
-			logx.Errorw("Trace shutdown error", "error", traceErr)
-		}
-	}
+	a.shutdownTracing()
 
+	if stopErr != nil {
+		return stopErr
+	}
 	logx.Infow("Application shutdown complete")
 	return nil
 }
 
+// shutdownTracing flushes and stops the trace agent started by Run, once.
+func (a *App) shutdownTracing() {
+	a.mu.Lock()
+	shutdown := a.traceShutdown
+	enabled := a.tracingEnabled
+	a.traceShutdown = nil
+	a.tracingEnabled = false
+	a.mu.Unlock()
+
+	if !enabled || shutdown == nil {
+		return
+	}
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := shutdown(shutdownCtx); err != nil {
+		logx.Errorw("Trace shutdown error", "error", err)
+	}
+}
+
+// markReady marks the metric server ready, gated by the health manager's
+// aggregate status when one is attached.
+func (a *App) markReady() {
+	if h := a.Health(); h != nil {
+		metric.SetReadinessCheck(func(ctx context.Context) bool {
+			return h.Check(ctx).Status != lifecycle.HealthStatusDown
+		})
+	}
+	metric.SetReady(true)
+}
+
 // Stop stops all services gracefully.
 func (a *App) Stop() error {
+	metric.SetReady(false)
 	return a.manager.Stop(context.Background())
 }
 
@@ -226,7 +449,12 @@ type HertzOption func(*hertzOptions)
 
 type hertzOptions struct {
 	enableTracing  bool
+	enableMetrics  bool
 	maxRequestBody int
+	network        string
+	middlewares    *middleware.DefaultConfig
+	forceTrace     *middleware.ForceTraceConfig
+	grpcWeb        *grpcweb.Bridge
 	serverOptions  []config.Option
 }
 
@@ -237,6 +465,15 @@ func WithTracing(enable bool) HertzOption {
 	}
 }
 
+// WithMetrics installs middleware.Metrics after the tracing middleware and
+// ahead of the default middleware chain, so that recovered panics are
+// counted as 500 responses.
+func WithMetrics(enable bool) HertzOption {
+	return func(o *hertzOptions) {
+		o.enableMetrics = enable
+	}
+}
+
 // WithMaxRequestBody sets the maximum request body size in bytes.
 func WithMaxRequestBody(size int) HertzOption {
 	return func(o *hertzOptions) {
@@ -244,6 +481,41 @@ func WithMaxRequestBody(size int) HertzOption {
 	}
 }
 
+// WithNetwork sets the network the server listens on: "tcp" (the default)
+// or "unix", in which case the address passed to NewHertzServer is the
+// socket path. The socket file is removed when the server stops.
+func WithNetwork(network string) HertzOption {
+	return func(o *hertzOptions) {
+		o.network = network
+	}
+}
+
+// WithDefaultMiddleware installs the middleware chain returned by
+// middleware.Default, after the tracing middleware when tracing is enabled.
+func WithDefaultMiddleware(cfg middleware.DefaultConfig) HertzOption {
+	return func(o *hertzOptions) {
+		o.middlewares = &cfg
+	}
+}
+
+// WithForceTrace installs middleware.ForceTrace ahead of the tracing
+// middleware, so that requests whose header carries one of the allowed
+// values are always traced. It has no effect unless tracing is enabled.
+func WithForceTrace(cfg middleware.ForceTraceConfig) HertzOption {
+	return func(o *hertzOptions) {
+		o.forceTrace = &cfg
+	}
+}
+
+// WithGRPCWeb mounts the routes of a gRPC-Web bridge, after all the
+// middlewares installed by the other options, so that browsers can call
+// the bridged Kitex services directly.
+func WithGRPCWeb(bridge *grpcweb.Bridge) HertzOption {
+	return func(o *hertzOptions) {
+		o.grpcWeb = bridge
+	}
+}
+
 // WithServerOptions adds additional Hertz server options.
 func WithServerOptions(opts ...config.Option) HertzOption {
 	return func(o *hertzOptions) {
@@ -252,15 +524,19 @@ func WithServerOptions(opts ...config.Option) HertzOption {
 }
 
 // NewHertzServer creates a pre-configured Hertz HTTP server with optional
-// tracing middleware. This is the recommended way to create a Hertz server
+// tracing and metrics middlewares. This is the recommended way to create a Hertz server
 // as it handles all the boilerplate configuration automatically.
 //
 // Example:
 //
-//	h := app.NewHertzServer(":8080", app.WithTracing(true))
+//	h := app.NewHertzServer(":8080", app.WithTracing(true), app.WithMetrics(true))
 //	handler.RegisterHandlers(h, svcCtx)
 //
 //	app.New(cfg).AddHTTP("http", h, ":8080").MustRun(ctx)
+//
+// To serve over a Unix socket, e.g. behind a service mesh sidecar:
+//
+//	h := app.NewHertzServer("/var/run/app/http.sock", app.WithNetwork("unix"))
 func NewHertzServer(addr string, opts ...HertzOption) *server.Hertz {
 	options := &hertzOptions{
 		maxRequestBody: 20 << 20, // 20MB default
@@ -274,21 +550,42 @@ func NewHertzServer(addr string, opts ...HertzOption) *server.Hertz {
 		server.WithHostPorts(addr),
 		server.WithMaxRequestBodySize(options.maxRequestBody),
 	}
+	if options.network != "" {
+		baseOpts = append(baseOpts, server.WithNetwork(options.network))
+	}
 
 	// Append custom server options
 	baseOpts = append(baseOpts, options.serverOptions...)
 
 	// Add tracing if enabled
+	var h *server.Hertz
 	if options.enableTracing {
 		tracer, tracerCfg := hertztracing.NewServerTracer()
 		baseOpts = append(baseOpts, tracer)
-		h := server.Default(baseOpts...)
+		h = server.Default(baseOpts...)
+		if options.forceTrace != nil {
+			h.Use(middleware.ForceTrace(*options.forceTrace))
+		}
 		h.Use(hertztracing.ServerMiddleware(tracerCfg))
-		return h
+	} else {
+		h = server.Default(baseOpts...)
+	}
+
+	// Add request metrics if enabled
+	if options.enableMetrics {
+		h.Use(middleware.Metrics())
 	}
 
-	// Create server without tracing
-	return server.Default(baseOpts...)
+	// Add the default middleware chain if requested
+	if options.middlewares != nil {
+		h.Use(middleware.Default(*options.middlewares)...)
+	}
+
+	// Mount the gRPC-Web bridge last, so that its routes get the middlewares
+	if options.grpcWeb != nil {
+		options.grpcWeb.Mount(h)
+	}
+	return h
 }
 
 // WithLogger initializes the logger with the standard logx configuration.
@@ -0,0 +1,266 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/cloudwego/hertz/pkg/app/server"
+
+	"github.com/ssgohq/goten-core/lifecycle"
+)
+
+// listenService binds addr when started and fails Validate with err.
+type listenService struct {
+	addr string
+	err  error
+	ln   net.Listener
+}
+
+func (s *listenService) Name() string { return "listener" }
+
+func (s *listenService) Start(context.Context) error {
+	ln, err := net.Listen("tcp", s.addr)
+	s.ln = ln
+	return err
+}
+
+func (s *listenService) Stop(context.Context) error { return s.ln.Close() }
+
+func (s *listenService) Validate(context.Context) error { return s.err }
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		name      string
+		cfg       Config
+		svcErr    error
+		hookErr   error
+		wantInErr []string
+	}{
+		{name: "valid", cfg: Config{Name: "orders"}},
+		{name: "missing name", cfg: Config{}, wantInErr: []string{"name is required"}},
+		{
+			name:      "negative grace period",
+			cfg:       Config{Name: "orders", GracePeriod: -time.Second},
+			wantInErr: []string{"gracePeriod"},
+		},
+		{
+			name:      "failing service and hook are joined",
+			cfg:       Config{Name: "orders"},
+			svcErr:    errors.New("bad dsn"),
+			hookErr:   errors.New("bad client"),
+			wantInErr: []string{"service listener: bad dsn", "bad client"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ln, err := net.Listen("tcp", "127.0.0.1:0")
+			if err != nil {
+				t.Fatal(err)
+			}
+			addr := ln.Addr().String()
+			_ = ln.Close()
+
+			svc := &listenService{addr: addr, err: tt.svcErr}
+			a := New(tt.cfg).AddService(svc)
+			a.OnValidate(func(context.Context) error { return tt.hookErr })
+
+			err = a.Validate(context.Background())
+			if len(tt.wantInErr) == 0 && err != nil {
+				t.Fatalf("Validate() = %v, want nil", err)
+			}
+			for _, want := range tt.wantInErr {
+				if err == nil || !strings.Contains(err.Error(), want) {
+					t.Errorf("Validate() = %v, want it to contain %q", err, want)
+				}
+			}
+
+			// The dry run starts nothing, so the port is still free
+			if svc.ln != nil {
+				t.Fatal("Validate started the service")
+			}
+			ln, err = net.Listen("tcp", addr)
+			if err != nil {
+				t.Fatalf("port bound after Validate: %v", err)
+			}
+			_ = ln.Close()
+		})
+	}
+}
+
+// stopService fails to stop with err and closes started once it has started.
+type stopService struct {
+	err     error
+	started chan struct{}
+}
+
+func (s *stopService) Name() string { return "stopper" }
+
+func (s *stopService) Start(context.Context) error {
+	close(s.started)
+	return nil
+}
+
+func (s *stopService) Stop(context.Context) error { return s.err }
+
+func TestRunStopResult(t *testing.T) {
+	tests := []struct {
+		name     string
+		stopErr  error
+		wantErr  error
+		wantCode int
+	}{
+		{name: "clean shutdown"},
+		{name: "service fails to stop", stopErr: errors.New("flush failed"), wantErr: ErrStopFailed, wantCode: 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc := &stopService{err: tt.stopErr, started: make(chan struct{})}
+			a := New(Config{Name: "stop", StopTimeout: time.Second, GracePeriod: time.Millisecond}).AddService(svc)
+
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+			go func() {
+				<-svc.started
+				cancel()
+			}()
+
+			err := a.Run(ctx)
+			if tt.wantErr == nil && err != nil {
+				t.Fatalf("Run() = %v, want nil", err)
+			}
+			if tt.wantErr != nil && !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Run() = %v, want %v", err, tt.wantErr)
+			}
+			if tt.stopErr != nil && !errors.Is(err, tt.stopErr) {
+				t.Errorf("Run() = %v, want it to wrap the stop error", err)
+			}
+			if got := ExitCode(err); got != tt.wantCode {
+				t.Errorf("ExitCode() = %d, want %d", got, tt.wantCode)
+			}
+		})
+	}
+}
+
+func TestExitCode(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want int
+	}{
+		{name: "clean", err: nil, want: 0},
+		{name: "stop failed", err: fmt.Errorf("%w: %w", ErrStopFailed, errors.New("db")), want: 1},
+		{name: "forced", err: ErrForcedShutdown, want: 2},
+		{name: "wrapped forced", err: fmt.Errorf("run: %w", ErrForcedShutdown), want: 2},
+		{name: "start failure", err: errors.New("failed to start services"), want: 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ExitCode(tt.err); got != tt.want {
+				t.Errorf("ExitCode(%v) = %d, want %d", tt.err, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAddHTTPAddr(t *testing.T) {
+	tests := []struct {
+		name      string
+		addr      string
+		wantPanic bool
+	}{
+		{name: "matching", addr: "127.0.0.1:18080"},
+		{name: "empty", addr: ""},
+		{name: "mismatch", addr: ":9090", wantPanic: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := server.New(server.WithHostPorts("127.0.0.1:18080"))
+			defer func() {
+				if r := recover(); (r != nil) != tt.wantPanic {
+					t.Errorf("panic = %v, want panic %v", r, tt.wantPanic)
+				}
+			}()
+			New(Config{Name: "test"}).AddHTTP("http", h, tt.addr)
+		})
+	}
+}
+
+// countingService counts its starts and stops.
+type countingService struct {
+	name          string
+	starts, stops *atomic.Int32
+}
+
+func (s countingService) Name() string { return s.name }
+
+func (s countingService) Start(context.Context) error {
+	s.starts.Add(1)
+	return nil
+}
+
+func (s countingService) Stop(context.Context) error {
+	s.stops.Add(1)
+	return nil
+}
+
+// TestRunConcurrentSetup exercises AddService and the other setup methods
+// while Run starts and stops services; run it with -race.
+func TestRunConcurrentSetup(t *testing.T) {
+	var starts, stops atomic.Int32
+	a := New(Config{Name: "race", StopTimeout: 5 * time.Second, GracePeriod: time.Second})
+	a.AddService(countingService{name: "initial", starts: &starts, stops: &stops})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	running := make(chan struct{})
+	a.OnStart("after_start", func(context.Context) error {
+		close(running)
+		return nil
+	})
+
+	const adders, perAdder = 4, 25
+	var wg sync.WaitGroup
+	for i := 0; i < adders; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			for j := 0; j < perAdder; j++ {
+				a.AddService(countingService{name: fmt.Sprintf("svc-%d-%d", i, j), starts: &starts, stops: &stops})
+				a.OnStop("before_stop", func(context.Context) error { return nil })
+				a.OnValidate(func(context.Context) error { return nil })
+				a.WithHealth(lifecycle.NewHealthManager())
+				_ = a.Health()
+				_ = a.Validate(ctx)
+			}
+		}(i)
+	}
+
+	done := make(chan error, 1)
+	go func() { done <- a.Run(ctx) }()
+
+	<-running
+	wg.Wait()
+	cancel()
+	if err := <-done; err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+
+	// Every service is stopped, including those added after startup
+	total := int32(1 + adders*perAdder)
+	if got := stops.Load(); got != total {
+		t.Errorf("stopped = %d, want %d", got, total)
+	}
+	if got := starts.Load(); got < 1 || got > total {
+		t.Errorf("started = %d, want between 1 and %d", got, total)
+	}
+}
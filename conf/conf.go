@@ -0,0 +1,158 @@
+// Package conf loads YAML configuration files into config structs.
+// Files may be split into a base and environment overlays that are merged
+// in order before defaults are applied. Environment variable references in
+// string fields, such as "${DB_PASSWORD}" in a DSN, are expanded.
+package conf
+
+import (
+	"fmt"
+	"os"
+
+	"gopkg.in/yaml.v3"
+)
+
+// defaulter is implemented by configs with a SetDefaults method.
+type defaulter interface {
+	SetDefaults()
+}
+
+// validator is implemented by configs with a Validate method.
+type validator interface {
+	Validate() error
+}
+
+// Option configures how a config is loaded.
+type Option func(*options)
+
+type options struct {
+	noEnv     bool
+	strictEnv bool
+}
+
+// WithStrictEnv makes loading fail when a string field references an
+// environment variable that is not set, instead of keeping the reference.
+func WithStrictEnv() Option {
+	return func(o *options) {
+		o.strictEnv = true
+	}
+}
+
+// WithoutEnvExpansion keeps string fields as written, with no environment
+// variable expansion.
+func WithoutEnvExpansion() Option {
+	return func(o *options) {
+		o.noEnv = true
+	}
+}
+
+// Load reads the YAML file at path into v, expands environment variables in
+// its string fields, then calls v.SetDefaults and v.Validate when v
+// implements them.
+//
+// Example:
+//
+//	var c config.Config
+//	if err := conf.Load("etc/user.yaml", &c, conf.WithStrictEnv()); err != nil {
+//	    log.Fatal(err)
+//	}
+func Load(path string, v any, opts ...Option) error {
+	return LoadFilesWithOptions(v, []string{path}, opts...)
+}
+
+// LoadFiles reads the YAML files at paths, merges them in order into v,
+// expands environment variables in its string fields, then calls
+// v.SetDefaults and v.Validate when v implements them.
+//
+// Later files override earlier ones. Mappings are merged key by key at every
+// level, so an overlay only needs the keys it changes; any other value,
+// including a list, replaces the earlier one as a whole.
+//
+// Example:
+//
+//	err := conf.LoadFiles(&c, "etc/base.yaml", "etc/production.yaml")
+func LoadFiles(v any, paths ...string) error {
+	return LoadFilesWithOptions(v, paths)
+}
+
+// LoadFilesWithOptions is like LoadFiles, with options.
+//
+// String fields, at any depth, may reference environment variables as
+// ${VAR} or $VAR, and "$$" stands for a literal "$". A reference to an
+// unset variable is kept as written, unless WithStrictEnv is given.
+//
+// Example:
+//
+//	err := conf.LoadFilesWithOptions(&c, []string{"etc/base.yaml", "etc/production.yaml"},
+//	    conf.WithStrictEnv())
+func LoadFilesWithOptions(v any, paths []string, opts ...Option) error {
+	var o options
+	for _, opt := range opts {
+		opt(&o)
+	}
+	if len(paths) == 0 {
+		return fmt.Errorf("conf: no config files given")
+	}
+
+	var merged map[string]any
+	for _, path := range paths {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return fmt.Errorf("conf: read %s: %w", path, err)
+		}
+		var doc map[string]any
+		if err := yaml.Unmarshal(data, &doc); err != nil {
+			return fmt.Errorf("conf: parse %s: %w", path, err)
+		}
+		merged = mergeMaps(merged, doc)
+	}
+
+	// Round-trip the merged document so that v's yaml tags apply
+	data, err := yaml.Marshal(merged)
+	if err != nil {
+		return fmt.Errorf("conf: encode merged config: %w", err)
+	}
+	if err := yaml.Unmarshal(data, v); err != nil {
+		return fmt.Errorf("conf: decode merged config: %w", err)
+	}
+
+	// Expand environment variables before defaults and validation
+	if !o.noEnv {
+		if err := expandEnv(v, o.strictEnv); err != nil {
+			return err
+		}
+	}
+
+	if d, ok := v.(defaulter); ok {
+		d.SetDefaults()
+	}
+	if val, ok := v.(validator); ok {
+		if err := val.Validate(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// MustLoad is like Load but panics on error.
+func MustLoad(path string, v any, opts ...Option) {
+	if err := Load(path, v, opts...); err != nil {
+		panic(err)
+	}
+}
+
+// mergeMaps deep-merges src into dst and returns dst.
+func mergeMaps(dst, src map[string]any) map[string]any {
+	if dst == nil {
+		dst = make(map[string]any, len(src))
+	}
+	for key, value := range src {
+		srcMap, srcIsMap := value.(map[string]any)
+		dstMap, dstIsMap := dst[key].(map[string]any)
+		if srcIsMap && dstIsMap {
+			dst[key] = mergeMaps(dstMap, srcMap)
+			continue
+		}
+		dst[key] = value
+	}
+	return dst
+}
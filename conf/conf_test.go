@@ -0,0 +1,136 @@
+package conf
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+type mergeTestServer struct {
+	Host    string `yaml:"host"`
+	Port    int    `yaml:"port"`
+	Timeout string `yaml:"timeout"`
+}
+
+type mergeTestConfig struct {
+	Name   string            `yaml:"name"`
+	Server mergeTestServer   `yaml:"server"`
+	Hosts  []string          `yaml:"hosts"`
+	Labels map[string]string `yaml:"labels"`
+}
+
+func (c *mergeTestConfig) SetDefaults() {
+	if c.Server.Timeout == "" {
+		c.Server.Timeout = "5s"
+	}
+}
+
+func TestLoadFilesMerge(t *testing.T) {
+	const base = "name: orders\n" +
+		"server:\n  host: 0.0.0.0\n  port: 8080\n" +
+		"hosts: [a, b]\n" +
+		"labels:\n  team: core\n  tier: backend\n"
+
+	tests := []struct {
+		name    string
+		overlay string
+		want    mergeTestConfig
+	}{
+		{
+			name:    "empty overlay",
+			overlay: "",
+			want: mergeTestConfig{
+				Name:   "orders",
+				Server: mergeTestServer{Host: "0.0.0.0", Port: 8080, Timeout: "5s"},
+				Hosts:  []string{"a", "b"},
+				Labels: map[string]string{"team": "core", "tier": "backend"},
+			},
+		},
+		{
+			name:    "overlay wins",
+			overlay: "name: orders-prod\n",
+			want: mergeTestConfig{
+				Name:   "orders-prod",
+				Server: mergeTestServer{Host: "0.0.0.0", Port: 8080, Timeout: "5s"},
+				Hosts:  []string{"a", "b"},
+				Labels: map[string]string{"team": "core", "tier": "backend"},
+			},
+		},
+		{
+			name:    "nested keys deep merge",
+			overlay: "server:\n  port: 9090\n  timeout: 30s\nlabels:\n  tier: edge\n  region: eu\n",
+			want: mergeTestConfig{
+				Name:   "orders",
+				Server: mergeTestServer{Host: "0.0.0.0", Port: 9090, Timeout: "30s"},
+				Hosts:  []string{"a", "b"},
+				Labels: map[string]string{"team": "core", "tier": "edge", "region": "eu"},
+			},
+		},
+		{
+			name:    "lists are replaced",
+			overlay: "hosts: [c]\n",
+			want: mergeTestConfig{
+				Name:   "orders",
+				Server: mergeTestServer{Host: "0.0.0.0", Port: 8080, Timeout: "5s"},
+				Hosts:  []string{"c"},
+				Labels: map[string]string{"team": "core", "tier": "backend"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			basePath := filepath.Join(dir, "base.yaml")
+			overlayPath := filepath.Join(dir, "production.yaml")
+			if err := os.WriteFile(basePath, []byte(base), 0o600); err != nil {
+				t.Fatal(err)
+			}
+			if err := os.WriteFile(overlayPath, []byte(tt.overlay), 0o600); err != nil {
+				t.Fatal(err)
+			}
+
+			var got mergeTestConfig
+			if err := LoadFiles(&got, basePath, overlayPath); err != nil {
+				t.Fatalf("LoadFiles() error = %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("LoadFiles() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLoadFilesErrors(t *testing.T) {
+	dir := t.TempDir()
+	valid := filepath.Join(dir, "valid.yaml")
+	invalid := filepath.Join(dir, "invalid.yaml")
+	if err := os.WriteFile(valid, []byte("name: orders\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(invalid, []byte("name: [orders\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name    string
+		paths   []string
+		wantErr string
+	}{
+		{name: "no files", wantErr: "conf: no config files given"},
+		{name: "missing overlay", paths: []string{valid, filepath.Join(dir, "missing.yaml")}, wantErr: "conf: read"},
+		{name: "invalid overlay", paths: []string{valid, invalid}, wantErr: "conf: parse " + invalid},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got mergeTestConfig
+			err := LoadFiles(&got, tt.paths...)
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("LoadFiles() error = %v, want it to contain %q", err, tt.wantErr)
+			}
+		})
+	}
+}
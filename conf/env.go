@@ -0,0 +1,133 @@
+package conf
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"reflect"
+	"strings"
+)
+
+// envExpander expands environment variable references in strings and
+// records the unset ones.
+type envExpander struct {
+	strict bool
+	errs   []error
+}
+
+// expandEnv replaces ${VAR} and $VAR references in the string fields of v,
+// at any depth of structs, pointers, slices, arrays and maps. "$$" stands
+// for a literal "$". References to unset variables are kept as written, or
+// reported as errors in strict mode.
+func expandEnv(v any, strict bool) error {
+	e := &envExpander{strict: strict}
+	e.walk(reflect.ValueOf(v), "")
+	return errors.Join(e.errs...)
+}
+
+// walk expands the strings reachable from v, which is named path in errors.
+func (e *envExpander) walk(v reflect.Value, path string) {
+	switch v.Kind() {
+	case reflect.Pointer:
+		if !v.IsNil() {
+			e.walk(v.Elem(), path)
+		}
+	case reflect.Struct:
+		t := v.Type()
+		for i := 0; i < v.NumField(); i++ {
+			if f := t.Field(i); f.IsExported() {
+				e.walk(v.Field(i), joinPath(path, f.Name))
+			}
+		}
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < v.Len(); i++ {
+			e.walk(v.Index(i), fmt.Sprintf("%s[%d]", path, i))
+		}
+	case reflect.Map:
+		// Map values are not addressable, so each is expanded in a copy
+		iter := v.MapRange()
+		for iter.Next() {
+			elem := reflect.New(v.Type().Elem()).Elem()
+			elem.Set(iter.Value())
+			e.walk(elem, fmt.Sprintf("%s[%v]", path, iter.Key()))
+			v.SetMapIndex(iter.Key(), elem)
+		}
+	case reflect.String:
+		if v.CanSet() && strings.Contains(v.String(), "$") {
+			v.SetString(e.expand(v.String(), path))
+		}
+	}
+}
+
+// expand returns s with its variable references replaced.
+func (e *envExpander) expand(s, path string) string {
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		if s[i] != '$' || i+1 == len(s) {
+			b.WriteByte(s[i])
+			continue
+		}
+
+		// "$$" is a literal "$"
+		if s[i+1] == '$' {
+			b.WriteByte('$')
+			i++
+			continue
+		}
+
+		// ${VAR} or $VAR; anything else is kept as written
+		var name, ref string
+		if s[i+1] == '{' {
+			end := strings.IndexByte(s[i+2:], '}')
+			if end < 0 {
+				b.WriteByte(s[i])
+				continue
+			}
+			name = s[i+2 : i+2+end]
+			ref = s[i : i+3+end]
+		} else {
+			n := envNameLen(s[i+1:])
+			name = s[i+1 : i+1+n]
+			ref = s[i : i+1+n]
+		}
+		if !isEnvName(name) {
+			b.WriteByte(s[i])
+			continue
+		}
+
+		if value, ok := os.LookupEnv(name); ok {
+			b.WriteString(value)
+		} else {
+			if e.strict {
+				e.errs = append(e.errs, fmt.Errorf("conf: %s: environment variable %s is not set", path, name))
+			}
+			b.WriteString(ref)
+		}
+		i += len(ref) - 1
+	}
+	return b.String()
+}
+
+// envNameLen returns the length of the variable name at the start of s.
+func envNameLen(s string) int {
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		if c == '_' || ('a' <= c && c <= 'z') || ('A' <= c && c <= 'Z') || (i > 0 && '0' <= c && c <= '9') {
+			continue
+		}
+		return i
+	}
+	return len(s)
+}
+
+// isEnvName reports whether s is a valid variable name.
+func isEnvName(s string) bool {
+	return s != "" && envNameLen(s) == len(s)
+}
+
+func joinPath(path, name string) string {
+	if path == "" {
+		return name
+	}
+	return path + "." + name
+}
@@ -0,0 +1,277 @@
+// Package grpcweb bridges gRPC-Web requests from browsers to Kitex services,
+// so that they can be served by a Hertz server without a separate proxy.
+//
+// Only unary calls are supported. Browsers also need CORS to expose the
+// grpc-status and grpc-message headers when the page is served from another
+// origin; see middleware.CORSConfig.ExposeHeaders.
+package grpcweb
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/binary"
+	stderrors "errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/cloudwego/hertz/pkg/app"
+	"github.com/cloudwego/hertz/pkg/route"
+	"github.com/cloudwego/kitex/client/genericclient"
+	"github.com/cloudwego/kitex/pkg/kerrors"
+
+	"github.com/ssgohq/goten-core/logx"
+	"github.com/ssgohq/goten-core/srpc/errors"
+)
+
+const (
+	// frameHeaderLen is the length of a gRPC frame header: a flags byte
+	// and a big-endian message length.
+	frameHeaderLen = 5
+	// frameCompressed flags a compressed message.
+	frameCompressed = 0x01
+	// frameTrailer flags the frame carrying the trailers.
+	frameTrailer = 0x80
+
+	contentTypeWeb     = "application/grpc-web"
+	contentTypeWebText = "application/grpc-web-text"
+)
+
+// gRPC status codes used by the bridge itself.
+const (
+	statusUnknown           = 2
+	statusInvalidArgument   = 3
+	statusDeadlineExceeded  = 4
+	statusResourceExhausted = 8
+	statusUnimplemented     = 12
+)
+
+// Invoker calls method of a service with a serialized protobuf request and
+// returns the serialized response. Errors are reported to the browser with
+// the gRPC code of their *errors.Error code; see errors.GRPCCode.
+type Invoker func(ctx context.Context, method string, req []byte) ([]byte, error)
+
+// KitexInvoker returns an Invoker calling a Kitex generic client created
+// with generic.BinaryPbGeneric, which forwards the serialized messages as
+// they are.
+//
+// Example:
+//
+//	g := generic.BinaryPbGeneric("UserService", "user")
+//	cli, err := genericclient.NewClient("user-rpc", g, srpc.NewClientBuilder(&c.UserRpc).Build()...)
+//	if err != nil {
+//	    return err
+//	}
+//	bridge := grpcweb.NewBridge().Register("user.UserService", grpcweb.KitexInvoker(cli))
+func KitexInvoker(cli genericclient.Client) Invoker {
+	return func(ctx context.Context, method string, req []byte) ([]byte, error) {
+		resp, err := cli.GenericCall(ctx, method, req)
+		if err != nil {
+			return nil, err
+		}
+		data, ok := resp.([]byte)
+		if !ok {
+			return nil, fmt.Errorf("grpcweb: unexpected response type %T, expected a binary protobuf generic client", resp)
+		}
+		return data, nil
+	}
+}
+
+// Bridge translates unary gRPC-Web calls to registered services into
+// Invoker calls.
+type Bridge struct {
+	services       map[string]Invoker
+	order          []string
+	maxMessageSize int
+}
+
+// BridgeOption configures a Bridge.
+type BridgeOption func(*Bridge)
+
+// WithMaxMessageSize sets the largest request message accepted, in bytes.
+// Default: 4MB, as in gRPC
+func WithMaxMessageSize(size int) BridgeOption {
+	return func(b *Bridge) {
+		b.maxMessageSize = size
+	}
+}
+
+// NewBridge creates a Bridge with no services.
+func NewBridge(opts ...BridgeOption) *Bridge {
+	b := &Bridge{
+		services:       make(map[string]Invoker),
+		maxMessageSize: 4 << 20,
+	}
+	for _, opt := range opts {
+		opt(b)
+	}
+	return b
+}
+
+// Register routes calls to service, the fully qualified protobuf service
+// name such as "user.UserService", to inv. It returns b for chaining.
+func (b *Bridge) Register(service string, inv Invoker) *Bridge {
+	if _, ok := b.services[service]; !ok {
+		b.order = append(b.order, service)
+	}
+	b.services[service] = inv
+	return b
+}
+
+// Mount registers a POST route for each service, at the gRPC path
+// "/<service>/<method>".
+//
+// Example:
+//
+//	h := app.NewHertzServer(":8080", app.WithGRPCWeb(bridge))
+//
+// or, on a server built by hand:
+//
+//	bridge.Mount(h)
+func (b *Bridge) Mount(r route.IRoutes) {
+	for _, service := range b.order {
+		r.POST("/"+service+"/:method", b.handler(b.services[service]))
+	}
+}
+
+// handler returns the Hertz handler calling inv.
+func (b *Bridge) handler(inv Invoker) app.HandlerFunc {
+	return func(ctx context.Context, c *app.RequestContext) {
+		contentType := string(c.Request.Header.ContentType())
+		if !strings.HasPrefix(contentType, contentTypeWeb) {
+			c.AbortWithMsg("unsupported content type "+contentType, 415)
+			return
+		}
+		text := strings.HasPrefix(contentType, contentTypeWebText)
+
+		// Response framing follows the request's encoding
+		if text {
+			c.Response.Header.SetContentType(contentTypeWebText + "+proto")
+		} else {
+			c.Response.Header.SetContentType(contentTypeWeb + "+proto")
+		}
+		c.SetStatusCode(200)
+
+		resp, code, msg := b.call(ctx, c, inv, text)
+		body := make([]byte, 0, len(resp)+2*frameHeaderLen+64)
+		if code == 0 {
+			body = appendFrame(body, 0, resp)
+		}
+		body = appendFrame(body, frameTrailer, []byte(
+			"grpc-status:"+strconv.FormatUint(uint64(code), 10)+"\r\n"+
+				"grpc-message:"+encodeGRPCMessage(msg)+"\r\n",
+		))
+		if text {
+			body = []byte(base64.StdEncoding.EncodeToString(body))
+		}
+		c.Response.SetBody(body)
+	}
+}
+
+// call decodes the request message, calls inv and returns the response
+// message or a gRPC status.
+func (b *Bridge) call(ctx context.Context, c *app.RequestContext, inv Invoker, text bool) ([]byte, uint32, string) {
+	body := c.Request.Body()
+	if text {
+		decoded, err := base64.StdEncoding.DecodeString(string(body))
+		if err != nil {
+			return nil, statusInvalidArgument, "invalid base64 request body"
+		}
+		body = decoded
+	}
+
+	if len(body) < frameHeaderLen || body[0]&frameTrailer != 0 {
+		return nil, statusInvalidArgument, "request must be a single data frame"
+	}
+	if body[0]&frameCompressed != 0 {
+		return nil, statusUnimplemented, "compressed messages are not supported"
+	}
+	size := binary.BigEndian.Uint32(body[1:frameHeaderLen])
+	if uint64(size) > uint64(b.maxMessageSize) {
+		return nil, statusResourceExhausted, fmt.Sprintf("request message larger than %d bytes", b.maxMessageSize)
+	}
+	if int(size) != len(body)-frameHeaderLen {
+		return nil, statusInvalidArgument, "request must be a single data frame"
+	}
+
+	if timeout, ok := parseTimeout(string(c.Request.Header.Peek("grpc-timeout"))); ok {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+
+	method := c.Param("method")
+	resp, err := inv(ctx, method, body[frameHeaderLen:])
+	if err == nil {
+		return resp, 0, ""
+	}
+
+	if e := errors.FromError(err); e != nil {
+		return nil, e.GRPCCode(), e.Message
+	}
+	if kerrors.IsTimeoutError(err) || stderrors.Is(err, context.DeadlineExceeded) {
+		return nil, statusDeadlineExceeded, "deadline exceeded"
+	}
+	// Other errors may describe internals, so they are logged, not returned
+	logx.Ctx(ctx).Warnw("gRPC-Web call failed",
+		"path", string(c.Request.URI().Path()),
+		"error", err,
+	)
+	return nil, statusUnknown, "internal error"
+}
+
+// appendFrame appends a gRPC frame holding msg to dst.
+func appendFrame(dst []byte, flags byte, msg []byte) []byte {
+	dst = append(dst, flags)
+	dst = binary.BigEndian.AppendUint32(dst, uint32(len(msg)))
+	return append(dst, msg...)
+}
+
+// parseTimeout parses a grpc-timeout header value, such as "500m": at most
+// eight digits and a unit of H, M, S, m, u or n.
+func parseTimeout(s string) (time.Duration, bool) {
+	if len(s) < 2 || len(s) > 9 {
+		return 0, false
+	}
+	n, err := strconv.ParseInt(s[:len(s)-1], 10, 64)
+	if err != nil || n < 0 {
+		return 0, false
+	}
+	var unit time.Duration
+	switch s[len(s)-1] {
+	case 'H':
+		unit = time.Hour
+	case 'M':
+		unit = time.Minute
+	case 'S':
+		unit = time.Second
+	case 'm':
+		unit = time.Millisecond
+	case 'u':
+		unit = time.Microsecond
+	case 'n':
+		unit = time.Nanosecond
+	default:
+		return 0, false
+	}
+	if n > math.MaxInt64/int64(unit) {
+		return 0, false
+	}
+	return time.Duration(n) * unit, true
+}
+
+// encodeGRPCMessage percent-encodes a grpc-message value: bytes outside
+// printable ASCII, and "%" itself.
+func encodeGRPCMessage(msg string) string {
+	var b strings.Builder
+	for i := 0; i < len(msg); i++ {
+		if c := msg[i]; c >= 0x20 && c <= 0x7e && c != '%' {
+			b.WriteByte(c)
+		} else {
+			fmt.Fprintf(&b, "%%%02X", c)
+		}
+	}
+	return b.String()
+}
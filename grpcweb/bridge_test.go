@@ -0,0 +1,280 @@
+package grpcweb
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/binary"
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/cloudwego/hertz/pkg/app/server"
+	"github.com/cloudwego/hertz/pkg/common/ut"
+
+	rpcerrors "github.com/ssgohq/goten-core/srpc/errors"
+)
+
+// downstreamCall is a call received by the fake invoker.
+type downstreamCall struct {
+	method      string
+	req         []byte
+	hasDeadline bool
+	timeLeft    time.Duration
+}
+
+// frame returns msg in a gRPC data frame with the given flags.
+func frame(flags byte, msg []byte) []byte {
+	return appendFrame(nil, flags, msg)
+}
+
+// parseFrames splits a gRPC-Web response body into its data message and
+// trailers.
+func parseFrames(t *testing.T, body []byte) ([]byte, map[string]string) {
+	t.Helper()
+	var data []byte
+	trailers := make(map[string]string)
+	for len(body) > 0 {
+		if len(body) < frameHeaderLen {
+			t.Fatalf("truncated frame header: %q", body)
+		}
+		flags, size := body[0], binary.BigEndian.Uint32(body[1:frameHeaderLen])
+		msg := body[frameHeaderLen : frameHeaderLen+int(size)]
+		body = body[frameHeaderLen+int(size):]
+		if flags&frameTrailer == 0 {
+			data = msg
+			continue
+		}
+		for _, line := range strings.Split(strings.TrimSpace(string(msg)), "\r\n") {
+			key, value, _ := strings.Cut(line, ":")
+			trailers[key] = value
+		}
+	}
+	return data, trailers
+}
+
+func TestBridge(t *testing.T) {
+	tests := []struct {
+		name         string
+		contentType  string
+		headers      []ut.Header
+		body         []byte
+		respond      func(ctx context.Context) ([]byte, error)
+		wantHTTP     int
+		wantCall     bool
+		wantStatus   string
+		wantMessage  string
+		wantResponse string
+		wantDeadline time.Duration
+	}{
+		{
+			name:         "binary request",
+			contentType:  "application/grpc-web+proto",
+			body:         frame(0, []byte("request")),
+			wantHTTP:     http.StatusOK,
+			wantCall:     true,
+			wantStatus:   "0",
+			wantResponse: "response",
+		},
+		{
+			name:         "text request",
+			contentType:  "application/grpc-web-text",
+			body:         []byte(base64.StdEncoding.EncodeToString(frame(0, []byte("request")))),
+			wantHTTP:     http.StatusOK,
+			wantCall:     true,
+			wantStatus:   "0",
+			wantResponse: "response",
+		},
+		{
+			name:         "grpc-timeout",
+			contentType:  "application/grpc-web+proto",
+			headers:      []ut.Header{{Key: "grpc-timeout", Value: "500m"}},
+			body:         frame(0, []byte("request")),
+			wantHTTP:     http.StatusOK,
+			wantCall:     true,
+			wantStatus:   "0",
+			wantResponse: "response",
+			wantDeadline: 500 * time.Millisecond,
+		},
+		{
+			name:        "biz error",
+			contentType: "application/grpc-web+proto",
+			body:        frame(0, []byte("request")),
+			respond: func(context.Context) ([]byte, error) {
+				return nil, rpcerrors.ToKitexError(rpcerrors.NotFound("user 100% gone"))
+			},
+			wantHTTP:    http.StatusOK,
+			wantCall:    true,
+			wantStatus:  "5",
+			wantMessage: "user 100%25 gone",
+		},
+		{
+			name:        "deadline exceeded",
+			contentType: "application/grpc-web+proto",
+			body:        frame(0, []byte("request")),
+			respond: func(context.Context) ([]byte, error) {
+				return nil, context.DeadlineExceeded
+			},
+			wantHTTP:    http.StatusOK,
+			wantCall:    true,
+			wantStatus:  "4",
+			wantMessage: "deadline exceeded",
+		},
+		{
+			name:        "internal error hidden",
+			contentType: "application/grpc-web+proto",
+			body:        frame(0, []byte("request")),
+			respond: func(context.Context) ([]byte, error) {
+				return nil, errors.New("dial tcp 10.0.0.1:8888: connection refused")
+			},
+			wantHTTP:    http.StatusOK,
+			wantCall:    true,
+			wantStatus:  "2",
+			wantMessage: "internal error",
+		},
+		{
+			name:        "compressed message",
+			contentType: "application/grpc-web+proto",
+			body:        frame(frameCompressed, []byte("request")),
+			wantHTTP:    http.StatusOK,
+			wantStatus:  "12",
+			wantMessage: "compressed messages are not supported",
+		},
+		{
+			name:        "message too large",
+			contentType: "application/grpc-web+proto",
+			body:        frame(0, bytes.Repeat([]byte("x"), 65)),
+			wantHTTP:    http.StatusOK,
+			wantStatus:  "8",
+			wantMessage: "request message larger than 64 bytes",
+		},
+		{
+			name:        "truncated frame",
+			contentType: "application/grpc-web+proto",
+			body:        frame(0, []byte("request"))[:8],
+			wantHTTP:    http.StatusOK,
+			wantStatus:  "3",
+			wantMessage: "request must be a single data frame",
+		},
+		{
+			name:        "invalid base64",
+			contentType: "application/grpc-web-text",
+			body:        []byte("not base64!"),
+			wantHTTP:    http.StatusOK,
+			wantStatus:  "3",
+			wantMessage: "invalid base64 request body",
+		},
+		{
+			name:        "not gRPC-Web",
+			contentType: "application/json",
+			body:        []byte(`{}`),
+			wantHTTP:    http.StatusUnsupportedMediaType,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var calls []downstreamCall
+			inv := func(ctx context.Context, method string, req []byte) ([]byte, error) {
+				call := downstreamCall{method: method, req: req}
+				if deadline, ok := ctx.Deadline(); ok {
+					call.hasDeadline, call.timeLeft = true, time.Until(deadline)
+				}
+				calls = append(calls, call)
+				if tt.respond != nil {
+					return tt.respond(ctx)
+				}
+				return []byte("response"), nil
+			}
+
+			h := server.New()
+			NewBridge(WithMaxMessageSize(64)).Register("user.UserService", inv).Mount(h)
+
+			headers := append([]ut.Header{{Key: "Content-Type", Value: tt.contentType}}, tt.headers...)
+			w := ut.PerformRequest(h.Engine, http.MethodPost, "/user.UserService/GetUser",
+				&ut.Body{Body: bytes.NewReader(tt.body), Len: len(tt.body)}, headers...)
+			resp := w.Result()
+			if got := resp.StatusCode(); got != tt.wantHTTP {
+				t.Fatalf("HTTP status = %d, want %d", got, tt.wantHTTP)
+			}
+			if tt.wantHTTP != http.StatusOK {
+				if len(calls) != 0 {
+					t.Errorf("downstream called %d times, want 0", len(calls))
+				}
+				return
+			}
+
+			if !tt.wantCall {
+				if len(calls) != 0 {
+					t.Errorf("downstream called %d times, want 0", len(calls))
+				}
+			} else {
+				if len(calls) != 1 {
+					t.Fatalf("downstream called %d times, want 1", len(calls))
+				}
+				if calls[0].method != "GetUser" || string(calls[0].req) != "request" {
+					t.Errorf("downstream call = %s(%q), want GetUser(\"request\")", calls[0].method, calls[0].req)
+				}
+				if tt.wantDeadline > 0 && (!calls[0].hasDeadline || calls[0].timeLeft > tt.wantDeadline) {
+					t.Errorf("downstream deadline in %v (set %v), want at most %v",
+						calls[0].timeLeft, calls[0].hasDeadline, tt.wantDeadline)
+				}
+			}
+
+			body := resp.Body()
+			wantType := "application/grpc-web+proto"
+			if strings.HasPrefix(tt.contentType, "application/grpc-web-text") {
+				wantType = "application/grpc-web-text+proto"
+				decoded, err := base64.StdEncoding.DecodeString(string(body))
+				if err != nil {
+					t.Fatalf("response is not base64: %v", err)
+				}
+				body = decoded
+			}
+			if got := string(resp.Header.ContentType()); got != wantType {
+				t.Errorf("Content-Type = %q, want %q", got, wantType)
+			}
+
+			data, trailers := parseFrames(t, body)
+			if trailers["grpc-status"] != tt.wantStatus || trailers["grpc-message"] != tt.wantMessage {
+				t.Errorf("trailers = %v, want grpc-status %s and grpc-message %q",
+					trailers, tt.wantStatus, tt.wantMessage)
+			}
+			if string(data) != tt.wantResponse {
+				t.Errorf("response message = %q, want %q", data, tt.wantResponse)
+			}
+		})
+	}
+}
+
+func TestParseTimeout(t *testing.T) {
+	tests := []struct {
+		in     string
+		want   time.Duration
+		wantOK bool
+	}{
+		{in: "1H", want: time.Hour, wantOK: true},
+		{in: "2M", want: 2 * time.Minute, wantOK: true},
+		{in: "3S", want: 3 * time.Second, wantOK: true},
+		{in: "500m", want: 500 * time.Millisecond, wantOK: true},
+		{in: "250u", want: 250 * time.Microsecond, wantOK: true},
+		{in: "99999999n", want: 99999999 * time.Nanosecond, wantOK: true},
+		{in: ""},
+		{in: "5"},
+		{in: "5s"},
+		{in: "-1S"},
+		{in: "123456789S"},
+		{in: "99999999H"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.in, func(t *testing.T) {
+			got, ok := parseTimeout(tt.in)
+			if got != tt.want || ok != tt.wantOK {
+				t.Errorf("parseTimeout(%q) = %v, %v, want %v, %v", tt.in, got, ok, tt.want, tt.wantOK)
+			}
+		})
+	}
+}
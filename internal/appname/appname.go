@@ -0,0 +1,44 @@
+// Package appname records the name of the running service, so that
+// libraries can label the connections they open with it.
+package appname
+
+import (
+	"os"
+	"path/filepath"
+	"sync/atomic"
+)
+
+var name atomic.Pointer[string]
+
+// Set records the service name. app.New and the srpc server builder call
+// it with their configured name; an empty name is ignored.
+func Set(n string) {
+	if n != "" {
+		name.Store(&n)
+	}
+}
+
+// Get returns the service name recorded with Set, else OTEL_SERVICE_NAME,
+// else the executable name.
+func Get() string {
+	if n := name.Load(); n != nil {
+		return *n
+	}
+	if n := os.Getenv("OTEL_SERVICE_NAME"); n != "" {
+		return n
+	}
+	return filepath.Base(os.Args[0])
+}
+
+// Postgres returns the application_name to set on PostgreSQL connections:
+// appName when set, else the one given in the DSN runtime params, else the
+// service name (see Get).
+func Postgres(appName string, params map[string]string) string {
+	if appName != "" {
+		return appName
+	}
+	if n := params["application_name"]; n != "" {
+		return n
+	}
+	return Get()
+}
@@ -0,0 +1,37 @@
+package appname
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestPostgres(t *testing.T) {
+	tests := []struct {
+		name    string
+		set     string
+		env     string
+		appName string
+		params  map[string]string
+		want    string
+	}{
+		{name: "configured", set: "orders", appName: "orders-worker", want: "orders-worker"},
+		{name: "dsn", set: "orders", params: map[string]string{"application_name": "from-dsn"}, want: "from-dsn"},
+		{name: "service name", set: "orders", env: "from-env", want: "orders"},
+		{name: "otel service name", env: "from-env", want: "from-env"},
+		{name: "executable", want: filepath.Base(os.Args[0])},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			name.Store(nil)
+			t.Cleanup(func() { name.Store(nil) })
+			t.Setenv("OTEL_SERVICE_NAME", tt.env)
+			Set(tt.set)
+
+			if got := Postgres(tt.appName, tt.params); got != tt.want {
+				t.Errorf("Postgres() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
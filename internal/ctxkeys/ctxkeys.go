@@ -0,0 +1,49 @@
+// Package ctxkeys defines typed context keys shared by goten packages.
+// Unexported key types cannot collide with keys set by other packages,
+// unlike plain string keys.
+package ctxkeys
+
+import "context"
+
+// String keys used with Hertz RequestContext.Set. They are kept for code
+// that reads values with RequestContext.Get.
+const (
+	// RequestIDString is the RequestContext key of the request ID.
+	RequestIDString = "requestID"
+	// JWTClaimsString is the default RequestContext key of the JWT claims.
+	JWTClaimsString = "jwt"
+)
+
+type key int
+
+const (
+	requestIDKey key = iota
+	jwtClaimsKey
+)
+
+// WithRequestID returns a copy of ctx carrying the request ID.
+func WithRequestID(ctx context.Context, requestID string) context.Context {
+	return context.WithValue(ctx, requestIDKey, requestID)
+}
+
+// RequestID returns the request ID carried by ctx, or "" if none.
+func RequestID(ctx context.Context) string {
+	if ctx == nil {
+		return ""
+	}
+	requestID, _ := ctx.Value(requestIDKey).(string)
+	return requestID
+}
+
+// WithJWTClaims returns a copy of ctx carrying the JWT claims.
+func WithJWTClaims(ctx context.Context, claims any) context.Context {
+	return context.WithValue(ctx, jwtClaimsKey, claims)
+}
+
+// JWTClaims returns the JWT claims carried by ctx, or nil if none.
+func JWTClaims(ctx context.Context) any {
+	if ctx == nil {
+		return nil
+	}
+	return ctx.Value(jwtClaimsKey)
+}
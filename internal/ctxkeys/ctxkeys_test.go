@@ -0,0 +1,125 @@
+// The tests live in an external package so that they can drive the
+// middlewares that import ctxkeys.
+package ctxkeys_test
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/bytedance/gopkg/cloud/metainfo"
+	"github.com/cloudwego/hertz/pkg/app"
+	"github.com/cloudwego/hertz/pkg/app/server"
+	"github.com/cloudwego/hertz/pkg/common/ut"
+	"github.com/golang-jwt/jwt/v5"
+
+	"github.com/ssgohq/goten-core/internal/ctxkeys"
+	"github.com/ssgohq/goten-core/middleware"
+	srpcmw "github.com/ssgohq/goten-core/srpc/middleware"
+)
+
+func TestAccessors(t *testing.T) {
+	tests := []struct {
+		name          string
+		ctx           context.Context
+		wantRequestID string
+		wantClaims    any
+	}{
+		{name: "nil context", ctx: nil},
+		{name: "empty context", ctx: context.Background()},
+		{
+			name:          "request ID",
+			ctx:           ctxkeys.WithRequestID(context.Background(), "req-1"),
+			wantRequestID: "req-1",
+		},
+		{
+			name:       "claims",
+			ctx:        ctxkeys.WithJWTClaims(context.Background(), "claims"),
+			wantClaims: "claims",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ctxkeys.RequestID(tt.ctx); got != tt.wantRequestID {
+				t.Errorf("RequestID() = %q, want %q", got, tt.wantRequestID)
+			}
+			if got := ctxkeys.JWTClaims(tt.ctx); got != tt.wantClaims {
+				t.Errorf("JWTClaims() = %v, want %v", got, tt.wantClaims)
+			}
+		})
+	}
+}
+
+func TestHTTPMiddlewareValues(t *testing.T) {
+	const secret = "test-secret"
+	token, err := middleware.GenerateToken(secret, jwt.MapClaims{"sub": "user-1"}, time.Minute)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var (
+		requestID, keptRequestID any
+		claims, keptClaims       any
+	)
+	h := server.New()
+	h.Use(middleware.RequestID(), middleware.JWT(middleware.JWTConfig{Secret: secret}))
+	h.GET("/", func(ctx context.Context, c *app.RequestContext) {
+		requestID = ctxkeys.RequestID(ctx)
+		keptRequestID, _ = c.Get(ctxkeys.RequestIDString)
+		claims = ctxkeys.JWTClaims(ctx)
+		keptClaims, _ = c.Get(ctxkeys.JWTClaimsString)
+	})
+	w := ut.PerformRequest(h.Engine, http.MethodGet, "/", nil,
+		ut.Header{Key: "X-Request-ID", Value: "req-1"},
+		ut.Header{Key: "Authorization", Value: "Bearer " + token})
+	if code := w.Result().StatusCode(); code != http.StatusOK {
+		t.Fatalf("status = %d, want %d", code, http.StatusOK)
+	}
+
+	if requestID != "req-1" {
+		t.Errorf("RequestID() = %v, want req-1", requestID)
+	}
+	if keptRequestID != "req-1" {
+		t.Errorf("%q key = %v, want req-1", ctxkeys.RequestIDString, keptRequestID)
+	}
+	got, ok := claims.(jwt.MapClaims)
+	if !ok || got["sub"] != "user-1" {
+		t.Errorf("JWTClaims() = %v, want the token claims", claims)
+	}
+	if kept, ok := keptClaims.(jwt.MapClaims); !ok || kept["sub"] != "user-1" {
+		t.Errorf("%q key = %v, want the token claims", ctxkeys.JWTClaimsString, keptClaims)
+	}
+}
+
+func TestRPCMiddlewareValues(t *testing.T) {
+	tests := []struct {
+		name     string
+		metadata string
+	}{
+		{name: "from metadata", metadata: "req-1"},
+		{name: "generated"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			if tt.metadata != "" {
+				ctx = metainfo.WithPersistentValue(ctx, srpcmw.RequestIDKey, tt.metadata)
+			}
+
+			var got string
+			ep := srpcmw.RequestID()(func(ctx context.Context, _, _ interface{}) error {
+				got = ctxkeys.RequestID(ctx)
+				return nil
+			})
+			if err := ep(ctx, nil, nil); err != nil {
+				t.Fatal(err)
+			}
+			if got == "" || (tt.metadata != "" && got != tt.metadata) {
+				t.Errorf("RequestID() = %q, want %q", got, tt.metadata)
+			}
+		})
+	}
+}
@@ -2,6 +2,13 @@ package lifecycle
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"net"
+	"os"
+	"syscall"
+	"time"
 
 	"github.com/cloudwego/hertz/pkg/app/server"
 	kitexserver "github.com/cloudwego/kitex/server"
@@ -26,15 +33,125 @@ func (a *HertzAdapter) Name() string {
 	return a.name
 }
 
-// Start starts the Hertz server.
-func (a *HertzAdapter) Start(_ context.Context) error {
-	go a.server.Spin()
-	return nil
+// hertzStartTimeout bounds how long Start waits for the server to listen
+// when ctx has no deadline.
+const hertzStartTimeout = 5 * time.Second
+
+// Start starts the Hertz server and waits until it listens, so that a bind
+// failure such as an address already in use is returned as a start error
+// instead of crashing or leaving the application running without the server.
+// For a unix network, a socket file left behind by a previous run is
+// removed first so that the address can be bound; a socket another process
+// still listens on is reported as an address in use.
+func (a *HertzAdapter) Start(ctx context.Context) error {
+	if err := a.removeStaleSocket(); err != nil {
+		return err
+	}
+	// The netpoll transport panics when it cannot bind, so check first
+	if err := a.checkBind(); err != nil {
+		return err
+	}
+	exited := make(chan struct{})
+	go func() {
+		defer close(exited)
+		a.server.Spin()
+	}()
+	return a.waitListening(ctx, exited)
+}
+
+// waitListening polls the engine until it is listening or Spin exits, which
+// it does, after logging the cause, when the server fails to run.
+func (a *HertzAdapter) waitListening(ctx context.Context, exited <-chan struct{}) error {
+	if _, ok := ctx.Deadline(); !ok {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, hertzStartTimeout)
+		defer cancel()
+	}
+	ticker := time.NewTicker(10 * time.Millisecond)
+	defer ticker.Stop()
+
+	for {
+		if a.server.IsRunning() {
+			return nil
+		}
+		select {
+		case <-exited:
+			return fmt.Errorf("hertz server %s failed to start, see the server log", a.name)
+		case <-ctx.Done():
+			return fmt.Errorf("hertz server %s not listening: %w", a.name, ctx.Err())
+		case <-ticker.C:
+		}
+	}
 }
 
-// Stop stops the Hertz server gracefully.
+// checkBind binds the server's address and releases it, reporting why the
+// server would fail to listen, e.g. because the address is already in use.
+func (a *HertzAdapter) checkBind() error {
+	opts := a.server.GetOptions()
+	network := opts.Network
+	if network == "" {
+		network = "tcp"
+	}
+	ln, err := net.Listen(network, opts.Addr)
+	if err != nil {
+		return fmt.Errorf("hertz server %s failed to start: %w", a.name, err)
+	}
+	return ln.Close()
+}
+
+// Stop stops the Hertz server gracefully and removes its unix socket file.
 func (a *HertzAdapter) Stop(ctx context.Context) error {
-	return a.server.Shutdown(ctx)
+	err := a.server.Shutdown(ctx)
+	if rmErr := a.removeSocket(); rmErr != nil && err == nil {
+		err = rmErr
+	}
+	return err
+}
+
+// socketDialTimeout bounds the probe of an existing unix socket.
+const socketDialTimeout = time.Second
+
+// removeStaleSocket deletes the server's unix socket file when nothing
+// listens on it any more. The socket is dialed first: a refused connection
+// means it is stale, while an accepted one, or any other dial error, leaves
+// the file in place and reports the address as in use.
+func (a *HertzAdapter) removeStaleSocket() error {
+	if !a.isSocket() {
+		return nil
+	}
+	addr := a.server.GetOptions().Addr
+	conn, err := net.DialTimeout("unix", addr, socketDialTimeout)
+	if err == nil {
+		_ = conn.Close()
+		return fmt.Errorf("hertz server %s failed to start: %s: address in use", a.name, addr)
+	}
+	if !errors.Is(err, syscall.ECONNREFUSED) {
+		return fmt.Errorf("hertz server %s failed to start: %s: address in use: %w", a.name, addr, err)
+	}
+	return a.removeSocket()
+}
+
+// removeSocket deletes the server's unix socket file if there is one.
+// Files that are not sockets are left untouched.
+func (a *HertzAdapter) removeSocket() error {
+	if !a.isSocket() {
+		return nil
+	}
+	if err := os.Remove(a.server.GetOptions().Addr); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return err
+	}
+	return nil
+}
+
+// isSocket reports whether the server listens on a unix network and its
+// address is an existing socket file.
+func (a *HertzAdapter) isSocket() bool {
+	opts := a.server.GetOptions()
+	if opts.Network != "unix" {
+		return false
+	}
+	info, err := os.Lstat(opts.Addr)
+	return err == nil && info.Mode()&fs.ModeSocket != 0
 }
 
 // KitexAdapter wraps a Kitex server to implement the Service interface.
@@ -0,0 +1,153 @@
+package lifecycle
+
+import (
+	"context"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/cloudwego/hertz/pkg/app/server"
+)
+
+func TestHertzAdapterUnixSocket(t *testing.T) {
+	tests := []struct {
+		name       string
+		setup      func(t *testing.T, path string)
+		wantErr    string
+		wantExists bool
+	}{
+		{
+			name:  "no file",
+			setup: func(t *testing.T, path string) {},
+		},
+		{
+			name: "stale socket",
+			setup: func(t *testing.T, path string) {
+				ln, err := net.Listen("unix", path)
+				if err != nil {
+					t.Fatal(err)
+				}
+				ln.(*net.UnixListener).SetUnlinkOnClose(false)
+				_ = ln.Close()
+			},
+		},
+		{
+			name: "socket in use",
+			setup: func(t *testing.T, path string) {
+				ln, err := net.Listen("unix", path)
+				if err != nil {
+					t.Fatal(err)
+				}
+				t.Cleanup(func() { _ = ln.Close() })
+			},
+			wantErr:    "address in use",
+			wantExists: true,
+		},
+		{
+			name: "regular file",
+			setup: func(t *testing.T, path string) {
+				if err := os.WriteFile(path, nil, 0o600); err != nil {
+					t.Fatal(err)
+				}
+			},
+			wantExists: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "http.sock")
+			tt.setup(t, path)
+
+			h := server.New(server.WithHostPorts(path), server.WithNetwork("unix"))
+			a := NewHertzAdapter("http", h)
+
+			err := a.removeStaleSocket()
+			if tt.wantErr == "" && err != nil {
+				t.Fatalf("removeStaleSocket() = %v", err)
+			}
+			if tt.wantErr != "" && (err == nil || !strings.Contains(err.Error(), tt.wantErr)) {
+				t.Fatalf("removeStaleSocket() = %v, want error containing %q", err, tt.wantErr)
+			}
+			if _, statErr := os.Lstat(path); (statErr == nil) != tt.wantExists {
+				t.Errorf("file exists = %v, want %v", statErr == nil, tt.wantExists)
+			}
+		})
+	}
+}
+
+func TestHertzAdapterStartOnStaleSocket(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "http.sock")
+	ln, err := net.Listen("unix", path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ln.(*net.UnixListener).SetUnlinkOnClose(false)
+	_ = ln.Close()
+
+	h := server.New(server.WithHostPorts(path), server.WithNetwork("unix"))
+	a := NewHertzAdapter("http", h)
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := a.Start(ctx); err != nil {
+		t.Fatalf("Start() = %v", err)
+	}
+	if err := a.Stop(ctx); err != nil {
+		t.Fatalf("Stop() = %v", err)
+	}
+	if _, err := os.Lstat(path); err == nil {
+		t.Error("socket file left after Stop")
+	}
+}
+
+func TestHertzAdapterStartBindError(t *testing.T) {
+	tests := []struct {
+		name     string
+		occupied bool
+		wantErr  string
+	}{
+		{name: "free port"},
+		{name: "port in use", occupied: true, wantErr: "address already in use"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ln, err := net.Listen("tcp", "127.0.0.1:0")
+			if err != nil {
+				t.Fatal(err)
+			}
+			addr := ln.Addr().String()
+			if tt.occupied {
+				t.Cleanup(func() { _ = ln.Close() })
+			} else {
+				_ = ln.Close()
+			}
+
+			h := server.New(server.WithHostPorts(addr))
+			m := NewManager(LifecycleConfig{Name: "test"})
+			m.Register(NewHertzAdapter("http", h))
+
+			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+			err = m.Start(ctx)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Fatalf("Start() = %v", err)
+				}
+				if err := m.Stop(ctx); err != nil {
+					t.Fatalf("Stop() = %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Fatalf("Start() = %v, want error containing %q", err, tt.wantErr)
+			}
+			if got := m.State(); got != StateError {
+				t.Errorf("state = %v, want %v", got, StateError)
+			}
+		})
+	}
+}
@@ -3,7 +3,11 @@ package lifecycle
 import (
 	"context"
 	"encoding/json"
+	"errors"
+	"fmt"
 	"net/http"
+	"strconv"
+	"strings"
 	"sync"
 	"time"
 
@@ -23,8 +27,15 @@ const (
 )
 
 // HealthCheck is a function that returns the health of a component.
+// Prefer DetailedHealthCheck for checks that can explain their status.
 type HealthCheck func(ctx context.Context) HealthStatus
 
+// DetailedHealthCheck is a function that returns the health of a component
+// together with details explaining its status, such as the latency or error
+// of a dependency, which are shown in the health response. Register it with
+// RegisterDetailed; see PingHealthCheck for a ready-made one.
+type DetailedHealthCheck func(ctx context.Context) ComponentHealth
+
 // ComponentHealth represents the health of a single component.
 type ComponentHealth struct {
 	Status    HealthStatus   `json:"status"`
@@ -39,43 +50,141 @@ type HealthResponse struct {
 	Timestamp  time.Time                  `json:"timestamp"`
 }
 
+// ErrTooManyHealthChecks is returned by TryRegister when the configured
+// maximum number of health checks has been reached.
+var ErrTooManyHealthChecks = errors.New("lifecycle: too many health checks registered")
+
 // HealthManager manages health checks for services.
 type HealthManager struct {
-	checks map[string]HealthCheck
-	mu     sync.RWMutex
+	checks       map[string]*healthEntry
+	maxChecks    int
+	checkTimeout time.Duration
+	mu           sync.RWMutex
+}
+
+// healthEntry is a registered check and its run in flight, if any.
+type healthEntry struct {
+	check DetailedHealthCheck
+
+	mu      sync.Mutex
+	running *checkRun
+}
+
+// checkRun is a single run of a check, shared by the Check calls made while
+// it is in flight.
+type checkRun struct {
+	done   chan struct{}
+	result ComponentHealth
+}
+
+// HealthOption configures a HealthManager.
+type HealthOption func(*HealthManager)
+
+// WithMaxChecks limits the number of health checks that can be registered.
+// 0 means unlimited.
+func WithMaxChecks(n int) HealthOption {
+	return func(h *HealthManager) {
+		h.maxChecks = n
+	}
+}
+
+// WithCheckTimeout bounds how long each health check may run; a check that
+// does not return in time is reported as down. 0 means no per-check limit.
+// Default: 5 seconds.
+func WithCheckTimeout(d time.Duration) HealthOption {
+	return func(h *HealthManager) {
+		h.checkTimeout = d
+	}
 }
 
 // NewHealthManager creates a new health manager.
-func NewHealthManager() *HealthManager {
-	return &HealthManager{
-		checks: make(map[string]HealthCheck),
+func NewHealthManager(opts ...HealthOption) *HealthManager {
+	h := &HealthManager{
+		checks:       make(map[string]*healthEntry),
+		checkTimeout: 5 * time.Second,
 	}
+	for _, opt := range opts {
+		opt(h)
+	}
+	return h
 }
 
 // Register adds a health check for a component.
+// Registering an existing name replaces the previous check and logs a
+// warning. A check that would exceed the limit set with WithMaxChecks is
+// dropped and logged; use TryRegister to handle that case.
 func (h *HealthManager) Register(name string, check HealthCheck) {
+	h.RegisterDetailed(name, detailed(check))
+}
+
+// RegisterDetailed adds a health check that reports details along with
+// its status. It follows the same rules as Register.
+func (h *HealthManager) RegisterDetailed(name string, check DetailedHealthCheck) {
+	if err := h.TryRegisterDetailed(name, check); err != nil {
+		logx.Errorw("Health check not registered", "name", name, "error", err)
+	}
+}
+
+// TryRegister is like Register but returns ErrTooManyHealthChecks instead
+// of dropping a check beyond the limit set with WithMaxChecks.
+func (h *HealthManager) TryRegister(name string, check HealthCheck) error {
+	return h.TryRegisterDetailed(name, detailed(check))
+}
+
+// TryRegisterDetailed is like RegisterDetailed but returns
+// ErrTooManyHealthChecks instead of dropping a check beyond the limit.
+func (h *HealthManager) TryRegisterDetailed(name string, check DetailedHealthCheck) error {
 	h.mu.Lock()
 	defer h.mu.Unlock()
-	h.checks[name] = check
+
+	if _, exists := h.checks[name]; exists {
+		logx.Warnw("Health check already registered, replacing", "name", name)
+	} else if h.maxChecks > 0 && len(h.checks) >= h.maxChecks {
+		return fmt.Errorf("%w: limit %d, rejected %q", ErrTooManyHealthChecks, h.maxChecks, name)
+	}
+	h.checks[name] = &healthEntry{check: check}
+	return nil
 }
 
-// Check runs all health checks and returns the overall health.
+// detailed adapts a HealthCheck to a DetailedHealthCheck.
+func detailed(check HealthCheck) DetailedHealthCheck {
+	return func(ctx context.Context) ComponentHealth {
+		return ComponentHealth{Status: check(ctx)}
+	}
+}
+
+// Check runs all health checks concurrently and returns the overall health.
+// Checks run outside the lock, so Register is never blocked by a slow check,
+// and each is bounded by the timeout set with WithCheckTimeout.
 func (h *HealthManager) Check(ctx context.Context) HealthResponse {
 	h.mu.RLock()
-	defer h.mu.RUnlock()
+	checks := make(map[string]*healthEntry, len(h.checks))
+	for name, entry := range h.checks {
+		checks[name] = entry
+	}
+	h.mu.RUnlock()
 
 	response := HealthResponse{
 		Status:     HealthStatusUp,
-		Components: make(map[string]ComponentHealth),
+		Components: make(map[string]ComponentHealth, len(checks)),
 		Timestamp:  time.Now(),
 	}
 
-	for name, check := range h.checks {
-		status := check(ctx)
-		response.Components[name] = ComponentHealth{
-			Status:    status,
-			Timestamp: time.Now(),
-		}
+	type result struct {
+		name      string
+		component ComponentHealth
+	}
+	results := make(chan result, len(checks))
+	for name, entry := range checks {
+		go func() {
+			results <- result{name: name, component: h.runCheck(ctx, name, entry)}
+		}()
+	}
+
+	for range checks {
+		r := <-results
+		response.Components[r.name] = r.component
+		status := r.component.Status
 
 		// Update overall status
 		if status == HealthStatusDown {
@@ -88,15 +197,68 @@ func (h *HealthManager) Check(ctx context.Context) HealthResponse {
 	return response
 }
 
+// runCheck runs a single check, reporting it down if it outlives the
+// per-check timeout or ctx. A check that ignores its context keeps running
+// in the background but no longer delays the response; until it returns,
+// later calls wait for that run instead of starting another one, so a hung
+// check holds at most one goroutine. A panicking check is reported down.
+func (h *HealthManager) runCheck(ctx context.Context, name string, entry *healthEntry) ComponentHealth {
+	if h.checkTimeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, h.checkTimeout)
+		defer cancel()
+	}
+
+	entry.mu.Lock()
+	run := entry.running
+	if run == nil {
+		run = &checkRun{done: make(chan struct{})}
+		entry.running = run
+		go entry.run(ctx, name, run)
+	}
+	entry.mu.Unlock()
+
+	var component ComponentHealth
+	select {
+	case <-run.done:
+		component = run.result
+	case <-ctx.Done():
+		component = ComponentHealth{
+			Status:  HealthStatusDown,
+			Details: map[string]any{"error": ctx.Err().Error()},
+		}
+	}
+	component.Timestamp = time.Now()
+	return component
+}
+
+// run calls the check, recovering a panic, and completes the run.
+func (e *healthEntry) run(ctx context.Context, name string, run *checkRun) {
+	defer func() {
+		if p := recover(); p != nil {
+			logx.Errorw("Health check panicked", "name", name, "panic", p)
+			run.result = ComponentHealth{
+				Status:  HealthStatusDown,
+				Details: map[string]any{"error": fmt.Sprintf("panic: %v", p)},
+			}
+		}
+		e.mu.Lock()
+		e.running = nil
+		e.mu.Unlock()
+		close(run.done)
+	}()
+	run.result = e.check(ctx)
+}
+
 // HTTPHandler returns an HTTP handler for health checks.
 // Returns 200 for healthy, 503 for unhealthy.
+// The body is JSON unless the Accept header prefers text/plain, in which
+// case it is a bare "OK" or "DOWN" with the same status code.
 func (h *HealthManager) HTTPHandler() http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		ctx := r.Context()
 		response := h.Check(ctx)
 
-		w.Header().Set("Content-Type", "application/json")
-
 		var statusCode int
 		switch response.Status {
 		case HealthStatusUp:
@@ -106,6 +268,19 @@ func (h *HealthManager) HTTPHandler() http.HandlerFunc {
 		default:
 			statusCode = http.StatusServiceUnavailable
 		}
+
+		if wantsText(r.Header.Get("Accept")) {
+			w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+			w.WriteHeader(statusCode)
+			body := "OK"
+			if statusCode != http.StatusOK {
+				body = "DOWN"
+			}
+			_, _ = w.Write([]byte(body))
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
 		w.WriteHeader(statusCode)
 
 		if err := json.NewEncoder(w).Encode(response); err != nil {
@@ -114,6 +289,34 @@ func (h *HealthManager) HTTPHandler() http.HandlerFunc {
 	}
 }
 
+// wantsText reports whether an Accept header prefers text/plain over JSON.
+// Media types are compared by their q values; ties, wildcards and an empty
+// header favour JSON.
+func wantsText(accept string) bool {
+	if accept == "" {
+		return false
+	}
+	textQ, jsonQ := -1.0, -1.0
+	for _, part := range strings.Split(accept, ",") {
+		mediaType, params, _ := strings.Cut(part, ";")
+		q := 1.0
+		for _, param := range strings.Split(params, ";") {
+			if key, value, ok := strings.Cut(strings.TrimSpace(param), "="); ok && key == "q" {
+				if parsed, err := strconv.ParseFloat(value, 64); err == nil {
+					q = parsed
+				}
+			}
+		}
+		switch strings.ToLower(strings.TrimSpace(mediaType)) {
+		case "text/plain":
+			textQ = max(textQ, q)
+		case "application/json":
+			jsonQ = max(jsonQ, q)
+		}
+	}
+	return textQ > 0 && textQ > jsonQ
+}
+
 // LivenessHandler returns an HTTP handler for liveness probe.
 // Always returns 200 if the process is running.
 func LivenessHandler() http.HandlerFunc {
@@ -0,0 +1,260 @@
+package lifecycle
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestHealthManagerCheck(t *testing.T) {
+	tests := []struct {
+		name       string
+		check      HealthCheck
+		wantStatus HealthStatus
+		wantError  string
+	}{
+		{
+			name:       "up",
+			check:      func(context.Context) HealthStatus { return HealthStatusUp },
+			wantStatus: HealthStatusUp,
+		},
+		{
+			name:       "degraded",
+			check:      func(context.Context) HealthStatus { return HealthStatusDegraded },
+			wantStatus: HealthStatusDegraded,
+		},
+		{
+			name: "timeout",
+			check: func(ctx context.Context) HealthStatus {
+				<-ctx.Done()
+				return HealthStatusUp
+			},
+			wantStatus: HealthStatusDown,
+			wantError:  "deadline exceeded",
+		},
+		{
+			name:       "panic",
+			check:      func(context.Context) HealthStatus { panic("boom") },
+			wantStatus: HealthStatusDown,
+			wantError:  "panic: boom",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := NewHealthManager(WithCheckTimeout(50 * time.Millisecond))
+			h.Register("db", tt.check)
+
+			resp := h.Check(context.Background())
+			component := resp.Components["db"]
+			if component.Status != tt.wantStatus || resp.Status != tt.wantStatus {
+				t.Fatalf("status = %s (overall %s), want %s", component.Status, resp.Status, tt.wantStatus)
+			}
+			if tt.wantError != "" {
+				msg, _ := component.Details["error"].(string)
+				if !strings.Contains(msg, tt.wantError) {
+					t.Fatalf("error detail = %q, want it to contain %q", msg, tt.wantError)
+				}
+			}
+		})
+	}
+}
+
+func TestHealthManagerCheckInFlight(t *testing.T) {
+	var runs atomic.Int32
+	release := make(chan struct{})
+	h := NewHealthManager(WithCheckTimeout(10 * time.Millisecond))
+	// The check ignores its context, like a hung driver call
+	h.Register("hung", func(context.Context) HealthStatus {
+		runs.Add(1)
+		<-release
+		return HealthStatusUp
+	})
+
+	for i := 0; i < 5; i++ {
+		if got := h.Check(context.Background()).Status; got != HealthStatusDown {
+			t.Fatalf("check %d: status = %s, want down", i, got)
+		}
+	}
+	if got := runs.Load(); got != 1 {
+		t.Fatalf("runs while in flight = %d, want 1", got)
+	}
+
+	// Once the run completes, its result is reported and the next Check
+	// starts a new run
+	close(release)
+	deadline := time.Now().Add(time.Second)
+	for h.Check(context.Background()).Status != HealthStatusUp {
+		if time.Now().After(deadline) {
+			t.Fatal("check did not recover after the hung run returned")
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+	if got := h.Check(context.Background()).Status; got != HealthStatusUp {
+		t.Fatalf("status after recovery = %s, want up", got)
+	}
+	if got := runs.Load(); got < 2 {
+		t.Fatalf("runs after release = %d, want at least 2", got)
+	}
+}
+
+func TestHealthManagerTryRegister(t *testing.T) {
+	up := func(context.Context) HealthStatus { return HealthStatusUp }
+	tests := []struct {
+		name      string
+		maxChecks int
+		names     []string
+		wantErr   []bool
+		wantCount int
+	}{
+		{name: "unlimited", names: []string{"a", "b", "c"}, wantErr: []bool{false, false, false}, wantCount: 3},
+		{name: "limit", maxChecks: 2, names: []string{"a", "b", "c"}, wantErr: []bool{false, false, true}, wantCount: 2},
+		{name: "replace at limit", maxChecks: 1, names: []string{"a", "a"}, wantErr: []bool{false, false}, wantCount: 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := NewHealthManager(WithMaxChecks(tt.maxChecks))
+			for i, name := range tt.names {
+				err := h.TryRegister(name, up)
+				if (err != nil) != tt.wantErr[i] || (err != nil && !errors.Is(err, ErrTooManyHealthChecks)) {
+					t.Errorf("TryRegister(%s) = %v, wantErr %v", name, err, tt.wantErr[i])
+				}
+			}
+			if got := len(h.Check(context.Background()).Components); got != tt.wantCount {
+				t.Errorf("components = %d, want %d", got, tt.wantCount)
+			}
+		})
+	}
+}
+
+func TestHealthManagerConcurrentRegisterAndCheck(t *testing.T) {
+	h := NewHealthManager()
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 20; j++ {
+				h.Register(fmt.Sprintf("check-%d-%d", i, j), func(context.Context) HealthStatus { return HealthStatusUp })
+			}
+		}()
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 20; j++ {
+				if got := h.Check(context.Background()).Status; got != HealthStatusUp {
+					t.Errorf("status = %s, want up", got)
+				}
+			}
+		}()
+	}
+	wg.Wait()
+}
+
+func TestHealthManagerHTTPHandler(t *testing.T) {
+	tests := []struct {
+		name     string
+		status   HealthStatus
+		accept   string
+		wantCode int
+		wantType string
+		wantBody string // text body; JSON bodies are decoded instead
+	}{
+		{name: "up json", status: HealthStatusUp, wantCode: http.StatusOK, wantType: "application/json"},
+		{
+			name:     "down json",
+			status:   HealthStatusDown,
+			accept:   "application/json",
+			wantCode: http.StatusServiceUnavailable,
+			wantType: "application/json",
+		},
+		{
+			name:     "up text",
+			status:   HealthStatusUp,
+			accept:   "text/plain",
+			wantCode: http.StatusOK,
+			wantType: "text/plain; charset=utf-8",
+			wantBody: "OK",
+		},
+		{
+			name:     "degraded text",
+			status:   HealthStatusDegraded,
+			accept:   "text/plain",
+			wantCode: http.StatusOK,
+			wantType: "text/plain; charset=utf-8",
+			wantBody: "OK",
+		},
+		{
+			name:     "down text",
+			status:   HealthStatusDown,
+			accept:   "text/plain",
+			wantCode: http.StatusServiceUnavailable,
+			wantType: "text/plain; charset=utf-8",
+			wantBody: "DOWN",
+		},
+		{
+			name:     "json preferred by q value",
+			status:   HealthStatusUp,
+			accept:   "text/plain;q=0.5, application/json",
+			wantCode: http.StatusOK,
+			wantType: "application/json",
+		},
+		{
+			name:     "text preferred by q value",
+			status:   HealthStatusDown,
+			accept:   "application/json;q=0.2, text/plain;q=0.8",
+			wantCode: http.StatusServiceUnavailable,
+			wantType: "text/plain; charset=utf-8",
+			wantBody: "DOWN",
+		},
+		{
+			name:     "wildcard",
+			status:   HealthStatusUp,
+			accept:   "*/*",
+			wantCode: http.StatusOK,
+			wantType: "application/json",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := NewHealthManager()
+			h.Register("db", func(context.Context) HealthStatus { return tt.status })
+
+			req := httptest.NewRequest(http.MethodGet, "/readyz", nil)
+			if tt.accept != "" {
+				req.Header.Set("Accept", tt.accept)
+			}
+			rec := httptest.NewRecorder()
+			h.HTTPHandler()(rec, req)
+
+			if rec.Code != tt.wantCode {
+				t.Errorf("status code = %d, want %d", rec.Code, tt.wantCode)
+			}
+			if got := rec.Header().Get("Content-Type"); got != tt.wantType {
+				t.Errorf("Content-Type = %q, want %q", got, tt.wantType)
+			}
+			if tt.wantBody != "" {
+				if got := rec.Body.String(); got != tt.wantBody {
+					t.Errorf("body = %q, want %q", got, tt.wantBody)
+				}
+				return
+			}
+			var resp HealthResponse
+			if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+				t.Fatalf("body %q is not a health response: %v", rec.Body, err)
+			}
+			if resp.Status != tt.status || resp.Components["db"].Status != tt.status {
+				t.Errorf("response = %+v, want status %s", resp, tt.status)
+			}
+		})
+	}
+}
@@ -0,0 +1,69 @@
+package lifecycle
+
+import (
+	"context"
+	"testing"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+
+	"github.com/ssgohq/goten-core/logx"
+)
+
+// loggingService logs through the logger of the context it is given.
+type loggingService struct{ name string }
+
+func (s loggingService) Name() string { return s.name }
+
+func (s loggingService) Start(ctx context.Context) error {
+	logx.FromContext(ctx).Infow("Service work")
+	return nil
+}
+
+func (s loggingService) Stop(context.Context) error { return nil }
+
+func TestManagerServiceLogFields(t *testing.T) {
+	tests := []struct {
+		name        string
+		config      LifecycleConfig
+		wantManager string
+	}{
+		{name: "sequential", config: LifecycleConfig{Name: "orders"}, wantManager: "orders"},
+		{name: "parallel", config: LifecycleConfig{Name: "orders", ParallelStartup: true}, wantManager: "orders"},
+		{name: "default name", wantManager: "default"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			core, logs := observer.New(zapcore.InfoLevel)
+			ctx := logx.WithContext(context.Background(), zap.New(core).Sugar())
+
+			m := NewManager(tt.config)
+			m.Register(loggingService{name: "db"})
+			m.Register(loggingService{name: "cache"})
+			if err := m.Start(ctx); err != nil {
+				t.Fatal(err)
+			}
+
+			for _, msg := range []string{"Starting service", "Service work", "Service started"} {
+				entries := logs.FilterMessage(msg).All()
+				if len(entries) != 2 {
+					t.Fatalf("%q logged %d times, want 2", msg, len(entries))
+				}
+				services := map[string]bool{}
+				for _, e := range entries {
+					fields := e.ContextMap()
+					if fields["manager"] != tt.wantManager {
+						t.Errorf("%q manager = %v, want %q", msg, fields["manager"], tt.wantManager)
+					}
+					service, _ := fields["service"].(string)
+					services[service] = true
+				}
+				if !services["db"] || !services["cache"] {
+					t.Errorf("%q services = %v, want db and cache", msg, services)
+				}
+			}
+		})
+	}
+}
@@ -2,22 +2,28 @@ package lifecycle
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"sort"
+	"strings"
 	"sync"
 	"time"
 
+	"go.uber.org/zap"
+	"golang.org/x/sync/errgroup"
+
 	"github.com/ssgohq/goten-core/logx"
 )
 
 // Manager orchestrates the lifecycle of multiple services.
 // It handles graceful startup and shutdown, executing hooks at appropriate times.
 type Manager struct {
-	config   LifecycleConfig
-	services []Service
-	hooks    map[HookPhase][]Hook
-	state    State
-	mu       sync.RWMutex
+	config    LifecycleConfig
+	services  []Service
+	hooks     map[HookPhase][]Hook
+	state     State
+	listeners []func(from, to State)
+	mu        sync.RWMutex
 }
 
 // NewManager creates a new lifecycle manager.
@@ -28,6 +34,9 @@ func NewManager(config LifecycleConfig) *Manager {
 	if config.GracePeriod == 0 {
 		config.GracePeriod = 5 * time.Second
 	}
+	if config.Name == "" {
+		config.Name = "default"
+	}
 	return &Manager{
 		config:   config,
 		services: make([]Service, 0),
@@ -40,6 +49,8 @@ func NewManager(config LifecycleConfig) *Manager {
 }
 
 // Register adds a service to be managed.
+// Services registered after Start has begun are not started by that call,
+// but are stopped by Stop.
 func (m *Manager) Register(svc Service) {
 	m.mu.Lock()
 	defer m.mu.Unlock()
@@ -53,6 +64,14 @@ func (m *Manager) AddHook(hook Hook) {
 	m.hooks[hook.Phase] = append(m.hooks[hook.Phase], hook)
 }
 
+// snapshot returns a copy of the registered services, so that Start and Stop
+// can iterate them while Register is called concurrently.
+func (m *Manager) snapshot() []Service {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return append([]Service(nil), m.services...)
+}
+
 // State returns the current state.
 func (m *Manager) State() State {
 	m.mu.RLock()
@@ -62,10 +81,10 @@ func (m *Manager) State() State {
 
 // Start starts all registered services in order.
 // It executes startup hooks before and after starting services.
+// If a service fails to start, the services already started are stopped in
+// reverse order and the start error is returned joined with any stop errors.
 func (m *Manager) Start(ctx context.Context) error {
-	m.mu.Lock()
-	m.state = StateStarting
-	m.mu.Unlock()
+	m.setState(StateStarting)
 
 	// Execute pre-start hooks
 	if err := m.executeHooks(ctx, HookPhaseStartup, "before_start"); err != nil {
@@ -74,64 +93,233 @@ func (m *Manager) Start(ctx context.Context) error {
 	}
 
 	// Start services
-	for _, svc := range m.services {
-		logx.Infow("Starting service", "name", svc.Name())
-		if err := svc.Start(ctx); err != nil {
+	services := m.snapshot()
+	if m.config.ParallelStartup {
+		if err := m.startParallel(ctx, services); err != nil {
 			m.setState(StateError)
-			return fmt.Errorf("service %s failed to start: %w", svc.Name(), err)
+			return err
+		}
+	} else {
+		started := make([]bool, len(services))
+		for i, svc := range services {
+			svcCtx, log := m.serviceContext(ctx, svc)
+			log.Infow("Starting service")
+			if err := svc.Start(svcCtx); err != nil {
+				m.setState(StateError)
+				startErr := fmt.Errorf("service %s failed to start: %w", svc.Name(), err)
+				return errors.Join(startErr, m.rollback(ctx, services, started))
+			}
+			started[i] = true
+			log.Infow("Service started")
 		}
-		logx.Infow("Service started", "name", svc.Name())
 	}
 
 	// Execute post-start hooks
 	if err := m.executeHooks(ctx, HookPhaseStartup, "after_start"); err != nil {
-		logx.Warnw("Post-start hooks failed", "error", err)
+		m.logger(ctx).Warnw("Post-start hooks failed", "error", err)
 	}
 
 	m.setState(StateRunning)
 	return nil
 }
 
+// ErrShutdownTimeout is returned by Stop when services are still stopping
+// after both ShutdownTimeout and GracePeriod have elapsed.
+var ErrShutdownTimeout = errors.New("lifecycle: shutdown timed out")
+
 // Stop stops all registered services in reverse order.
 // It executes shutdown hooks before and after stopping services.
+//
+// Services get ShutdownTimeout to stop, through the context passed to them.
+// If they are still stopping when it expires, Stop waits GracePeriod longer
+// for them to return, then logs the services that are stuck or were never
+// reached and returns an error wrapping ErrShutdownTimeout without waiting
+// further.
 func (m *Manager) Stop(ctx context.Context) error {
-	m.mu.Lock()
-	m.state = StateStopping
-	m.mu.Unlock()
+	m.setState(StateStopping)
 
 	// Create timeout context
 	timeoutCtx, cancel := context.WithTimeout(ctx, m.config.ShutdownTimeout)
 	defer cancel()
 
+	services := m.snapshot()
+	progress := newStopProgress(services)
+	done := make(chan error, 1)
+	go func() {
+		done <- m.stopServices(timeoutCtx, services, progress)
+	}()
+
+	select {
+	case err := <-done:
+		m.setState(StateStopped)
+		return err
+	case <-timeoutCtx.Done():
+	}
+
+	// Grace phase: give services that honor the cancelled context time to return
+	grace := time.NewTimer(m.config.GracePeriod)
+	defer grace.Stop()
+	select {
+	case err := <-done:
+		m.setState(StateStopped)
+		return err
+	case <-grace.C:
+	}
+
+	stuck := progress.pending()
+	m.logger(ctx).Errorw("Shutdown timed out, services not stopped",
+		"services", stuck,
+		"shutdownTimeout", m.config.ShutdownTimeout,
+		"gracePeriod", m.config.GracePeriod,
+	)
+	m.setState(StateError)
+	return fmt.Errorf("%w: not stopped: %s", ErrShutdownTimeout, strings.Join(stuck, ", "))
+}
+
+// stopServices runs the shutdown hooks and drains and stops the services,
+// recording each stopped service in progress.
+func (m *Manager) stopServices(ctx context.Context, services []Service, progress *stopProgress) error {
 	// Execute pre-stop hooks
-	if err := m.executeHooks(timeoutCtx, HookPhaseShutdown, "before_stop"); err != nil {
-		logx.Warnw("Pre-stop hooks failed", "error", err)
+	if err := m.executeHooks(ctx, HookPhaseShutdown, "before_stop"); err != nil {
+		m.logger(ctx).Warnw("Pre-stop hooks failed", "error", err)
 	}
 
+	// Stop intake everywhere before stopping anything
+	m.drain(ctx, services)
+
 	// Stop services in reverse order
 	var stopErr error
-	for i := len(m.services) - 1; i >= 0; i-- {
-		svc := m.services[i]
-		logx.Infow("Stopping service", "name", svc.Name())
-		if err := svc.Stop(timeoutCtx); err != nil {
-			logx.Errorw("Service failed to stop", "name", svc.Name(), "error", err)
+	for i := len(services) - 1; i >= 0; i-- {
+		svc := services[i]
+		svcCtx, log := m.serviceContext(ctx, svc)
+		log.Infow("Stopping service")
+		if err := svc.Stop(svcCtx); err != nil {
+			log.Errorw("Service failed to stop", "error", err)
 			if stopErr == nil {
 				stopErr = err
 			}
 		} else {
-			logx.Infow("Service stopped", "name", svc.Name())
+			log.Infow("Service stopped")
 		}
+		progress.done(i)
 	}
 
 	// Execute post-stop hooks
-	if err := m.executeHooks(timeoutCtx, HookPhaseShutdown, "after_stop"); err != nil {
-		logx.Warnw("Post-stop hooks failed", "error", err)
+	if err := m.executeHooks(ctx, HookPhaseShutdown, "after_stop"); err != nil {
+		m.logger(ctx).Warnw("Post-stop hooks failed", "error", err)
 	}
 
-	m.setState(StateStopped)
 	return stopErr
 }
 
+// stopProgress tracks which services have not returned from Stop yet.
+type stopProgress struct {
+	mu      sync.Mutex
+	names   []string
+	stopped []bool
+}
+
+func newStopProgress(services []Service) *stopProgress {
+	names := make([]string, len(services))
+	for i, svc := range services {
+		names[i] = svc.Name()
+	}
+	return &stopProgress{names: names, stopped: make([]bool, len(services))}
+}
+
+func (p *stopProgress) done(i int) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.stopped[i] = true
+}
+
+// pending returns the services not stopped yet, in stop order.
+func (p *stopProgress) pending() []string {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	var names []string
+	for i := len(p.names) - 1; i >= 0; i-- {
+		if !p.stopped[i] {
+			names = append(names, p.names[i])
+		}
+	}
+	return names
+}
+
+// startParallel starts all services concurrently. If any fails, the context
+// of the others is cancelled and the services that did start are rolled back.
+func (m *Manager) startParallel(ctx context.Context, services []Service) error {
+	started := make([]bool, len(services))
+	g, gctx := errgroup.WithContext(ctx)
+	for i, svc := range services {
+		g.Go(func() error {
+			svcCtx, log := m.serviceContext(gctx, svc)
+			log.Infow("Starting service")
+			if err := svc.Start(svcCtx); err != nil {
+				return fmt.Errorf("service %s failed to start: %w", svc.Name(), err)
+			}
+			started[i] = true
+			log.Infow("Service started")
+			return nil
+		})
+	}
+	if err := g.Wait(); err != nil {
+		return errors.Join(err, m.rollback(ctx, services, started))
+	}
+	return nil
+}
+
+// rollback stops the started services in reverse registration order after a
+// failed startup, within ShutdownTimeout. It returns their stop errors joined.
+func (m *Manager) rollback(ctx context.Context, services []Service, started []bool) error {
+	stopCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), m.config.ShutdownTimeout)
+	defer cancel()
+
+	var errs []error
+	for i := len(services) - 1; i >= 0; i-- {
+		if !started[i] {
+			continue
+		}
+		svc := services[i]
+		svcCtx, log := m.serviceContext(stopCtx, svc)
+		log.Infow("Stopping service after failed startup")
+		if err := svc.Stop(svcCtx); err != nil {
+			log.Errorw("Service failed to stop", "error", err)
+			errs = append(errs, fmt.Errorf("service %s failed to stop: %w", svc.Name(), err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// drain calls Drain on every service implementing Drainer, in reverse order.
+// Failures are logged; the services are still stopped afterwards.
+func (m *Manager) drain(ctx context.Context, services []Service) {
+	for i := len(services) - 1; i >= 0; i-- {
+		d, ok := services[i].(Drainer)
+		if !ok {
+			continue
+		}
+		svcCtx, log := m.serviceContext(ctx, services[i])
+		log.Infow("Draining service")
+		if err := d.Drain(svcCtx); err != nil {
+			log.Warnw("Service failed to drain", "error", err)
+		}
+	}
+}
+
+// logger returns the logger for ctx tagged with the manager name.
+func (m *Manager) logger(ctx context.Context) *zap.SugaredLogger {
+	return logx.FromContext(ctx).With("manager", m.config.Name)
+}
+
+// serviceContext returns ctx carrying a logger tagged with the manager and
+// service names, for the service to log through logx.Ctx or
+// logx.FromContext, along with that logger.
+func (m *Manager) serviceContext(ctx context.Context, svc Service) (context.Context, *zap.SugaredLogger) {
+	log := m.logger(ctx).With("service", svc.Name())
+	return logx.WithContext(ctx, log), log
+}
+
 // executeHooks executes hooks for the given phase and name.
 func (m *Manager) executeHooks(ctx context.Context, phase HookPhase, name string) error {
 	m.mu.RLock()
@@ -156,8 +344,32 @@ func (m *Manager) executeHooks(ctx context.Context, phase HookPhase, name string
 	return nil
 }
 
-func (m *Manager) setState(state State) {
+// OnStateChange registers a listener called on every state transition with
+// the previous and new state. Listeners run synchronously in registration order,
+// without the manager's lock held, so they may call back into the manager.
+//
+// Example:
+//
+//	manager.OnStateChange(func(_, state lifecycle.State) {
+//	    metric.SetReady(state == lifecycle.StateRunning)
+//	})
+func (m *Manager) OnStateChange(fn func(from, to State)) {
 	m.mu.Lock()
 	defer m.mu.Unlock()
+	m.listeners = append(m.listeners, fn)
+}
+
+func (m *Manager) setState(state State) {
+	m.mu.Lock()
+	old := m.state
 	m.state = state
+	listeners := append([]func(from, to State){}, m.listeners...)
+	m.mu.Unlock()
+
+	if old == state {
+		return
+	}
+	for _, fn := range listeners {
+		fn(old, state)
+	}
 }
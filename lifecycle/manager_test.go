@@ -0,0 +1,293 @@
+package lifecycle
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// events records service calls in order.
+type events struct {
+	mu   sync.Mutex
+	list []string
+}
+
+func (e *events) add(event string) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.list = append(e.list, event)
+}
+
+func (e *events) String() string {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return strings.Join(e.list, " ")
+}
+
+// fakeService takes startTime to start, then fails with startErr if set.
+type fakeService struct {
+	name      string
+	startTime time.Duration
+	startErr  error
+	events    *events
+}
+
+func (s *fakeService) Name() string { return s.name }
+
+func (s *fakeService) Start(ctx context.Context) error {
+	select {
+	case <-time.After(s.startTime):
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+	if s.startErr != nil {
+		return s.startErr
+	}
+	s.events.add("start:" + s.name)
+	return nil
+}
+
+func (s *fakeService) Stop(context.Context) error {
+	s.events.add("stop:" + s.name)
+	return nil
+}
+
+func TestManagerParallelStartup(t *testing.T) {
+	const startTime = 200 * time.Millisecond
+	tests := []struct {
+		name        string
+		parallel    bool
+		minDuration time.Duration
+		maxDuration time.Duration
+	}{
+		{name: "sequential takes the sum", minDuration: 2 * startTime, maxDuration: time.Hour},
+		{
+			name:        "parallel takes the max",
+			parallel:    true,
+			minDuration: startTime,
+			maxDuration: 2*startTime - 50*time.Millisecond,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ev := &events{}
+			m := NewManager(LifecycleConfig{ParallelStartup: tt.parallel})
+			m.Register(&fakeService{name: "a", startTime: startTime, events: ev})
+			m.Register(&fakeService{name: "b", startTime: startTime, events: ev})
+
+			begin := time.Now()
+			if err := m.Start(context.Background()); err != nil {
+				t.Fatalf("Start() error = %v", err)
+			}
+			elapsed := time.Since(begin)
+			if elapsed < tt.minDuration || elapsed > tt.maxDuration {
+				t.Errorf("Start() took %v, want between %v and %v", elapsed, tt.minDuration, tt.maxDuration)
+			}
+			if m.State() != StateRunning {
+				t.Errorf("state = %s, want running", m.State())
+			}
+
+			// Shutdown keeps reverse registration order
+			if err := m.Stop(context.Background()); err != nil {
+				t.Fatalf("Stop() error = %v", err)
+			}
+			if got := ev.String(); !strings.HasSuffix(got, "stop:b stop:a") {
+				t.Errorf("events = %q, want them to end with stop:b stop:a", got)
+			}
+		})
+	}
+}
+
+func TestManagerParallelStartupFailure(t *testing.T) {
+	ev := &events{}
+	m := NewManager(LifecycleConfig{ParallelStartup: true})
+	m.Register(&fakeService{name: "a", events: ev})
+	m.Register(&fakeService{name: "b", startTime: 10 * time.Millisecond, startErr: errors.New("boom"), events: ev})
+	m.Register(&fakeService{name: "c", startTime: time.Hour, events: ev})
+
+	begin := time.Now()
+	err := m.Start(context.Background())
+	if err == nil || !strings.Contains(err.Error(), "service b failed to start: boom") {
+		t.Fatalf("Start() error = %v, want b's start error", err)
+	}
+	// c sees its start context cancelled instead of blocking for an hour
+	if elapsed := time.Since(begin); elapsed > time.Second {
+		t.Errorf("Start() took %v after a failure", elapsed)
+	}
+	if got := ev.String(); got != "start:a stop:a" {
+		t.Errorf("events = %q, want %q", got, "start:a stop:a")
+	}
+	if m.State() != StateError {
+		t.Errorf("state = %s, want error", m.State())
+	}
+}
+
+// blockingStop is a fakeService whose Stop blocks until release is closed,
+// or until its context is done if honorCtx is set.
+type blockingStop struct {
+	*fakeService
+	release  chan struct{}
+	honorCtx bool
+}
+
+func (s blockingStop) Stop(ctx context.Context) error {
+	done := ctx.Done()
+	if !s.honorCtx {
+		done = nil
+	}
+	select {
+	case <-s.release:
+	case <-done:
+	}
+	return s.fakeService.Stop(ctx)
+}
+
+func TestManagerStopTimeout(t *testing.T) {
+	const (
+		shutdownTimeout = 100 * time.Millisecond
+		gracePeriod     = 100 * time.Millisecond
+	)
+	tests := []struct {
+		name        string
+		honorCtx    bool
+		release     bool
+		wantEvents  string
+		wantPending string
+		minDuration time.Duration
+		maxDuration time.Duration
+	}{
+		{
+			name:        "stops in time",
+			release:     true,
+			wantEvents:  "stop:last stop:stuck stop:first",
+			maxDuration: shutdownTimeout,
+		},
+		{
+			name:        "returns on cancellation",
+			honorCtx:    true,
+			wantEvents:  "stop:last stop:stuck stop:first",
+			minDuration: shutdownTimeout,
+			maxDuration: shutdownTimeout + gracePeriod,
+		},
+		{
+			name:        "stuck",
+			wantEvents:  "stop:last",
+			wantPending: "stuck, first",
+			minDuration: shutdownTimeout + gracePeriod,
+			maxDuration: shutdownTimeout + gracePeriod + 100*time.Millisecond,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ev := &events{}
+			release := make(chan struct{})
+			if tt.release {
+				close(release)
+			} else {
+				// Let the stuck service return once the test is over
+				t.Cleanup(func() { close(release) })
+			}
+			m := NewManager(LifecycleConfig{ShutdownTimeout: shutdownTimeout, GracePeriod: gracePeriod})
+			m.Register(&fakeService{name: "first", events: ev})
+			stuck := &fakeService{name: "stuck", events: ev}
+			m.Register(blockingStop{fakeService: stuck, release: release, honorCtx: tt.honorCtx})
+			m.Register(&fakeService{name: "last", events: ev})
+			if err := m.Start(context.Background()); err != nil {
+				t.Fatalf("Start() error = %v", err)
+			}
+
+			begin := time.Now()
+			err := m.Stop(context.Background())
+			elapsed := time.Since(begin)
+			if elapsed < tt.minDuration || elapsed > tt.maxDuration {
+				t.Errorf("Stop() took %v, want between %v and %v", elapsed, tt.minDuration, tt.maxDuration)
+			}
+			if got := ev.String(); !strings.HasSuffix(got, tt.wantEvents) {
+				t.Errorf("events = %q, want them to end with %q", got, tt.wantEvents)
+			}
+
+			if tt.wantPending == "" {
+				if err != nil {
+					t.Fatalf("Stop() error = %v", err)
+				}
+				if m.State() != StateStopped {
+					t.Errorf("state = %s, want stopped", m.State())
+				}
+			} else {
+				if !errors.Is(err, ErrShutdownTimeout) {
+					t.Fatalf("Stop() error = %v, want ErrShutdownTimeout", err)
+				}
+				if want := "not stopped: " + tt.wantPending; !strings.HasSuffix(err.Error(), want) {
+					t.Errorf("Stop() error = %q, want it to end with %q", err, want)
+				}
+				if m.State() != StateError {
+					t.Errorf("state = %s, want error", m.State())
+				}
+			}
+		})
+	}
+}
+
+// failingStop is a fakeService whose Stop fails.
+type failingStop struct {
+	*fakeService
+}
+
+func (s failingStop) Stop(ctx context.Context) error {
+	_ = s.fakeService.Stop(ctx)
+	return errors.New("stuck")
+}
+
+func TestManagerStartRollback(t *testing.T) {
+	startErr := errors.New("boom")
+	tests := []struct {
+		name       string
+		firstStop  func(*fakeService) Service
+		wantEvents string
+		wantErrs   []string
+	}{
+		{
+			name:       "first service stopped",
+			firstStop:  func(s *fakeService) Service { return s },
+			wantEvents: "start:first stop:first",
+			wantErrs:   []string{"service second failed to start: boom"},
+		},
+		{
+			name:       "stop error joined",
+			firstStop:  func(s *fakeService) Service { return failingStop{s} },
+			wantEvents: "start:first stop:first",
+			wantErrs:   []string{"service second failed to start: boom", "service first failed to stop: stuck"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ev := &events{}
+			m := NewManager(LifecycleConfig{})
+			m.Register(tt.firstStop(&fakeService{name: "first", events: ev}))
+			m.Register(&fakeService{name: "second", startErr: startErr, events: ev})
+			m.Register(&fakeService{name: "third", events: ev})
+
+			err := m.Start(context.Background())
+			if !errors.Is(err, startErr) {
+				t.Fatalf("Start() error = %v, want it to wrap the start error", err)
+			}
+			for _, want := range tt.wantErrs {
+				if !strings.Contains(err.Error(), want) {
+					t.Errorf("Start() error = %q, want it to contain %q", err, want)
+				}
+			}
+			if got := ev.String(); got != tt.wantEvents {
+				t.Errorf("events = %q, want %q", got, tt.wantEvents)
+			}
+			if m.State() != StateError {
+				t.Errorf("state = %s, want error", m.State())
+			}
+		})
+	}
+}
@@ -0,0 +1,147 @@
+package lifecycle
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/ssgohq/goten-core/metric"
+)
+
+var (
+	lifecycleMetricsOnce sync.Once
+	// stateGauge holds the manager's current State as its numeric value.
+	stateGauge *metric.GaugeVec
+	// healthGauge holds each component's status, see healthValue.
+	healthGauge *metric.GaugeVec
+)
+
+func initLifecycleMetrics() {
+	lifecycleMetricsOnce.Do(func() {
+		stateGauge = metric.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: "goten",
+			Subsystem: "lifecycle",
+			Name:      "state",
+			Help:      "Current lifecycle state: 0 idle, 1 starting, 2 running, 3 stopping, 4 stopped, 5 error",
+		}, []string{"name"})
+		healthGauge = metric.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: "goten",
+			Subsystem: "lifecycle",
+			Name:      "health_status",
+			Help:      "Health status of each component: 1 up, 0.5 degraded, 0 down",
+		}, []string{"name", "component"})
+	})
+}
+
+// healthValue encodes a health status as a gauge value, so that alerts can
+// use thresholds such as "< 1" for degraded or down.
+func healthValue(status HealthStatus) float64 {
+	switch status {
+	case HealthStatusUp:
+		return 1
+	case HealthStatusDegraded:
+		return 0.5
+	default:
+		return 0
+	}
+}
+
+// MetricsCollector exports the state of a Manager and the component health
+// of a HealthManager as Prometheus gauges.
+type MetricsCollector struct {
+	manager  *Manager
+	health   *HealthManager
+	name     string
+	interval time.Duration
+	cancel   context.CancelFunc
+}
+
+// MetricsConfig configures the metrics collector.
+type MetricsConfig struct {
+	// Name is a label used to identify this application in metrics.
+	// If empty, defaults to "default".
+	Name string
+
+	// CollectInterval is the interval between collections.
+	// Default is 15 seconds.
+	CollectInterval time.Duration
+}
+
+// NewMetricsCollector creates a new lifecycle metrics collector. Either
+// manager or health may be nil, in which case its gauge is not exported.
+// Call Start() to begin collecting metrics, and Stop() to stop.
+//
+// Example:
+//
+//	collector := lifecycle.NewMetricsCollector(manager, health, &lifecycle.MetricsConfig{
+//	    Name: "user-api",
+//	})
+//	collector.Start()
+//	defer collector.Stop()
+func NewMetricsCollector(manager *Manager, health *HealthManager, cfg *MetricsConfig) *MetricsCollector {
+	if cfg == nil {
+		cfg = &MetricsConfig{}
+	}
+
+	name := cfg.Name
+	if name == "" {
+		name = "default"
+	}
+
+	interval := cfg.CollectInterval
+	if interval == 0 {
+		interval = 15 * time.Second
+	}
+
+	initLifecycleMetrics()
+	return &MetricsCollector{
+		manager:  manager,
+		health:   health,
+		name:     name,
+		interval: interval,
+	}
+}
+
+// Start begins collecting metrics at the configured interval.
+func (c *MetricsCollector) Start() {
+	ctx, cancel := context.WithCancel(context.Background())
+	c.cancel = cancel
+
+	// Collect initial stats
+	c.collect(ctx)
+
+	// Start background collection
+	go func() {
+		ticker := time.NewTicker(c.interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				c.collect(ctx)
+			}
+		}
+	}()
+}
+
+// Stop stops the metrics collection.
+func (c *MetricsCollector) Stop() {
+	if c.cancel != nil {
+		c.cancel()
+	}
+}
+
+func (c *MetricsCollector) collect(ctx context.Context) {
+	if c.manager != nil {
+		stateGauge.Set(float64(c.manager.State()), c.name)
+	}
+	if c.health != nil {
+		for component, h := range c.health.Check(ctx).Components {
+			healthGauge.Set(healthValue(h.Status), c.name, component)
+		}
+	}
+}
@@ -0,0 +1,102 @@
+package lifecycle
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// gaugeValue returns the value of the gauge series of name with labels from
+// the default registry, and whether it exists.
+func gaugeValue(t *testing.T, name string, labels map[string]string) (float64, bool) {
+	t.Helper()
+	families, err := prometheus.DefaultGatherer.Gather()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, mf := range families {
+		if mf.GetName() != name {
+			continue
+		}
+	series:
+		for _, m := range mf.GetMetric() {
+			got := make(map[string]string, len(m.GetLabel()))
+			for _, lp := range m.GetLabel() {
+				got[lp.GetName()] = lp.GetValue()
+			}
+			for k, v := range labels {
+				if got[k] != v {
+					continue series
+				}
+			}
+			return m.GetGauge().GetValue(), true
+		}
+	}
+	return 0, false
+}
+
+func TestMetricsCollectorHealth(t *testing.T) {
+	h := NewHealthManager()
+	h.Register("up", func(context.Context) HealthStatus { return HealthStatusUp })
+	h.Register("degraded", func(context.Context) HealthStatus { return HealthStatusDegraded })
+	h.Register("down", func(context.Context) HealthStatus { return HealthStatusDown })
+
+	c := NewMetricsCollector(nil, h, &MetricsConfig{Name: "health-test"})
+	c.Start()
+	defer c.Stop()
+
+	tests := []struct {
+		component string
+		want      float64
+	}{
+		{component: "up", want: 1},
+		{component: "degraded", want: 0.5},
+		{component: "down", want: 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.component, func(t *testing.T) {
+			got, ok := gaugeValue(t, "goten_lifecycle_health_status",
+				map[string]string{"name": "health-test", "component": tt.component})
+			if !ok || got != tt.want {
+				t.Errorf("health_status = %v (exported %v), want %v", got, ok, tt.want)
+			}
+		})
+	}
+	if _, ok := gaugeValue(t, "goten_lifecycle_state", map[string]string{"name": "health-test"}); ok {
+		t.Error("state exported without a manager")
+	}
+}
+
+func TestMetricsCollectorState(t *testing.T) {
+	m := NewManager(LifecycleConfig{})
+	c := NewMetricsCollector(m, nil, &MetricsConfig{Name: "state-test", CollectInterval: 5 * time.Millisecond})
+	c.Start()
+	defer c.Stop()
+
+	waitState := func(want State) {
+		t.Helper()
+		deadline := time.Now().Add(time.Second)
+		for {
+			got, ok := gaugeValue(t, "goten_lifecycle_state", map[string]string{"name": "state-test"})
+			if ok && got == float64(want) {
+				return
+			}
+			if time.Now().After(deadline) {
+				t.Fatalf("state gauge = %v (exported %v), want %v (%s)", got, ok, float64(want), want)
+			}
+			time.Sleep(5 * time.Millisecond)
+		}
+	}
+
+	waitState(StateIdle)
+	if err := m.Start(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	waitState(StateRunning)
+	if err := m.Stop(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	waitState(StateStopped)
+}
@@ -0,0 +1,36 @@
+package lifecycle
+
+import (
+	"context"
+	"time"
+)
+
+// PingHealthCheck returns a health check that calls ping and reports its
+// latency and, on failure, its error as details. The component is down when
+// ping fails, degraded when it succeeds slower than slow (if slow > 0) and
+// up otherwise.
+//
+// Example:
+//
+//	healthMgr.RegisterDetailed("postgres", lifecycle.PingHealthCheck(pool.Ping, 200*time.Millisecond))
+func PingHealthCheck(ping func(ctx context.Context) error, slow time.Duration) DetailedHealthCheck {
+	return func(ctx context.Context) ComponentHealth {
+		start := time.Now()
+		err := ping(ctx)
+		latency := time.Since(start)
+
+		details := map[string]any{
+			"latency":    latency.String(),
+			"latency_ms": latency.Milliseconds(),
+		}
+		if err != nil {
+			details["error"] = err.Error()
+			return ComponentHealth{Status: HealthStatusDown, Details: details}
+		}
+		if slow > 0 && latency > slow {
+			details["slow_threshold"] = slow.String()
+			return ComponentHealth{Status: HealthStatusDegraded, Details: details}
+		}
+		return ComponentHealth{Status: HealthStatusUp, Details: details}
+	}
+}
@@ -0,0 +1,74 @@
+package lifecycle
+
+import (
+	"context"
+	"runtime"
+	"time"
+)
+
+// RuntimeComponent is the component name of the runtime health check.
+const RuntimeComponent = "runtime"
+
+// RuntimeHealthConfig configures the thresholds of the runtime health check.
+// A zero threshold is disabled.
+type RuntimeHealthConfig struct {
+	// MaxGoroutines degrades the component above this goroutine count.
+	MaxGoroutines int `yaml:"maxGoroutines,omitempty" json:"maxGoroutines,omitempty"`
+	// MaxHeapInUse degrades the component above this many heap bytes in use.
+	MaxHeapInUse uint64 `yaml:"maxHeapInUse,omitempty" json:"maxHeapInUse,omitempty"`
+	// MaxGCPause degrades the component when the last GC pause exceeds it.
+	MaxGCPause time.Duration `yaml:"maxGcPause,omitempty" json:"maxGcPause,omitempty"`
+}
+
+// WithRuntimeHealth registers RuntimeHealthCheck as the "runtime" component.
+//
+// Example:
+//
+//	healthMgr := lifecycle.NewHealthManager(lifecycle.WithRuntimeHealth(lifecycle.RuntimeHealthConfig{
+//	    MaxGoroutines: 10000,
+//	}))
+func WithRuntimeHealth(cfg RuntimeHealthConfig) HealthOption {
+	return func(h *HealthManager) {
+		h.checks[RuntimeComponent] = &healthEntry{check: RuntimeHealthCheck(cfg)}
+	}
+}
+
+// RuntimeHealthCheck returns a health check reporting goroutine count, heap
+// in use and the last GC pause as details. The component is always up unless
+// a configured threshold is exceeded, in which case it is degraded.
+//
+// Example:
+//
+//	healthMgr.RegisterDetailed("runtime", lifecycle.RuntimeHealthCheck(lifecycle.RuntimeHealthConfig{
+//	    MaxGoroutines: 10000,
+//	}))
+func RuntimeHealthCheck(cfg RuntimeHealthConfig) DetailedHealthCheck {
+	return func(_ context.Context) ComponentHealth {
+		var ms runtime.MemStats
+		runtime.ReadMemStats(&ms)
+
+		goroutines := runtime.NumGoroutine()
+		var lastPause time.Duration
+		if ms.NumGC > 0 {
+			lastPause = time.Duration(ms.PauseNs[(ms.NumGC+255)%256])
+		}
+
+		status := HealthStatusUp
+		if (cfg.MaxGoroutines > 0 && goroutines > cfg.MaxGoroutines) ||
+			(cfg.MaxHeapInUse > 0 && ms.HeapInuse > cfg.MaxHeapInUse) ||
+			(cfg.MaxGCPause > 0 && lastPause > cfg.MaxGCPause) {
+			status = HealthStatusDegraded
+		}
+
+		return ComponentHealth{
+			Status: status,
+			Details: map[string]any{
+				"goroutines":       goroutines,
+				"heap_inuse":       ms.HeapInuse,
+				"gc_count":         ms.NumGC,
+				"last_gc_pause":    lastPause.String(),
+				"last_gc_pause_ns": lastPause.Nanoseconds(),
+			},
+		}
+	}
+}
@@ -0,0 +1,59 @@
+package lifecycle
+
+import (
+	"context"
+	"runtime"
+	"testing"
+	"time"
+)
+
+func TestRuntimeHealthCheck(t *testing.T) {
+	tests := []struct {
+		name       string
+		cfg        RuntimeHealthConfig
+		wantStatus HealthStatus
+	}{
+		{name: "no thresholds", cfg: RuntimeHealthConfig{}, wantStatus: HealthStatusUp},
+		{
+			name:       "below thresholds",
+			cfg:        RuntimeHealthConfig{MaxGoroutines: 1 << 20, MaxHeapInUse: 1 << 40, MaxGCPause: time.Hour},
+			wantStatus: HealthStatusUp,
+		},
+		{name: "goroutines above", cfg: RuntimeHealthConfig{MaxGoroutines: 1}, wantStatus: HealthStatusDegraded},
+		{name: "heap above", cfg: RuntimeHealthConfig{MaxHeapInUse: 1}, wantStatus: HealthStatusDegraded},
+		{name: "gc pause above", cfg: RuntimeHealthConfig{MaxGCPause: time.Nanosecond}, wantStatus: HealthStatusDegraded},
+	}
+
+	// Make sure there is a GC pause to report
+	runtime.GC()
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := RuntimeHealthCheck(tt.cfg)(context.Background())
+			if got.Status != tt.wantStatus {
+				t.Errorf("status = %s, want %s (details %v)", got.Status, tt.wantStatus, got.Details)
+			}
+			for _, key := range []string{"goroutines", "heap_inuse", "gc_count", "last_gc_pause", "last_gc_pause_ns"} {
+				if _, ok := got.Details[key]; !ok {
+					t.Errorf("details missing %q: %v", key, got.Details)
+				}
+			}
+			if n, _ := got.Details["goroutines"].(int); n < 1 {
+				t.Errorf("goroutines = %v, want at least 1", got.Details["goroutines"])
+			}
+		})
+	}
+}
+
+func TestWithRuntimeHealth(t *testing.T) {
+	h := NewHealthManager(WithRuntimeHealth(RuntimeHealthConfig{MaxGoroutines: 1}))
+
+	resp := h.Check(context.Background())
+	component, ok := resp.Components[RuntimeComponent]
+	if !ok {
+		t.Fatalf("components = %v, want %q", resp.Components, RuntimeComponent)
+	}
+	if component.Status != HealthStatusDegraded || resp.Status != HealthStatusDegraded {
+		t.Errorf("status = %s (overall %s), want degraded", component.Status, resp.Status)
+	}
+}
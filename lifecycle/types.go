@@ -8,6 +8,9 @@ import (
 )
 
 // Service represents a managed service with start/stop lifecycle.
+// The context the manager passes to Start, Stop and Drain carries a logger
+// tagged with the manager and service names: log through logx.Ctx(ctx) or
+// logx.FromContext(ctx) to have those fields added.
 type Service interface {
 	// Name returns the service name for logging and identification.
 	Name() string
@@ -17,6 +20,23 @@ type Service interface {
 	Stop(ctx context.Context) error
 }
 
+// Validator is an optional interface for services that can check their
+// configuration without starting. It is used for dry-run validation.
+type Validator interface {
+	// Validate returns an error if the service cannot be started as configured.
+	// It must not bind ports or open long-lived connections.
+	Validate(ctx context.Context) error
+}
+
+// Drainer is an optional interface for services that accept work, such as
+// worker pools, schedulers and queue consumers. During shutdown the manager
+// calls Drain on every service before stopping any of them, so that all
+// intake stops at once while in-flight work keeps running until Stop.
+type Drainer interface {
+	// Drain stops accepting new work. It must not wait for in-flight work.
+	Drain(ctx context.Context) error
+}
+
 // HookPhase defines when a hook should be executed.
 type HookPhase int
 
@@ -44,12 +64,22 @@ type Hook struct {
 
 // LifecycleConfig configures the lifecycle manager.
 type LifecycleConfig struct {
+	// Name identifies the manager in its log lines, as the "manager" field,
+	// to tell apart several managers in one process.
+	// Default: "default"
+	Name string `yaml:"name,omitempty" json:"name,omitempty"`
 	// ShutdownTimeout is the maximum time to wait for graceful shutdown.
 	// Default: 30 seconds.
 	ShutdownTimeout time.Duration `yaml:"shutdownTimeout,omitempty" json:"shutdownTimeout,omitempty"`
-	// GracePeriod is the time to wait before forceful shutdown after timeout.
+	// GracePeriod is how long Stop keeps waiting for services still stopping
+	// after ShutdownTimeout, before it reports them and gives up.
 	// Default: 5 seconds.
 	GracePeriod time.Duration `yaml:"gracePeriod,omitempty" json:"gracePeriod,omitempty"`
+	// ParallelStartup starts all services concurrently instead of in
+	// registration order. Use it only when services do not depend on each
+	// other. Shutdown still runs in reverse registration order.
+	// Default: false.
+	ParallelStartup bool `yaml:"parallelStartup,omitempty" json:"parallelStartup,omitempty"`
 }
 
 // State represents the current state of a service.
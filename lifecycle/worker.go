@@ -0,0 +1,112 @@
+package lifecycle
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+)
+
+// ErrWorkerDraining is returned by WorkerPool.Submit once the pool has
+// started draining and no longer accepts jobs.
+var ErrWorkerDraining = errors.New("lifecycle: worker pool is draining")
+
+// WorkerPool is a Service that runs submitted jobs with bounded concurrency.
+// On shutdown it rejects new jobs as soon as it is drained and lets in-flight
+// jobs finish within the stop deadline; jobs still running when the deadline
+// expires see their context cancelled.
+type WorkerPool struct {
+	name     string
+	sem      chan struct{}
+	wg       sync.WaitGroup
+	mu       sync.RWMutex
+	draining bool
+	ctx      context.Context
+	cancel   context.CancelFunc
+}
+
+// NewWorkerPool creates a worker pool running at most concurrency jobs at a
+// time. A concurrency <= 0 is treated as 1.
+//
+// Example:
+//
+//	pool := lifecycle.NewWorkerPool("mailer", 8)
+//	application.AddService(pool)
+//	err := pool.Submit(ctx, func(ctx context.Context) { send(ctx, msg) })
+func NewWorkerPool(name string, concurrency int) *WorkerPool {
+	if concurrency <= 0 {
+		concurrency = 1
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	return &WorkerPool{
+		name:   name,
+		sem:    make(chan struct{}, concurrency),
+		ctx:    ctx,
+		cancel: cancel,
+	}
+}
+
+// Name returns the service name.
+func (p *WorkerPool) Name() string {
+	return p.name
+}
+
+// Start is a no-op; the pool accepts jobs as soon as it is created.
+func (p *WorkerPool) Start(_ context.Context) error {
+	return nil
+}
+
+// Submit runs job in the pool, waiting for a free slot until ctx is done.
+// It returns ErrWorkerDraining once the pool is draining.
+func (p *WorkerPool) Submit(ctx context.Context, job func(ctx context.Context)) error {
+	p.mu.RLock()
+	if p.draining {
+		p.mu.RUnlock()
+		return ErrWorkerDraining
+	}
+	p.wg.Add(1)
+	p.mu.RUnlock()
+
+	select {
+	case p.sem <- struct{}{}:
+	case <-ctx.Done():
+		p.wg.Done()
+		return ctx.Err()
+	}
+
+	go func() {
+		defer p.wg.Done()
+		defer func() { <-p.sem }()
+		job(p.ctx)
+	}()
+	return nil
+}
+
+// Drain stops accepting new jobs. Jobs already submitted keep running.
+func (p *WorkerPool) Drain(_ context.Context) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.draining = true
+	return nil
+}
+
+// Stop drains the pool and waits for in-flight jobs. If ctx is done first,
+// the jobs' context is cancelled and an error is returned.
+func (p *WorkerPool) Stop(ctx context.Context) error {
+	_ = p.Drain(ctx)
+
+	done := make(chan struct{})
+	go func() {
+		p.wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		p.cancel()
+		return nil
+	case <-ctx.Done():
+		p.cancel()
+		return fmt.Errorf("worker pool %s: in-flight jobs did not finish: %w", p.name, ctx.Err())
+	}
+}
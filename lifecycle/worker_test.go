@@ -0,0 +1,96 @@
+package lifecycle
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// stopFunc is a Service running fn when stopped.
+type stopFunc struct {
+	fn func(ctx context.Context) error
+}
+
+func (s stopFunc) Name() string                   { return "probe" }
+func (s stopFunc) Start(context.Context) error    { return nil }
+func (s stopFunc) Stop(ctx context.Context) error { return s.fn(ctx) }
+
+func TestWorkerPoolShutdown(t *testing.T) {
+	tests := []struct {
+		name          string
+		jobTime       time.Duration
+		stopTimeout   time.Duration
+		wantStopErr   bool
+		wantCompleted int32
+		wantCancelled int32
+	}{
+		{name: "in-flight jobs finish", jobTime: 50 * time.Millisecond, stopTimeout: time.Second, wantCompleted: 2},
+		{
+			name:          "deadline cancels stragglers",
+			jobTime:       time.Hour,
+			stopTimeout:   50 * time.Millisecond,
+			wantStopErr:   true,
+			wantCancelled: 2,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var completed, cancelled atomic.Int32
+			job := func(ctx context.Context) {
+				select {
+				case <-time.After(tt.jobTime):
+					completed.Add(1)
+				case <-ctx.Done():
+					cancelled.Add(1)
+				}
+			}
+
+			pool := NewWorkerPool("jobs", 2)
+			var submitErr error
+			var inFlight int32
+			m := NewManager(LifecycleConfig{ShutdownTimeout: tt.stopTimeout, GracePeriod: time.Second})
+			m.Register(pool)
+			// Stopped first, after every service drained but before the pool stops
+			m.Register(stopFunc{fn: func(ctx context.Context) error {
+				inFlight = 2 - completed.Load() - cancelled.Load()
+				submitErr = pool.Submit(ctx, job)
+				return nil
+			}})
+			if err := m.Start(context.Background()); err != nil {
+				t.Fatalf("Start() error = %v", err)
+			}
+			for i := 0; i < 2; i++ {
+				if err := pool.Submit(context.Background(), job); err != nil {
+					t.Fatalf("Submit() error = %v", err)
+				}
+			}
+
+			err := m.Stop(context.Background())
+			if (err != nil) != tt.wantStopErr {
+				t.Errorf("Stop() error = %v, want error %v", err, tt.wantStopErr)
+			}
+			if !errors.Is(submitErr, ErrWorkerDraining) {
+				t.Errorf("Submit() during shutdown = %v, want ErrWorkerDraining", submitErr)
+			}
+			if inFlight != 2 {
+				t.Errorf("jobs in flight when draining = %d, want 2", inFlight)
+			}
+			// Cancelled jobs return shortly after Stop gives up on them
+			for deadline := time.Now().Add(time.Second); completed.Load()+cancelled.Load() < 2; {
+				if time.Now().After(deadline) {
+					t.Fatal("jobs did not return after Stop")
+				}
+				time.Sleep(time.Millisecond)
+			}
+			if got := completed.Load(); got != tt.wantCompleted {
+				t.Errorf("completed = %d, want %d", got, tt.wantCompleted)
+			}
+			if got := cancelled.Load(); got != tt.wantCancelled {
+				t.Errorf("cancelled = %d, want %d", got, tt.wantCancelled)
+			}
+		})
+	}
+}
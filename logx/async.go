@@ -0,0 +1,179 @@
+package logx
+
+import (
+	"errors"
+	"sync"
+	"sync/atomic"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// AsyncConfig configures buffered, background-flushed log output.
+type AsyncConfig struct {
+	// QueueSize is the maximum number of log entries waiting to be written.
+	// Default: 4096
+	QueueSize int `yaml:"queueSize,omitempty" json:"queueSize,omitempty"`
+
+	// DropWhenFull drops entries instead of blocking the caller when the
+	// queue is full. Dropped entries are counted in
+	// goten_log_async_dropped_total.
+	DropWhenFull bool `yaml:"dropWhenFull,omitempty" json:"dropWhenFull,omitempty"`
+}
+
+// Validate checks the async configuration for invalid values.
+func (c *AsyncConfig) Validate() error {
+	if c.QueueSize < 0 {
+		return errors.New("logx: async queueSize must be >= 0")
+	}
+	return nil
+}
+
+var (
+	asyncMetricsOnce sync.Once
+	asyncCurrent     atomic.Pointer[asyncWriter]
+	asyncDropped     atomic.Uint64
+)
+
+// registerAsyncMetrics exposes the queue depth and drop count of the
+// current async writer. logx cannot use the metric package, which logs
+// through logx, so the collectors are registered directly.
+func registerAsyncMetrics() {
+	asyncMetricsOnce.Do(func() {
+		depth := prometheus.NewGaugeFunc(prometheus.GaugeOpts{
+			Namespace: "goten",
+			Subsystem: "log_async",
+			Name:      "queue_depth",
+			Help:      "Number of log entries waiting to be written",
+		}, func() float64 {
+			if w := asyncCurrent.Load(); w != nil {
+				return float64(len(w.queue))
+			}
+			return 0
+		})
+		dropped := prometheus.NewCounterFunc(prometheus.CounterOpts{
+			Namespace: "goten",
+			Subsystem: "log_async",
+			Name:      "dropped_total",
+			Help:      "Total number of log entries dropped because the queue was full",
+		}, func() float64 {
+			return float64(asyncDropped.Load())
+		})
+		for _, c := range []prometheus.Collector{depth, dropped} {
+			if err := prometheus.Register(c); err != nil {
+				Warnw("Failed to register async log metric", "error", err)
+			}
+		}
+	})
+}
+
+// asyncEntry is either an encoded log entry or, when flushed is set, a
+// marker that Sync uses to wait for the entries queued before it.
+type asyncEntry struct {
+	data    []byte
+	flushed chan struct{}
+}
+
+// asyncWriter is a zapcore.WriteSyncer that hands writes to a background
+// goroutine through a bounded queue.
+type asyncWriter struct {
+	out   zapcore.WriteSyncer
+	queue chan asyncEntry
+	drop  bool
+	done  chan struct{}
+	once  sync.Once
+}
+
+func newAsyncWriter(out zapcore.WriteSyncer, cfg AsyncConfig) *asyncWriter {
+	size := cfg.QueueSize
+	if size == 0 {
+		size = 4096
+	}
+	w := &asyncWriter{
+		out:   out,
+		queue: make(chan asyncEntry, size),
+		drop:  cfg.DropWhenFull,
+		done:  make(chan struct{}),
+	}
+	go w.run()
+	return w
+}
+
+func (w *asyncWriter) run() {
+	for {
+		select {
+		case e := <-w.queue:
+			w.handle(e)
+		case <-w.done:
+			// Flush whatever is left before exiting
+			for {
+				select {
+				case e := <-w.queue:
+					w.handle(e)
+				default:
+					return
+				}
+			}
+		}
+	}
+}
+
+func (w *asyncWriter) handle(e asyncEntry) {
+	if e.flushed != nil {
+		close(e.flushed)
+		return
+	}
+	_, _ = w.out.Write(e.data)
+}
+
+// Write queues a copy of p; zap reuses its buffers after Write returns.
+func (w *asyncWriter) Write(p []byte) (int, error) {
+	e := asyncEntry{data: append([]byte(nil), p...)}
+	if w.drop {
+		select {
+		case w.queue <- e:
+		default:
+			asyncDropped.Add(1)
+		}
+		return len(p), nil
+	}
+	select {
+	case w.queue <- e:
+	case <-w.done:
+		return w.out.Write(p)
+	}
+	return len(p), nil
+}
+
+// Sync waits until every entry queued before the call has been written,
+// then syncs the underlying output.
+func (w *asyncWriter) Sync() error {
+	flushed := make(chan struct{})
+	select {
+	case w.queue <- asyncEntry{flushed: flushed}:
+		select {
+		case <-flushed:
+		case <-w.done:
+		}
+	case <-w.done:
+	}
+	return w.out.Sync()
+}
+
+// close flushes queued entries and stops the background goroutine.
+func (w *asyncWriter) close() {
+	_ = w.Sync()
+	w.once.Do(func() { close(w.done) })
+}
+
+// asyncOption returns a zap option that replaces the core built from zapCfg
+// (whose output paths must be empty) with one writing to out through an
+// async writer, plus the writer itself.
+func (c *AsyncConfig) asyncOption(zapCfg zap.Config, out zapcore.WriteSyncer) (zap.Option, *asyncWriter) {
+	w := newAsyncWriter(out, *c)
+	core := zapcore.NewCore(newEncoder(zapCfg), w, zapCfg.Level)
+	return zap.WrapCore(func(zapcore.Core) zapcore.Core {
+		return core
+	}), w
+}
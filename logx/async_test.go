@@ -0,0 +1,143 @@
+package logx
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+)
+
+// restoreGlobal puts back the global logger when the test ends and stops
+// any async writer the test installed.
+func restoreGlobal(tb testing.TB) {
+	tb.Helper()
+	previous := L()
+	tb.Cleanup(func() {
+		if w := asyncCurrent.Swap(nil); w != nil {
+			w.close()
+		}
+		if w := rotationCurrent.Swap(nil); w != nil {
+			_ = w.Close()
+		}
+		ringCurrent.Store(nil)
+		SetLogger(previous)
+	})
+}
+
+func TestInitAsync(t *testing.T) {
+	tests := []struct {
+		name  string
+		async *AsyncConfig
+	}{
+		{name: "sync", async: nil},
+		{name: "async blocking", async: &AsyncConfig{QueueSize: 8}},
+		{name: "async default queue", async: &AsyncConfig{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			restoreGlobal(t)
+			path := filepath.Join(t.TempDir(), "app.log")
+			if err := Init(Config{OutputPaths: []string{path}, Async: tt.async}); err != nil {
+				t.Fatalf("Init() error = %v", err)
+			}
+
+			const n = 100
+			for i := 0; i < n; i++ {
+				Infow("entry", "i", i)
+			}
+			if err := Sync(); err != nil {
+				t.Fatalf("Sync() error = %v", err)
+			}
+
+			data, err := os.ReadFile(path)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := bytes.Count(data, []byte("\n")); got != n {
+				t.Errorf("lines after Sync = %d, want %d", got, n)
+			}
+		})
+	}
+}
+
+// slowWriter blocks every write until release is closed.
+type slowWriter struct {
+	release chan struct{}
+	mu      sync.Mutex
+	writes  int
+}
+
+func (w *slowWriter) Write(p []byte) (int, error) {
+	<-w.release
+	w.mu.Lock()
+	w.writes++
+	w.mu.Unlock()
+	return len(p), nil
+}
+
+func (w *slowWriter) Sync() error { return nil }
+
+func TestAsyncWriterDropWhenFull(t *testing.T) {
+	out := &slowWriter{release: make(chan struct{})}
+	w := newAsyncWriter(out, AsyncConfig{QueueSize: 1, DropWhenFull: true})
+	before := asyncDropped.Load()
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for i := 0; i < 10; i++ {
+			_, _ = w.Write([]byte("entry\n"))
+		}
+	}()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("Write blocked on a full queue with DropWhenFull")
+	}
+
+	// At most one entry is being written and one is queued.
+	if dropped := asyncDropped.Load() - before; dropped < 8 {
+		t.Errorf("dropped = %d, want at least 8", dropped)
+	}
+	close(out.release)
+	w.close()
+}
+
+// benchmarkInit points the global logger at a file in a temp dir.
+func benchmarkInit(b *testing.B, async *AsyncConfig) {
+	restoreGlobal(b)
+	path := filepath.Join(b.TempDir(), "bench.log")
+	if err := Init(Config{OutputPaths: []string{path}, Async: async}); err != nil {
+		b.Fatal(err)
+	}
+}
+
+func benchmarkLogging(b *testing.B) {
+	b.ReportAllocs()
+	b.ResetTimer()
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			Infow("HTTP request", "method", "GET", "path", "/users", "status", 200)
+		}
+	})
+	b.StopTimer()
+	_ = Sync()
+}
+
+func BenchmarkLogSync(b *testing.B) {
+	benchmarkInit(b, nil)
+	benchmarkLogging(b)
+}
+
+func BenchmarkLogAsync(b *testing.B) {
+	benchmarkInit(b, &AsyncConfig{})
+	benchmarkLogging(b)
+}
+
+func BenchmarkLogAsyncDrop(b *testing.B) {
+	benchmarkInit(b, &AsyncConfig{DropWhenFull: true})
+	benchmarkLogging(b)
+}
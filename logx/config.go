@@ -4,6 +4,7 @@
 package logx
 
 import (
+	"fmt"
 	"os"
 	"strings"
 
@@ -38,6 +39,32 @@ type Config struct {
 	// Default: ["stderr"]
 	ErrorOutputPaths []string `yaml:"errorOutputPaths,omitempty" json:"errorOutputPaths,omitempty"`
 
+	// TimeFormat is the encoding of the entry time: "iso8601", "rfc3339nano"
+	// or "epoch" (seconds as a float).
+	// Default: "iso8601"
+	TimeFormat string `yaml:"timeFormat,omitempty" json:"timeFormat,omitempty"`
+
+	// TimeKey, LevelKey, MessageKey and CallerKey rename the corresponding
+	// fields of each entry. Defaults: zap's, i.e. "ts", "level", "msg" and
+	// "caller" in production and "T", "L", "M" and "C" in development.
+	TimeKey    string `yaml:"timeKey,omitempty" json:"timeKey,omitempty"`
+	LevelKey   string `yaml:"levelKey,omitempty" json:"levelKey,omitempty"`
+	MessageKey string `yaml:"messageKey,omitempty" json:"messageKey,omitempty"`
+	CallerKey  string `yaml:"callerKey,omitempty" json:"callerKey,omitempty"`
+
+	// Rotation, when set, also writes logs to a size-rotated file alongside
+	// OutputPaths. Unlike OutputPaths, the file is rotated and pruned.
+	Rotation *RotationConfig `yaml:"rotation,omitempty" json:"rotation,omitempty"`
+
+	// Async, when set, writes OutputPaths through a bounded in-memory queue
+	// flushed by a background goroutine, so logging calls do not wait on I/O.
+	// Sync drains the queue. Rotation output stays synchronous.
+	Async *AsyncConfig `yaml:"async,omitempty" json:"async,omitempty"`
+
+	// RingBuffer, when set, also keeps the most recent entries in memory so
+	// that DumpRecent can write them out, e.g. after a panic.
+	RingBuffer *RingBufferConfig `yaml:"ringBuffer,omitempty" json:"ringBuffer,omitempty"`
+
 	// InitialFields are fields to add to every log entry.
 	InitialFields map[string]interface{} `yaml:"initialFields,omitempty" json:"initialFields,omitempty"`
 }
@@ -93,27 +120,44 @@ func ConfigFromEnv() Config {
 	return cfg
 }
 
-// toZapConfig converts Config to zap.Config.
-func (c *Config) toZapConfig() zap.Config {
-	level := zap.NewAtomicLevel()
+// Validate checks the configuration for invalid values.
+func (c *Config) Validate() error {
 	switch strings.ToLower(c.Level) {
-	case "debug":
-		level.SetLevel(zapcore.DebugLevel)
-	case "info":
-		level.SetLevel(zapcore.InfoLevel)
-	case "warn", "warning":
-		level.SetLevel(zapcore.WarnLevel)
-	case "error":
-		level.SetLevel(zapcore.ErrorLevel)
-	case "dpanic":
-		level.SetLevel(zapcore.DPanicLevel)
-	case "panic":
-		level.SetLevel(zapcore.PanicLevel)
-	case "fatal":
-		level.SetLevel(zapcore.FatalLevel)
+	case "", "debug", "info", "warn", "warning", "error", "dpanic", "panic", "fatal":
+	default:
+		return fmt.Errorf("logx: unknown level %q", c.Level)
+	}
+	switch c.Format {
+	case "", "json", "console":
+	default:
+		return fmt.Errorf("logx: unknown format %q (expected json or console)", c.Format)
+	}
+	switch strings.ToLower(c.TimeFormat) {
+	case "", "iso8601", "rfc3339nano", "epoch":
 	default:
-		level.SetLevel(zapcore.InfoLevel)
+		return fmt.Errorf("logx: unknown timeFormat %q (expected iso8601, rfc3339nano or epoch)", c.TimeFormat)
+	}
+	if c.Rotation != nil {
+		if err := c.Rotation.Validate(); err != nil {
+			return err
+		}
+	}
+	if c.Async != nil {
+		if err := c.Async.Validate(); err != nil {
+			return err
+		}
+	}
+	if c.RingBuffer != nil {
+		if err := c.RingBuffer.Validate(); err != nil {
+			return err
+		}
 	}
+	return nil
+}
+
+// toZapConfig converts Config to zap.Config.
+func (c *Config) toZapConfig() zap.Config {
+	level := zap.NewAtomicLevelAt(parseLevel(c.Level))
 
 	outputPaths := c.OutputPaths
 	if len(outputPaths) == 0 {
@@ -131,7 +175,7 @@ func (c *Config) toZapConfig() zap.Config {
 	} else {
 		encoderConfig = zap.NewProductionEncoderConfig()
 	}
-	encoderConfig.EncodeTime = zapcore.ISO8601TimeEncoder
+	c.applyEncoderConfig(&encoderConfig)
 
 	encoding := "json"
 	if c.Format == "console" {
@@ -150,3 +194,50 @@ func (c *Config) toZapConfig() zap.Config {
 		InitialFields:     c.InitialFields,
 	}
 }
+
+// applyEncoderConfig sets the time encoding and key names chosen in the config.
+func (c *Config) applyEncoderConfig(ec *zapcore.EncoderConfig) {
+	switch strings.ToLower(c.TimeFormat) {
+	case "rfc3339nano":
+		ec.EncodeTime = zapcore.RFC3339NanoTimeEncoder
+	case "epoch":
+		ec.EncodeTime = zapcore.EpochTimeEncoder
+	default:
+		ec.EncodeTime = zapcore.ISO8601TimeEncoder
+	}
+	if c.TimeKey != "" {
+		ec.TimeKey = c.TimeKey
+	}
+	if c.LevelKey != "" {
+		ec.LevelKey = c.LevelKey
+	}
+	if c.MessageKey != "" {
+		ec.MessageKey = c.MessageKey
+	}
+	if c.CallerKey != "" {
+		ec.CallerKey = c.CallerKey
+	}
+}
+
+// parseLevel converts a configured level name to a zapcore.Level,
+// falling back to info for unknown or empty names.
+func parseLevel(name string) zapcore.Level {
+	switch strings.ToLower(name) {
+	case "debug":
+		return zapcore.DebugLevel
+	case "info":
+		return zapcore.InfoLevel
+	case "warn", "warning":
+		return zapcore.WarnLevel
+	case "error":
+		return zapcore.ErrorLevel
+	case "dpanic":
+		return zapcore.DPanicLevel
+	case "panic":
+		return zapcore.PanicLevel
+	case "fatal":
+		return zapcore.FatalLevel
+	default:
+		return zapcore.InfoLevel
+	}
+}
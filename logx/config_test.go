@@ -0,0 +1,89 @@
+package logx
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"go.uber.org/zap/zapcore"
+)
+
+func TestConfigValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		cfg     Config
+		wantErr bool
+	}{
+		{name: "empty", cfg: Config{}},
+		{name: "valid", cfg: Config{Level: "WARN", Format: "console"}},
+		{name: "unknown level", cfg: Config{Level: "verbose"}, wantErr: true},
+		{name: "unknown format", cfg: Config{Format: "logfmt"}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.cfg.Validate(); (err != nil) != tt.wantErr {
+				t.Errorf("Validate() = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestConfigEncoder(t *testing.T) {
+	at := time.Date(2024, 5, 6, 7, 8, 9, 123456789, time.UTC)
+	tests := []struct {
+		name string
+		cfg  Config
+		want map[string]any
+	}{
+		{
+			name: "defaults",
+			cfg:  Config{},
+			want: map[string]any{"ts": "2024-05-06T07:08:09.123Z", "level": "info", "msg": "hello", "caller": "app/main.go:42"},
+		},
+		{
+			name: "rfc3339nano",
+			cfg:  Config{TimeFormat: "RFC3339Nano"},
+			want: map[string]any{"ts": "2024-05-06T07:08:09.123456789Z"},
+		},
+		{
+			name: "epoch",
+			cfg:  Config{TimeFormat: "epoch"},
+			want: map[string]any{"ts": float64(at.UnixNano()) / float64(time.Second)},
+		},
+		{
+			name: "renamed keys",
+			cfg:  Config{TimeKey: "@timestamp", LevelKey: "severity", MessageKey: "message", CallerKey: "source"},
+			want: map[string]any{
+				"@timestamp": "2024-05-06T07:08:09.123Z",
+				"severity":   "info",
+				"message":    "hello",
+				"source":     "app/main.go:42",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			enc := newEncoder(tt.cfg.toZapConfig())
+			buf, err := enc.EncodeEntry(zapcore.Entry{
+				Level:   zapcore.InfoLevel,
+				Time:    at,
+				Message: "hello",
+				Caller:  zapcore.NewEntryCaller(0, "/src/app/main.go", 42, true),
+			}, nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var got map[string]any
+			if err := json.Unmarshal(buf.Bytes(), &got); err != nil {
+				t.Fatalf("entry %q is not JSON: %v", buf, err)
+			}
+			for key, want := range tt.want {
+				if got[key] != want {
+					t.Errorf("%s = %v, want %v (entry %s)", key, got[key], want, buf)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,51 @@
+package logx
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+
+	"go.uber.org/zap"
+)
+
+// SetLevel changes the minimum enabled level of the logger built by Init
+// (or the default logger) without restarting the process.
+// Loggers installed with SetLogger keep their own level.
+//
+// Example:
+//
+//	if err := logx.SetLevel("debug"); err != nil {
+//	    logx.Errorw("Invalid log level", "error", err)
+//	}
+func SetLevel(level string) error {
+	switch strings.ToLower(level) {
+	case "debug", "info", "warn", "warning", "error", "dpanic", "panic", "fatal":
+	default:
+		return fmt.Errorf("logx: unknown level %q", level)
+	}
+	atomicLevel().SetLevel(parseLevel(level))
+	return nil
+}
+
+// Level returns the current minimum enabled level, e.g. "info".
+func Level() string {
+	return atomicLevel().Level().String()
+}
+
+// LevelHandler returns an HTTP handler that reports the current level on GET
+// and changes it on PUT, using the same protocol as zap's AtomicLevel:
+//
+//	curl -X GET localhost:6060/debug/loglevel
+//	curl -X PUT localhost:6060/debug/loglevel -d '{"level":"debug"}'
+func LevelHandler() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		atomicLevel().ServeHTTP(w, r)
+	}
+}
+
+// atomicLevel returns the level handle of the current global logger.
+func atomicLevel() zap.AtomicLevel {
+	globalMu.RLock()
+	defer globalMu.RUnlock()
+	return globalLevel
+}
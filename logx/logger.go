@@ -5,31 +5,89 @@ import (
 	"sync"
 
 	"go.uber.org/zap"
+	"gopkg.in/natefinch/lumberjack.v2"
 )
 
 var (
 	globalLogger *zap.SugaredLogger
+	globalLevel  zap.AtomicLevel
 	globalMu     sync.RWMutex
 )
 
 func init() {
 	// Initialize with a default production logger
-	logger, _ := zap.NewProduction()
+	cfg := zap.NewProductionConfig()
+	globalLevel = cfg.Level
+	logger, _ := cfg.Build()
 	globalLogger = logger.Sugar()
 }
 
 // Init initializes the global logger with the given configuration.
 // It should be called early in application startup.
 func Init(cfg Config) error {
+	if err := cfg.Validate(); err != nil {
+		return err
+	}
 	zapCfg := cfg.toZapConfig()
-	logger, err := zapCfg.Build()
+	var opts []zap.Option
+	var async *asyncWriter
+	if cfg.Async != nil {
+		// The async core owns the output paths; zap builds its own core
+		// without any and the option below replaces it.
+		out, _, err := zap.Open(zapCfg.OutputPaths...)
+		if err != nil {
+			return err
+		}
+		zapCfg.OutputPaths = []string{}
+		var opt zap.Option
+		opt, async = cfg.Async.asyncOption(zapCfg, out)
+		opts = append(opts, opt)
+	}
+	var rotation *lumberjack.Logger
+	if cfg.Rotation != nil {
+		var opt zap.Option
+		opt, rotation = cfg.Rotation.rotationOption(zapCfg)
+		opts = append(opts, opt)
+	}
+	var ring *ringBuffer
+	if cfg.RingBuffer != nil {
+		var opt zap.Option
+		opt, ring = cfg.RingBuffer.ringBufferOption(zapCfg)
+		opts = append(opts, opt)
+	}
+	logger, err := zapCfg.Build(opts...)
 	if err != nil {
+		if async != nil {
+			async.close()
+		}
+		if rotation != nil {
+			_ = rotation.Close()
+		}
 		return err
 	}
 
 	globalMu.Lock()
+	previous := globalLogger
 	globalLogger = logger.Sugar()
+	globalLevel = zapCfg.Level
 	globalMu.Unlock()
+	ringCurrent.Store(ring)
+
+	if async != nil {
+		registerAsyncMetrics()
+	}
+	// Flush and release the writers of the logger being replaced, if any
+	oldAsync := asyncCurrent.Swap(async)
+	oldRotation := rotationCurrent.Swap(rotation)
+	if oldAsync != nil || oldRotation != nil {
+		_ = previous.Sync()
+	}
+	if oldAsync != nil {
+		oldAsync.close()
+	}
+	if oldRotation != nil {
+		_ = oldRotation.Close()
+	}
 
 	return nil
 }
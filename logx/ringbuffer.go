@@ -0,0 +1,170 @@
+package logx
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+	"sync/atomic"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// RingBufferConfig configures an in-memory buffer of the most recent log
+// entries, kept for crash diagnostics and written out by DumpRecent.
+type RingBufferConfig struct {
+	// Size is the number of most recent entries retained.
+	// Default: 1000
+	Size int `yaml:"size,omitempty" json:"size,omitempty"`
+
+	// Level is the minimum level of retained entries. It may be lower than
+	// the logger's level, to keep debug context that is not written out.
+	// Default: the logger's level
+	Level string `yaml:"level,omitempty" json:"level,omitempty"`
+
+	// PanicDumpSize is the number of most recent entries the recovery
+	// middlewares write out after a panic (see DumpRecentForPanic). It is
+	// capped at Size.
+	// Default: 100
+	PanicDumpSize int `yaml:"panicDumpSize,omitempty" json:"panicDumpSize,omitempty"`
+}
+
+// Validate checks the ring buffer configuration for invalid values.
+func (c *RingBufferConfig) Validate() error {
+	if c.Size < 0 {
+		return errors.New("logx: ringBuffer size must be >= 0")
+	}
+	if c.PanicDumpSize < 0 {
+		return errors.New("logx: ringBuffer panicDumpSize must be >= 0")
+	}
+	switch strings.ToLower(c.Level) {
+	case "", "debug", "info", "warn", "warning", "error", "dpanic", "panic", "fatal":
+	default:
+		return fmt.Errorf("logx: unknown ringBuffer level %q", c.Level)
+	}
+	return nil
+}
+
+// ringCurrent is the ring buffer of the global logger, if any.
+var ringCurrent atomic.Pointer[ringBuffer]
+
+// ringBuffer is a zapcore.WriteSyncer keeping the last entries written.
+type ringBuffer struct {
+	mu        sync.Mutex
+	entries   [][]byte
+	next      int
+	full      bool
+	panicDump int
+}
+
+func newRingBuffer(size, panicDump int) *ringBuffer {
+	return &ringBuffer{entries: make([][]byte, size), panicDump: min(panicDump, size)}
+}
+
+// Write stores a copy of p, which zap reuses, evicting the oldest entry
+// once the buffer is full.
+func (r *ringBuffer) Write(p []byte) (int, error) {
+	entry := append([]byte(nil), p...)
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.entries[r.next] = entry
+	r.next++
+	if r.next == len(r.entries) {
+		r.next = 0
+		r.full = true
+	}
+	return len(p), nil
+}
+
+// Sync implements zapcore.WriteSyncer; entries are in memory already.
+func (r *ringBuffer) Sync() error {
+	return nil
+}
+
+// last returns the n most recent retained entries, oldest first.
+func (r *ringBuffer) last(n int) [][]byte {
+	entries := r.snapshot()
+	if n < len(entries) {
+		entries = entries[len(entries)-n:]
+	}
+	return entries
+}
+
+// snapshot returns the retained entries, oldest first.
+func (r *ringBuffer) snapshot() [][]byte {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if !r.full {
+		return append([][]byte(nil), r.entries[:r.next]...)
+	}
+	out := make([][]byte, 0, len(r.entries))
+	out = append(out, r.entries[r.next:]...)
+	return append(out, r.entries[:r.next]...)
+}
+
+// ringBufferOption returns a zap option that tees log entries to a new ring
+// buffer, encoded like the rest of zapCfg's output.
+func (c *RingBufferConfig) ringBufferOption(zapCfg zap.Config) (zap.Option, *ringBuffer) {
+	size := c.Size
+	if size == 0 {
+		size = 1000
+	}
+	var level zapcore.LevelEnabler = zapCfg.Level
+	if c.Level != "" {
+		level = parseLevel(c.Level)
+	}
+
+	panicDump := c.PanicDumpSize
+	if panicDump == 0 {
+		panicDump = 100
+	}
+
+	ring := newRingBuffer(size, panicDump)
+	ringCore := zapcore.NewCore(newEncoder(zapCfg), ring, level)
+	return zap.WrapCore(func(core zapcore.Core) zapcore.Core {
+		return zapcore.NewTee(core, ringCore)
+	}), ring
+}
+
+// DumpRecent writes the entries retained by the ring buffer, oldest first,
+// to w. It does nothing unless Config.RingBuffer is set.
+//
+// Example:
+//
+//	defer func() {
+//	    if r := recover(); r != nil {
+//	        _ = logx.DumpRecent(os.Stderr)
+//	        panic(r)
+//	    }
+//	}()
+func DumpRecent(w io.Writer) error {
+	ring := ringCurrent.Load()
+	if ring == nil {
+		return nil
+	}
+	return writeEntries(w, ring.snapshot())
+}
+
+// DumpRecentForPanic is DumpRecent limited to the last
+// RingBufferConfig.PanicDumpSize entries. The recovery middlewares call it
+// with os.Stderr after logging a panic, so that the lead-up to the panic is
+// captured even when regular output is buffered, without flooding stderr
+// on every recovered panic.
+func DumpRecentForPanic(w io.Writer) error {
+	ring := ringCurrent.Load()
+	if ring == nil {
+		return nil
+	}
+	return writeEntries(w, ring.last(ring.panicDump))
+}
+
+func writeEntries(w io.Writer, entries [][]byte) error {
+	for _, entry := range entries {
+		if _, err := w.Write(entry); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,106 @@
+package logx
+
+import (
+	"bytes"
+	"fmt"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestRingBuffer(t *testing.T) {
+	tests := []struct {
+		name      string
+		cfg       RingBufferConfig
+		logged    int
+		wantDump  []int
+		wantPanic []int
+	}{
+		{name: "not full", cfg: RingBufferConfig{Size: 5}, logged: 3, wantDump: []int{0, 1, 2}, wantPanic: []int{0, 1, 2}},
+		{name: "wrapped", cfg: RingBufferConfig{Size: 3}, logged: 7, wantDump: []int{4, 5, 6}, wantPanic: []int{4, 5, 6}},
+		{
+			name:      "panic dump capped",
+			cfg:       RingBufferConfig{Size: 5, PanicDumpSize: 2},
+			logged:    7,
+			wantDump:  []int{2, 3, 4, 5, 6},
+			wantPanic: []int{5, 6},
+		},
+		{
+			name:      "debug retained below the logger level",
+			cfg:       RingBufferConfig{Size: 4, Level: "debug"},
+			logged:    2,
+			wantDump:  []int{0, 1},
+			wantPanic: []int{0, 1},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			restoreGlobal(t)
+			cfg := tt.cfg
+			out := filepath.Join(t.TempDir(), "out.log")
+			if err := Init(Config{Level: "info", OutputPaths: []string{out}, RingBuffer: &cfg}); err != nil {
+				t.Fatalf("Init() error = %v", err)
+			}
+			for i := 0; i < tt.logged; i++ {
+				if cfg.Level == "debug" {
+					Debugw("entry", "i", i)
+				} else {
+					Infow("entry", "i", i)
+				}
+			}
+
+			var dump, panicDump bytes.Buffer
+			if err := DumpRecent(&dump); err != nil {
+				t.Fatalf("DumpRecent() error = %v", err)
+			}
+			if err := DumpRecentForPanic(&panicDump); err != nil {
+				t.Fatalf("DumpRecentForPanic() error = %v", err)
+			}
+			if got, want := entryIndexes(t, dump.String()), fmt.Sprint(tt.wantDump); got != want {
+				t.Errorf("DumpRecent() entries = %s, want %s", got, want)
+			}
+			if got, want := entryIndexes(t, panicDump.String()), fmt.Sprint(tt.wantPanic); got != want {
+				t.Errorf("DumpRecentForPanic() entries = %s, want %s", got, want)
+			}
+		})
+	}
+}
+
+func TestDumpRecentWithoutRingBuffer(t *testing.T) {
+	restoreGlobal(t)
+	if err := Init(Config{OutputPaths: []string{filepath.Join(t.TempDir(), "out.log")}}); err != nil {
+		t.Fatalf("Init() error = %v", err)
+	}
+	Infow("entry")
+
+	var buf bytes.Buffer
+	if err := DumpRecent(&buf); err != nil || buf.Len() != 0 {
+		t.Errorf("DumpRecent() = %q, %v, want nothing", buf.String(), err)
+	}
+	if err := DumpRecentForPanic(&buf); err != nil || buf.Len() != 0 {
+		t.Errorf("DumpRecentForPanic() = %q, %v, want nothing", buf.String(), err)
+	}
+}
+
+// entryIndexes returns the "i" field of each JSON line in dump, formatted
+// like fmt.Sprint of an []int.
+func entryIndexes(t *testing.T, dump string) string {
+	t.Helper()
+	var indexes []int
+	for _, line := range strings.Split(strings.TrimSpace(dump), "\n") {
+		if line == "" {
+			continue
+		}
+		_, after, ok := strings.Cut(line, `"i":`)
+		if !ok {
+			t.Fatalf("entry without i: %s", line)
+		}
+		var i int
+		if _, err := fmt.Sscanf(after, "%d", &i); err != nil {
+			t.Fatalf("entry %s: %v", line, err)
+		}
+		indexes = append(indexes, i)
+	}
+	return fmt.Sprint(indexes)
+}
@@ -0,0 +1,114 @@
+package logx
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// openFiles returns how many of the process's file descriptors refer to
+// path, skipping the test where /proc is not available.
+func openFiles(t *testing.T, path string) int {
+	t.Helper()
+	fds, err := os.ReadDir("/proc/self/fd")
+	if err != nil {
+		t.Skipf("cannot list open files: %v", err)
+	}
+	n := 0
+	for _, fd := range fds {
+		if target, err := os.Readlink(filepath.Join("/proc/self/fd", fd.Name())); err == nil && target == path {
+			n++
+		}
+	}
+	return n
+}
+
+func TestInitClosesReplacedRotationFile(t *testing.T) {
+	tests := []struct {
+		name string
+		next Config
+	}{
+		{name: "another rotation file", next: Config{Rotation: &RotationConfig{Filename: "next.log"}}},
+		{name: "no rotation", next: Config{}},
+		{name: "async without rotation", next: Config{Async: &AsyncConfig{}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			restoreGlobal(t)
+			dir := t.TempDir()
+			first := filepath.Join(dir, "first.log")
+			stdout := filepath.Join(dir, "stdout.log")
+
+			if err := Init(Config{OutputPaths: []string{stdout}, Rotation: &RotationConfig{Filename: first}}); err != nil {
+				t.Fatalf("Init() error = %v", err)
+			}
+			Infow("before")
+			if got := openFiles(t, first); got != 1 {
+				t.Fatalf("open handles on the rotation file = %d, want 1", got)
+			}
+
+			next := tt.next
+			next.OutputPaths = []string{stdout}
+			if next.Rotation != nil {
+				next.Rotation.Filename = filepath.Join(dir, next.Rotation.Filename)
+			}
+			if err := Init(next); err != nil {
+				t.Fatalf("second Init() error = %v", err)
+			}
+			Infow("after")
+			if got := openFiles(t, first); got != 0 {
+				t.Errorf("open handles on the replaced rotation file = %d, want 0", got)
+			}
+		})
+	}
+}
+
+func TestRotationRollsOver(t *testing.T) {
+	const mb = 1 << 20
+	tests := []struct {
+		name        string
+		write       int
+		wantBackups int
+	}{
+		{name: "below max size", write: mb / 2},
+		{name: "past max size", write: mb + mb/2, wantBackups: 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			restoreGlobal(t)
+			dir := t.TempDir()
+			filename := filepath.Join(dir, "app.log")
+			cfg := Config{
+				OutputPaths: []string{filepath.Join(dir, "stdout.log")},
+				Rotation:    &RotationConfig{Filename: filename, MaxSizeMB: 1},
+			}
+			if err := Init(cfg); err != nil {
+				t.Fatalf("Init() error = %v", err)
+			}
+
+			payload := strings.Repeat("x", 1024)
+			for written := 0; written < tt.write; written += len(payload) {
+				Infow("filler", "payload", payload)
+			}
+			_ = Sync()
+
+			backups, err := filepath.Glob(filepath.Join(dir, "app-*.log"))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(backups) != tt.wantBackups {
+				t.Errorf("rotated files = %q, want %d", backups, tt.wantBackups)
+			}
+			info, err := os.Stat(filename)
+			if err != nil {
+				t.Fatalf("current log file: %v", err)
+			}
+			if info.Size() > mb {
+				t.Errorf("current log file is %d bytes, want at most %d", info.Size(), mb)
+			}
+		})
+	}
+}
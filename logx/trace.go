@@ -0,0 +1,27 @@
+package logx
+
+import (
+	"context"
+
+	oteltrace "go.opentelemetry.io/otel/trace"
+	"go.uber.org/zap"
+)
+
+// Ctx returns the logger for ctx (see FromContext) with trace_id and span_id
+// bound when ctx carries a sampled OpenTelemetry span. Without one it returns
+// the logger unchanged, so it is safe to use everywhere.
+//
+// Example:
+//
+//	logx.Ctx(ctx).Infow("Order created", "orderId", id)
+func Ctx(ctx context.Context) *zap.SugaredLogger {
+	logger := FromContext(ctx)
+	sc := oteltrace.SpanContextFromContext(ctx)
+	if !sc.IsValid() || !sc.IsSampled() {
+		return logger
+	}
+	return logger.With(
+		"trace_id", sc.TraceID().String(),
+		"span_id", sc.SpanID().String(),
+	)
+}
@@ -0,0 +1,105 @@
+package metric
+
+import (
+	"maps"
+	"slices"
+	"sync"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/ssgohq/goten-core/logx"
+)
+
+// OverflowLabelValue replaces label values beyond a cardinality limit.
+const OverflowLabelValue = "other"
+
+// labelLimit caps the number of distinct values of one label.
+type labelLimit struct {
+	name  string
+	index int
+	max   int
+
+	mu   sync.RWMutex
+	seen map[string]struct{}
+}
+
+// newLabelLimit returns a limit for the named label, or nil if labelNames
+// does not contain it.
+func newLabelLimit(labelNames []string, name string, limit int) *labelLimit {
+	index := slices.Index(labelNames, name)
+	if index < 0 {
+		logx.Errorw("Cannot limit cardinality of unknown label", "label", name, "labels", labelNames)
+		return nil
+	}
+	return &labelLimit{
+		name:  name,
+		index: index,
+		max:   limit,
+		seen:  make(map[string]struct{}, limit),
+	}
+}
+
+// value returns v if it is within the limit, or OverflowLabelValue.
+// The first max distinct values are admitted; later ones are collapsed.
+func (l *labelLimit) value(v string) string {
+	l.mu.RLock()
+	_, ok := l.seen[v]
+	full := len(l.seen) >= l.max
+	l.mu.RUnlock()
+	if ok {
+		return v
+	}
+	if full {
+		return OverflowLabelValue
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if _, ok := l.seen[v]; ok {
+		return v
+	}
+	if len(l.seen) >= l.max {
+		return OverflowLabelValue
+	}
+	l.seen[v] = struct{}{}
+	return v
+}
+
+// limitValues applies limits to label values, copying lvs only if a value
+// is replaced.
+func limitValues(limits []*labelLimit, lvs []string) []string {
+	copied := false
+	for _, l := range limits {
+		if l.index >= len(lvs) {
+			continue
+		}
+		if v := l.value(lvs[l.index]); v != lvs[l.index] {
+			if !copied {
+				lvs = slices.Clone(lvs)
+				copied = true
+			}
+			lvs[l.index] = v
+		}
+	}
+	return lvs
+}
+
+// limitLabels applies limits to labels, copying them only if a value is
+// replaced.
+func limitLabels(limits []*labelLimit, labels prometheus.Labels) prometheus.Labels {
+	copied := false
+	for _, l := range limits {
+		cur, ok := labels[l.name]
+		if !ok {
+			continue
+		}
+		if v := l.value(cur); v != cur {
+			if !copied {
+				labels = maps.Clone(labels)
+				copied = true
+			}
+			labels[l.name] = v
+		}
+	}
+	return labels
+}
@@ -0,0 +1,144 @@
+package metric
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+)
+
+// collectValues returns the value of every series of c, keyed by the value
+// of its label.
+func collectValues(t *testing.T, c prometheus.Collector, label string) map[string]float64 {
+	t.Helper()
+	ch := make(chan prometheus.Metric)
+	go func() {
+		c.Collect(ch)
+		close(ch)
+	}()
+
+	out := make(map[string]float64)
+	for m := range ch {
+		var pb dto.Metric
+		if err := m.Write(&pb); err != nil {
+			t.Fatal(err)
+		}
+		var key string
+		for _, lp := range pb.GetLabel() {
+			if lp.GetName() == label {
+				key = lp.GetValue()
+			}
+		}
+		switch {
+		case pb.Counter != nil:
+			out[key] = pb.GetCounter().GetValue()
+		case pb.Gauge != nil:
+			out[key] = pb.GetGauge().GetValue()
+		}
+	}
+	return out
+}
+
+func TestCardinalityLimit(t *testing.T) {
+	tests := []struct {
+		name    string
+		limit   int
+		tenants int
+		want    map[string]float64
+	}{
+		{
+			name:    "within limit",
+			limit:   3,
+			tenants: 3,
+			want:    map[string]float64{"t0": 1, "t1": 1, "t2": 1},
+		},
+		{
+			name:    "overflow collapsed",
+			limit:   2,
+			tenants: 50,
+			want:    map[string]float64{"t0": 1, "t1": 1, OverflowLabelValue: 48},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// A private registry lets the test run repeatedly (-count)
+			r := NewWithRegistry(prometheus.NewRegistry())
+			counter := r.NewCounterVec(prometheus.CounterOpts{
+				Name: "test_cardinality_counter_total",
+				Help: "test",
+			}, []string{"tenant", "method"}).WithCardinalityLimit("tenant", tt.limit)
+			gauge := r.NewGaugeVec(prometheus.GaugeOpts{
+				Name: "test_cardinality_gauge",
+				Help: "test",
+			}, []string{"tenant"}).WithCardinalityLimit("tenant", tt.limit)
+
+			for n := 0; n < tt.tenants; n++ {
+				tenant := fmt.Sprintf("t%d", n)
+				if n%2 == 0 {
+					counter.Inc(tenant, "GET")
+				} else {
+					counter.With(prometheus.Labels{"tenant": tenant, "method": "GET"}).Inc()
+				}
+				gauge.Inc(tenant)
+			}
+
+			for name, got := range map[string]map[string]float64{
+				"counter": collectValues(t, counter.counterVec, "tenant"),
+				"gauge":   collectValues(t, gauge.gaugeVec, "tenant"),
+			} {
+				if fmt.Sprint(got) != fmt.Sprint(tt.want) {
+					t.Errorf("%s series = %v, want %v", name, got, tt.want)
+				}
+			}
+		})
+	}
+}
+
+func TestLabelLimitConcurrent(t *testing.T) {
+	l := newLabelLimit([]string{"tenant"}, "tenant", 10)
+
+	var mu sync.Mutex
+	admitted := make(map[string]struct{})
+	var wg sync.WaitGroup
+	for g := 0; g < 8; g++ {
+		wg.Add(1)
+		go func(g int) {
+			defer wg.Done()
+			for n := 0; n < 100; n++ {
+				v := l.value(fmt.Sprintf("t%d", (g*100+n)%40))
+				mu.Lock()
+				admitted[v] = struct{}{}
+				mu.Unlock()
+			}
+		}(g)
+	}
+	wg.Wait()
+
+	var values []string
+	for v := range admitted {
+		values = append(values, v)
+	}
+	sort.Strings(values)
+	// Ten admitted values plus the overflow bucket
+	if len(values) != 11 {
+		t.Errorf("distinct values = %d (%v), want 11", len(values), values)
+	}
+}
+
+func TestCardinalityLimitUnknownLabel(t *testing.T) {
+	r := NewWithRegistry(prometheus.NewRegistry())
+	c := r.NewCounterVec(prometheus.CounterOpts{Name: "test_cardinality_unknown_total", Help: "test"}, []string{"tenant"}).
+		WithCardinalityLimit("region", 1)
+	if len(c.limits) != 0 {
+		t.Errorf("limits = %d, want none for an unknown label", len(c.limits))
+	}
+	c.Inc("a")
+	c.Inc("b")
+	if got := collectValues(t, c.counterVec, "tenant"); len(got) != 2 {
+		t.Errorf("series = %v, want both values", got)
+	}
+}
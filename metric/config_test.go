@@ -0,0 +1,35 @@
+package metric
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestSetDefaultsIdempotent(t *testing.T) {
+	tests := []struct {
+		name string
+		cfg  interface{ SetDefaults() }
+	}{
+		{name: "server", cfg: &Config{}},
+		{name: "server with push", cfg: &Config{Push: &PushConfig{URL: "http://pushgateway:9091"}}},
+		{name: "push", cfg: &PushConfig{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.cfg.SetDefaults()
+			first := fmt.Sprintf("%+v", tt.cfg)
+			if c, ok := tt.cfg.(*Config); ok && c.Push != nil {
+				first += fmt.Sprintf("%+v", *c.Push)
+			}
+			tt.cfg.SetDefaults()
+			second := fmt.Sprintf("%+v", tt.cfg)
+			if c, ok := tt.cfg.(*Config); ok && c.Push != nil {
+				second += fmt.Sprintf("%+v", *c.Push)
+			}
+			if second != first {
+				t.Errorf("second SetDefaults changed the config:\n first: %s\nsecond: %s", first, second)
+			}
+		})
+	}
+}
@@ -13,6 +13,8 @@ type Counter struct {
 // CounterVec is a wrapper around prometheus.CounterVec with auto-registration.
 type CounterVec struct {
 	counterVec *prometheus.CounterVec
+	labelNames []string
+	limits     []*labelLimit
 }
 
 // NewCounter creates and registers a new Counter.
@@ -26,6 +28,7 @@ func NewCounter(opts prometheus.CounterOpts) *Counter {
 func NewCounterVec(opts prometheus.CounterOpts, labelNames []string) *CounterVec {
 	return &CounterVec{
 		counterVec: promauto.NewCounterVec(opts, labelNames),
+		labelNames: labelNames,
 	}
 }
 
@@ -39,22 +42,37 @@ func (c *Counter) Add(v float64) {
 	c.counter.Add(v)
 }
 
+// WithCardinalityLimit caps the number of distinct values of label at limit.
+// Values beyond the first limit are recorded as OverflowLabelValue. It must be
+// called before the counter is used.
+//
+// Example:
+//
+//	requests := metric.NewCounterVec(opts, []string{"tenant"}).
+//	    WithCardinalityLimit("tenant", 100)
+func (c *CounterVec) WithCardinalityLimit(label string, limit int) *CounterVec {
+	if l := newLabelLimit(c.labelNames, label, limit); l != nil {
+		c.limits = append(c.limits, l)
+	}
+	return c
+}
+
 // WithLabelValues returns a counter with the given label values.
 func (c *CounterVec) WithLabelValues(lvs ...string) prometheus.Counter {
-	return c.counterVec.WithLabelValues(lvs...)
+	return c.counterVec.WithLabelValues(limitValues(c.limits, lvs)...)
 }
 
 // With returns a counter with the given labels.
 func (c *CounterVec) With(labels prometheus.Labels) prometheus.Counter {
-	return c.counterVec.With(labels)
+	return c.counterVec.With(limitLabels(c.limits, labels))
 }
 
 // Inc increments the counter with the given label values by 1.
 func (c *CounterVec) Inc(lvs ...string) {
-	c.counterVec.WithLabelValues(lvs...).Inc()
+	c.WithLabelValues(lvs...).Inc()
 }
 
 // Add adds the given value to the counter with the given label values.
 func (c *CounterVec) Add(v float64, lvs ...string) {
-	c.counterVec.WithLabelValues(lvs...).Add(v)
+	c.WithLabelValues(lvs...).Add(v)
 }
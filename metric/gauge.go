@@ -12,7 +12,9 @@ type Gauge struct {
 
 // GaugeVec is a wrapper around prometheus.GaugeVec with auto-registration.
 type GaugeVec struct {
-	gaugeVec *prometheus.GaugeVec
+	gaugeVec   *prometheus.GaugeVec
+	labelNames []string
+	limits     []*labelLimit
 }
 
 // NewGauge creates and registers a new Gauge.
@@ -25,7 +27,8 @@ func NewGauge(opts prometheus.GaugeOpts) *Gauge {
 // NewGaugeVec creates and registers a new GaugeVec.
 func NewGaugeVec(opts prometheus.GaugeOpts, labelNames []string) *GaugeVec {
 	return &GaugeVec{
-		gaugeVec: promauto.NewGaugeVec(opts, labelNames),
+		gaugeVec:   promauto.NewGaugeVec(opts, labelNames),
+		labelNames: labelNames,
 	}
 }
 
@@ -54,27 +57,37 @@ func (g *Gauge) Sub(v float64) {
 	g.gauge.Sub(v)
 }
 
+// WithCardinalityLimit caps the number of distinct values of label at limit.
+// Values beyond the first limit are recorded as OverflowLabelValue. It must be
+// called before the gauge is used.
+func (g *GaugeVec) WithCardinalityLimit(label string, limit int) *GaugeVec {
+	if l := newLabelLimit(g.labelNames, label, limit); l != nil {
+		g.limits = append(g.limits, l)
+	}
+	return g
+}
+
 // WithLabelValues returns a gauge with the given label values.
 func (g *GaugeVec) WithLabelValues(lvs ...string) prometheus.Gauge {
-	return g.gaugeVec.WithLabelValues(lvs...)
+	return g.gaugeVec.WithLabelValues(limitValues(g.limits, lvs)...)
 }
 
 // With returns a gauge with the given labels.
 func (g *GaugeVec) With(labels prometheus.Labels) prometheus.Gauge {
-	return g.gaugeVec.With(labels)
+	return g.gaugeVec.With(limitLabels(g.limits, labels))
 }
 
 // Set sets the gauge with the given label values to the given value.
 func (g *GaugeVec) Set(v float64, lvs ...string) {
-	g.gaugeVec.WithLabelValues(lvs...).Set(v)
+	g.WithLabelValues(lvs...).Set(v)
 }
 
 // Inc increments the gauge with the given label values by 1.
 func (g *GaugeVec) Inc(lvs ...string) {
-	g.gaugeVec.WithLabelValues(lvs...).Inc()
+	g.WithLabelValues(lvs...).Inc()
 }
 
 // Dec decrements the gauge with the given label values by 1.
 func (g *GaugeVec) Dec(lvs ...string) {
-	g.gaugeVec.WithLabelValues(lvs...).Dec()
+	g.WithLabelValues(lvs...).Dec()
 }
@@ -1,6 +1,9 @@
 package metric
 
 import (
+	"slices"
+	"time"
+
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/prometheus/client_golang/prometheus/promauto"
 )
@@ -16,19 +19,53 @@ type HistogramVec struct {
 }
 
 // NewHistogram creates and registers a new Histogram.
+// The histogram keeps its own copy of opts.Buckets.
 func NewHistogram(opts prometheus.HistogramOpts) *Histogram {
+	opts.Buckets = slices.Clone(opts.Buckets)
 	return &Histogram{
 		histogram: promauto.NewHistogram(opts),
 	}
 }
 
 // NewHistogramVec creates and registers a new HistogramVec.
+// The histogram keeps its own copy of opts.Buckets.
 func NewHistogramVec(opts prometheus.HistogramOpts, labelNames []string) *HistogramVec {
+	opts.Buckets = slices.Clone(opts.Buckets)
 	return &HistogramVec{
 		histogramVec: promauto.NewHistogramVec(opts, labelNames),
 	}
 }
 
+// NewLatencyHistogram creates and registers a Histogram of durations in
+// seconds, with the LatencyBuckets.
+//
+// Example:
+//
+//	var syncDuration = metric.NewLatencyHistogram("goten_sync_duration_seconds", "Sync latency in seconds")
+//
+//	defer syncDuration.Timer()()
+func NewLatencyHistogram(name, help string) *Histogram {
+	return NewHistogram(prometheus.HistogramOpts{Name: name, Help: help, Buckets: LatencyBuckets()})
+}
+
+// NewLatencyHistogramVec creates and registers a HistogramVec of durations
+// in seconds, with the LatencyBuckets.
+func NewLatencyHistogramVec(name, help string, labelNames []string) *HistogramVec {
+	return NewHistogramVec(prometheus.HistogramOpts{Name: name, Help: help, Buckets: LatencyBuckets()}, labelNames)
+}
+
+// NewSizeHistogram creates and registers a Histogram of sizes in bytes,
+// with the SizeBuckets.
+func NewSizeHistogram(name, help string) *Histogram {
+	return NewHistogram(prometheus.HistogramOpts{Name: name, Help: help, Buckets: SizeBuckets()})
+}
+
+// NewSizeHistogramVec creates and registers a HistogramVec of sizes in
+// bytes, with the SizeBuckets.
+func NewSizeHistogramVec(name, help string, labelNames []string) *HistogramVec {
+	return NewHistogramVec(prometheus.HistogramOpts{Name: name, Help: help, Buckets: SizeBuckets()}, labelNames)
+}
+
 // Observe adds an observation to the histogram.
 func (h *Histogram) Observe(v float64) {
 	h.histogram.Observe(v)
@@ -49,8 +86,60 @@ func (h *HistogramVec) Observe(v float64, lvs ...string) {
 	h.histogramVec.WithLabelValues(lvs...).Observe(v)
 }
 
+// Timer starts timing and returns a function that observes the elapsed time
+// in seconds when called.
+//
+// Example:
+//
+//	defer latency.Timer()()
+func (h *Histogram) Timer() func() {
+	start := time.Now()
+	return func() {
+		h.histogram.Observe(time.Since(start).Seconds())
+	}
+}
+
+// Timer starts timing and returns a function that observes the elapsed time
+// in seconds, with the given label values, when called.
+//
+// Example:
+//
+//	func (h *Handler) GetUser(ctx context.Context, c *app.RequestContext) {
+//	    defer requestDuration.Timer("GET", "/users/:id")()
+//	    // ...
+//	}
+func (h *HistogramVec) Timer(lvs ...string) func() {
+	start := time.Now()
+	return func() {
+		h.histogramVec.WithLabelValues(lvs...).Observe(time.Since(start).Seconds())
+	}
+}
+
+// latencyBuckets and sizeBuckets are arrays, copied on assignment, so that
+// the defaults cannot be changed through a returned slice.
+var (
+	latencyBuckets = [...]float64{.001, .005, .01, .025, .05, .1, .25, .5, 1, 2.5, 5, 10}
+	sizeBuckets    = [...]float64{100, 1000, 10000, 100000, 1000000, 10000000}
+)
+
+// LatencyBuckets returns a new copy of the default histogram buckets for
+// latency metrics (in seconds).
+func LatencyBuckets() []float64 {
+	b := latencyBuckets
+	return b[:]
+}
+
+// SizeBuckets returns a new copy of the default histogram buckets for size
+// metrics (in bytes).
+func SizeBuckets() []float64 {
+	b := sizeBuckets
+	return b[:]
+}
+
 // DefaultBuckets is the default histogram buckets for latency metrics (in seconds).
-var DefaultBuckets = []float64{.001, .005, .01, .025, .05, .1, .25, .5, 1, 2.5, 5, 10}
+// It is shared by every importer; prefer LatencyBuckets, which returns a copy.
+var DefaultBuckets = LatencyBuckets()
 
 // DefaultSizeBuckets is the default histogram buckets for size metrics (in bytes).
-var DefaultSizeBuckets = []float64{100, 1000, 10000, 100000, 1000000, 10000000}
+// It is shared by every importer; prefer SizeBuckets, which returns a copy.
+var DefaultSizeBuckets = SizeBuckets()
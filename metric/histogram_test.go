@@ -0,0 +1,148 @@
+package metric
+
+import (
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+)
+
+// histogramSample returns the sample count and sum of the single histogram
+// collected from c.
+func histogramSample(t *testing.T, c prometheus.Collector) (uint64, float64) {
+	t.Helper()
+	ch := make(chan prometheus.Metric, 1)
+	c.Collect(ch)
+	close(ch)
+
+	var pb dto.Metric
+	if err := (<-ch).Write(&pb); err != nil {
+		t.Fatal(err)
+	}
+	return pb.GetHistogram().GetSampleCount(), pb.GetHistogram().GetSampleSum()
+}
+
+func TestTimer(t *testing.T) {
+	const sleep = 20 * time.Millisecond
+
+	tests := []struct {
+		name string
+		run  func(r *Registry) prometheus.Collector
+	}{
+		{
+			name: "Histogram",
+			run: func(r *Registry) prometheus.Collector {
+				h := r.NewHistogram(prometheus.HistogramOpts{
+					Name:    "goten_test_timer_seconds",
+					Help:    "test",
+					Buckets: LatencyBuckets(),
+				})
+				stop := h.Timer()
+				time.Sleep(sleep)
+				stop()
+				return h.histogram
+			},
+		},
+		{
+			name: "HistogramVec",
+			run: func(r *Registry) prometheus.Collector {
+				h := r.NewHistogramVec(prometheus.HistogramOpts{
+					Name:    "goten_test_timer_vec_seconds",
+					Help:    "test",
+					Buckets: LatencyBuckets(),
+				}, []string{"method"})
+				stop := h.Timer("GET")
+				time.Sleep(sleep)
+				stop()
+				return h.histogramVec
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			count, sum := histogramSample(t, tt.run(NewWithRegistry(prometheus.NewRegistry())))
+			if count != 1 {
+				t.Errorf("count = %d, want 1", count)
+			}
+			if sum < sleep.Seconds() || sum > 5 {
+				t.Errorf("sum = %vs, want between %vs and 5s", sum, sleep.Seconds())
+			}
+		})
+	}
+}
+
+func TestBucketsAreCopies(t *testing.T) {
+	tests := []struct {
+		name    string
+		buckets func() []float64
+	}{
+		{name: "LatencyBuckets", buckets: LatencyBuckets},
+		{name: "SizeBuckets", buckets: SizeBuckets},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			first := tt.buckets()
+			want := slices.Clone(first)
+			first[0] = -1
+
+			if got := tt.buckets(); !slices.Equal(got, want) {
+				t.Errorf("%s() = %v after changing an earlier copy, want %v", tt.name, got, want)
+			}
+		})
+	}
+}
+
+func TestHistogramKeepsOwnBuckets(t *testing.T) {
+	tests := []struct {
+		name    string
+		newHist func(buckets []float64) prometheus.Collector
+	}{
+		{
+			name: "Histogram",
+			newHist: func(buckets []float64) prometheus.Collector {
+				h := NewHistogram(prometheus.HistogramOpts{Name: "goten_test_own_buckets", Help: "test", Buckets: buckets})
+				h.Observe(0.5)
+				return h.histogram
+			},
+		},
+		{
+			name: "HistogramVec",
+			newHist: func(buckets []float64) prometheus.Collector {
+				h := NewHistogramVec(prometheus.HistogramOpts{
+					Name: "goten_test_own_buckets_vec", Help: "test", Buckets: buckets,
+				}, []string{"op"})
+				h.Observe(0.5, "read")
+				return h.histogramVec
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			buckets := []float64{0.1, 1, 10}
+			c := tt.newHist(buckets)
+			// The constructors register with the default registry
+			t.Cleanup(func() { prometheus.Unregister(c) })
+			buckets[0], buckets[1], buckets[2] = 100, 200, 300
+
+			ch := make(chan prometheus.Metric, 1)
+			c.Collect(ch)
+			close(ch)
+			var pb dto.Metric
+			if err := (<-ch).Write(&pb); err != nil {
+				t.Fatal(err)
+			}
+			var bounds []float64
+			for _, b := range pb.GetHistogram().GetBucket() {
+				bounds = append(bounds, b.GetUpperBound())
+			}
+			if want := []float64{0.1, 1, 10}; !slices.Equal(bounds, want) {
+				t.Errorf("bucket bounds = %v, want %v", bounds, want)
+			}
+		})
+	}
+}
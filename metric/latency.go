@@ -0,0 +1,177 @@
+package metric
+
+import (
+	"encoding/json"
+	"math/rand/v2"
+	"net/http"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/ssgohq/goten-core/logx"
+)
+
+const (
+	// defaultLatencyWindow is the length of a latency recording window.
+	defaultLatencyWindow = time.Minute
+	// defaultLatencySamples is the reservoir size per series and window.
+	defaultLatencySamples = 1024
+	// defaultLatencySeries caps the number of recorded series.
+	defaultLatencySeries = 256
+)
+
+var (
+	latencyEnabled atomic.Bool
+	defaultLatency = NewLatencyRecorder(defaultLatencyWindow, defaultLatencySamples, defaultLatencySeries)
+)
+
+// LatencyRecorder keeps bounded, reservoir-sampled latencies per series over
+// a rolling window for on-box diagnostics. Percentiles cover the current and
+// the previous window, so they reflect between one and two windows of data.
+type LatencyRecorder struct {
+	window    time.Duration
+	samples   int
+	maxSeries int
+
+	mu     sync.Mutex
+	series map[string]*latencySeries
+}
+
+// LatencySnapshot reports the latency percentiles of a series.
+type LatencySnapshot struct {
+	Count int64   `json:"count"`
+	P50   float64 `json:"p50_ms"`
+	P90   float64 `json:"p90_ms"`
+	P99   float64 `json:"p99_ms"`
+	Max   float64 `json:"max_ms"`
+}
+
+type latencySeries struct {
+	start time.Time
+	cur   latencyReservoir
+	prev  latencyReservoir
+}
+
+type latencyReservoir struct {
+	seen    int64
+	samples []time.Duration
+}
+
+// NewLatencyRecorder creates a recorder keeping up to samples latencies per
+// series and window, for at most maxSeries series. Samples for new series
+// beyond the cap are dropped.
+func NewLatencyRecorder(window time.Duration, samples, maxSeries int) *LatencyRecorder {
+	return &LatencyRecorder{
+		window:    window,
+		samples:   samples,
+		maxSeries: maxSeries,
+		series:    make(map[string]*latencySeries),
+	}
+}
+
+// Record adds a latency sample to the named series.
+func (r *LatencyRecorder) Record(name string, d time.Duration) {
+	now := time.Now()
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	s, ok := r.series[name]
+	if !ok {
+		if len(r.series) >= r.maxSeries {
+			return
+		}
+		s = &latencySeries{start: now}
+		r.series[name] = s
+	}
+	r.rotate(s, now)
+
+	res := &s.cur
+	res.seen++
+	if len(res.samples) < r.samples {
+		res.samples = append(res.samples, d)
+	} else if j := rand.Int64N(res.seen); j < int64(r.samples) {
+		res.samples[j] = d
+	}
+}
+
+// Snapshot returns the percentiles of every series with recent samples.
+func (r *LatencyRecorder) Snapshot() map[string]LatencySnapshot {
+	now := time.Now()
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	out := make(map[string]LatencySnapshot, len(r.series))
+	for name, s := range r.series {
+		r.rotate(s, now)
+		count := s.cur.seen + s.prev.seen
+		if count == 0 {
+			// Idle for two windows; drop the series to free its slot.
+			delete(r.series, name)
+			continue
+		}
+
+		all := make([]time.Duration, 0, len(s.cur.samples)+len(s.prev.samples))
+		all = append(all, s.cur.samples...)
+		all = append(all, s.prev.samples...)
+		sort.Slice(all, func(i, j int) bool { return all[i] < all[j] })
+
+		out[name] = LatencySnapshot{
+			Count: count,
+			P50:   percentileMs(all, 0.50),
+			P90:   percentileMs(all, 0.90),
+			P99:   percentileMs(all, 0.99),
+			Max:   percentileMs(all, 1),
+		}
+	}
+	return out
+}
+
+// rotate starts a new window when the current one has elapsed.
+func (r *LatencyRecorder) rotate(s *latencySeries, now time.Time) {
+	elapsed := now.Sub(s.start)
+	if elapsed < r.window {
+		return
+	}
+	if elapsed < 2*r.window {
+		s.prev = s.cur
+	} else {
+		s.prev = latencyReservoir{}
+	}
+	s.cur = latencyReservoir{samples: s.prev.samples[:0:0]}
+	s.start = now
+}
+
+// percentileMs returns the nearest-rank percentile of sorted in milliseconds.
+func percentileMs(sorted []time.Duration, p float64) float64 {
+	if len(sorted) == 0 {
+		return 0
+	}
+	idx := int(p*float64(len(sorted))+0.5) - 1
+	if idx < 0 {
+		idx = 0
+	}
+	if idx >= len(sorted) {
+		idx = len(sorted) - 1
+	}
+	return float64(sorted[idx]) / float64(time.Millisecond)
+}
+
+// ServeHTTP writes the snapshot as JSON.
+func (r *LatencyRecorder) ServeHTTP(w http.ResponseWriter, _ *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(r.Snapshot()); err != nil {
+		logx.Errorw("Failed to encode latency snapshot", "error", err)
+	}
+}
+
+// RecordLatency adds a sample to the default latency recorder. It is a no-op
+// unless the metric server has the latency endpoint enabled, so middlewares
+// can call it unconditionally.
+func RecordLatency(name string, d time.Duration) {
+	if latencyEnabled.Load() {
+		defaultLatency.Record(name, d)
+	}
+}
@@ -0,0 +1,101 @@
+package metric
+
+import (
+	"encoding/json"
+	"math"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestLatencyRecorderPercentiles(t *testing.T) {
+	tests := []struct {
+		name    string
+		samples int
+		n       int
+		wantP50 float64
+		wantP90 float64
+		wantP99 float64
+		tol     float64
+	}{
+		// 1..100 ms, all kept: exact nearest-rank percentiles
+		{name: "exact", samples: 1000, n: 100, wantP50: 50, wantP90: 90, wantP99: 99},
+		// 1..10000 ms sampled into 1000 slots: within a few percent
+		{name: "sampled", samples: 1000, n: 10000, wantP50: 5000, wantP90: 9000, wantP99: 9900, tol: 500},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := NewLatencyRecorder(time.Hour, tt.samples, 4)
+			for i := 1; i <= tt.n; i++ {
+				r.Record("http GET /users/:id", time.Duration(i)*time.Millisecond)
+			}
+
+			s, ok := r.Snapshot()["http GET /users/:id"]
+			if !ok {
+				t.Fatal("series missing from snapshot")
+			}
+			if s.Count != int64(tt.n) {
+				t.Errorf("Count = %d, want %d", s.Count, tt.n)
+			}
+			for _, p := range []struct {
+				name      string
+				got, want float64
+			}{
+				{"p50", s.P50, tt.wantP50},
+				{"p90", s.P90, tt.wantP90},
+				{"p99", s.P99, tt.wantP99},
+			} {
+				if math.Abs(p.got-p.want) > tt.tol {
+					t.Errorf("%s = %v, want %v ± %v", p.name, p.got, p.want, tt.tol)
+				}
+			}
+		})
+	}
+}
+
+func TestLatencyRecorderBounds(t *testing.T) {
+	r := NewLatencyRecorder(time.Hour, 10, 2)
+	for i := 0; i < 100; i++ {
+		r.Record("a", time.Millisecond)
+		r.Record("b", time.Millisecond)
+		r.Record("c", time.Millisecond)
+	}
+
+	snap := r.Snapshot()
+	if len(snap) != 2 {
+		t.Errorf("series = %d, want 2", len(snap))
+	}
+	if _, ok := snap["c"]; ok {
+		t.Error("series beyond maxSeries was recorded")
+	}
+	if got := len(r.series["a"].cur.samples); got != 10 {
+		t.Errorf("retained samples = %d, want 10", got)
+	}
+}
+
+func TestLatencyRecorderWindow(t *testing.T) {
+	r := NewLatencyRecorder(20*time.Millisecond, 10, 4)
+	r.Record("a", time.Millisecond)
+
+	time.Sleep(50 * time.Millisecond)
+	if snap := r.Snapshot(); len(snap) != 0 {
+		t.Errorf("snapshot after two idle windows = %v, want empty", snap)
+	}
+}
+
+func TestLatencyRecorderServeHTTP(t *testing.T) {
+	r := NewLatencyRecorder(time.Hour, 10, 4)
+	r.Record("rpc_server Echo/Echo", 3*time.Millisecond)
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest("GET", "/debug/latency", nil))
+
+	var got map[string]LatencySnapshot
+	if err := json.Unmarshal(w.Body.Bytes(), &got); err != nil {
+		t.Fatalf("decode %s: %v", w.Body.String(), err)
+	}
+	if s := got["rpc_server Echo/Echo"]; s.Count != 1 || s.P99 != 3 {
+		t.Errorf("snapshot = %+v, want one 3ms sample", s)
+	}
+}
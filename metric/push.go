@@ -0,0 +1,85 @@
+package metric
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/push"
+)
+
+// PushConfig configures pushing metrics to a Prometheus Pushgateway.
+type PushConfig struct {
+	// URL is the Pushgateway address, e.g. http://pushgateway:9091.
+	URL string `yaml:"url,omitempty" json:"url,omitempty"`
+
+	// Job is the job label of the pushed metrics.
+	Job string `yaml:"job,omitempty" json:"job,omitempty"`
+
+	// Grouping are additional grouping labels. Pushes replace the metrics
+	// of the same job and grouping, so each instance needs its own.
+	// Default: {"instance": <hostname>}
+	Grouping map[string]string `yaml:"grouping,omitempty" json:"grouping,omitempty"`
+
+	// Timeout bounds a push. Default: 5s
+	Timeout time.Duration `yaml:"timeout,omitempty" json:"timeout,omitempty"`
+}
+
+// IsEnabled returns true if a Pushgateway is configured.
+func (c *PushConfig) IsEnabled() bool {
+	return c != nil && c.URL != ""
+}
+
+// SetDefaults applies default values.
+func (c *PushConfig) SetDefaults() {
+	if len(c.Grouping) == 0 {
+		if host, err := os.Hostname(); err == nil {
+			c.Grouping = map[string]string{"instance": host}
+		}
+	}
+	if c.Timeout == 0 {
+		c.Timeout = 5 * time.Second
+	}
+}
+
+// Validate checks the configuration for invalid values.
+func (c *PushConfig) Validate() error {
+	if c.URL != "" && c.Job == "" {
+		return errors.New("metric: push job is required")
+	}
+	if c.Timeout < 0 {
+		return fmt.Errorf("metric: push timeout must be >= 0, got %s", c.Timeout)
+	}
+	return nil
+}
+
+// Push sends all metrics of the default registry to the Pushgateway,
+// replacing those previously pushed with the same job and grouping.
+// The metric server calls it on Stop when Config.Push is set, so that
+// increments since the last scrape are not lost at shutdown; batch jobs
+// can call it directly before exiting.
+func Push(ctx context.Context, cfg PushConfig) error {
+	cfg.SetDefaults()
+	if err := cfg.Validate(); err != nil {
+		return err
+	}
+	if !cfg.IsEnabled() {
+		return errors.New("metric: push url is required")
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, cfg.Timeout)
+	defer cancel()
+
+	pusher := push.New(cfg.URL, cfg.Job).
+		Gatherer(prometheus.DefaultGatherer)
+	for name, value := range cfg.Grouping {
+		pusher = pusher.Grouping(name, value)
+	}
+	if err := pusher.PushContext(ctx); err != nil {
+		return fmt.Errorf("metric: push to %s: %w", cfg.URL, err)
+	}
+	return nil
+}
@@ -0,0 +1,134 @@
+package metric
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// pushRequest is a request received by the fake Pushgateway.
+type pushRequest struct {
+	method, path string
+	body         string
+}
+
+// newPushgateway returns a fake Pushgateway answering with status and the
+// requests it received.
+func newPushgateway(t *testing.T, status int) (*httptest.Server, func() []pushRequest) {
+	t.Helper()
+	var (
+		mu       sync.Mutex
+		requests []pushRequest
+	)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		mu.Lock()
+		requests = append(requests, pushRequest{method: r.Method, path: r.URL.Path, body: string(body)})
+		mu.Unlock()
+		w.WriteHeader(status)
+	}))
+	t.Cleanup(srv.Close)
+	return srv, func() []pushRequest {
+		mu.Lock()
+		defer mu.Unlock()
+		return append([]pushRequest(nil), requests...)
+	}
+}
+
+func TestServerStopPushesMetrics(t *testing.T) {
+	const counterName = "goten_test_pushed_total"
+	// The server pushes the default registry
+	counter := NewCounter(prometheus.CounterOpts{Name: counterName, Help: "test"})
+	t.Cleanup(func() { prometheus.Unregister(counter.counter) })
+	counter.Inc()
+
+	tests := []struct {
+		name     string
+		status   int
+		push     bool
+		wantPush bool
+		wantPath string
+		grouping map[string]string
+	}{
+		{
+			name:     "pushed on stop",
+			status:   http.StatusOK,
+			push:     true,
+			wantPush: true,
+			grouping: map[string]string{"instance": "test-1"},
+			wantPath: "/metrics/job/batch/instance/test-1",
+		},
+		{
+			name:     "failed push does not block stop",
+			status:   http.StatusInternalServerError,
+			push:     true,
+			wantPush: true,
+			grouping: map[string]string{"instance": "test-1"},
+			wantPath: "/metrics/job/batch/instance/test-1",
+		},
+		{name: "push disabled", status: http.StatusOK},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gateway, requests := newPushgateway(t, tt.status)
+			cfg := Config{Host: "127.0.0.1", Port: freePort(t), EnableMetrics: true}
+			if tt.push {
+				cfg.Push = &PushConfig{URL: gateway.URL, Job: "batch", Grouping: tt.grouping}
+			}
+			s := NewServer(cfg)
+			s.Start()
+
+			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+			if err := s.Stop(ctx); err != nil {
+				t.Fatalf("Stop() error = %v", err)
+			}
+
+			got := requests()
+			if !tt.wantPush {
+				if len(got) != 0 {
+					t.Fatalf("pushes = %d, want 0", len(got))
+				}
+				return
+			}
+			if len(got) != 1 {
+				t.Fatalf("pushes = %d, want 1", len(got))
+			}
+			if got[0].method != http.MethodPut || got[0].path != tt.wantPath {
+				t.Errorf("push = %s %s, want PUT %s", got[0].method, got[0].path, tt.wantPath)
+			}
+			if !strings.Contains(got[0].body, counterName) {
+				t.Errorf("pushed body does not contain %s", counterName)
+			}
+		})
+	}
+}
+
+func TestPushConfigValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		cfg     PushConfig
+		wantErr bool
+	}{
+		{name: "valid", cfg: PushConfig{URL: "http://gateway:9091", Job: "batch"}},
+		{name: "disabled", cfg: PushConfig{}},
+		{name: "missing job", cfg: PushConfig{URL: "http://gateway:9091"}, wantErr: true},
+		{name: "negative timeout", cfg: PushConfig{URL: "http://gateway:9091", Job: "batch", Timeout: -1}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.cfg.Validate(); (err != nil) != tt.wantErr {
+				t.Errorf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,62 @@
+package metric
+
+import (
+	"slices"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+// Registry creates metrics registered with a specific Prometheus registerer
+// instead of the default one. Tests can use a private registry to create
+// metrics with the same names without duplicate registration panics.
+type Registry struct {
+	factory promauto.Factory
+}
+
+// NewWithRegistry returns a Registry that registers metrics with reg.
+// A nil reg creates metrics without registering them.
+//
+// Example:
+//
+//	reg := prometheus.NewRegistry()
+//	requests := metric.NewWithRegistry(reg).NewCounter(prometheus.CounterOpts{
+//	    Name: "requests_total",
+//	})
+func NewWithRegistry(reg prometheus.Registerer) *Registry {
+	return &Registry{factory: promauto.With(reg)}
+}
+
+// NewCounter creates and registers a new Counter.
+func (r *Registry) NewCounter(opts prometheus.CounterOpts) *Counter {
+	return &Counter{counter: r.factory.NewCounter(opts)}
+}
+
+// NewCounterVec creates and registers a new CounterVec.
+func (r *Registry) NewCounterVec(opts prometheus.CounterOpts, labelNames []string) *CounterVec {
+	return &CounterVec{counterVec: r.factory.NewCounterVec(opts, labelNames), labelNames: labelNames}
+}
+
+// NewGauge creates and registers a new Gauge.
+func (r *Registry) NewGauge(opts prometheus.GaugeOpts) *Gauge {
+	return &Gauge{gauge: r.factory.NewGauge(opts)}
+}
+
+// NewGaugeVec creates and registers a new GaugeVec.
+func (r *Registry) NewGaugeVec(opts prometheus.GaugeOpts, labelNames []string) *GaugeVec {
+	return &GaugeVec{gaugeVec: r.factory.NewGaugeVec(opts, labelNames), labelNames: labelNames}
+}
+
+// NewHistogram creates and registers a new Histogram.
+// The histogram keeps its own copy of opts.Buckets.
+func (r *Registry) NewHistogram(opts prometheus.HistogramOpts) *Histogram {
+	opts.Buckets = slices.Clone(opts.Buckets)
+	return &Histogram{histogram: r.factory.NewHistogram(opts)}
+}
+
+// NewHistogramVec creates and registers a new HistogramVec.
+// The histogram keeps its own copy of opts.Buckets.
+func (r *Registry) NewHistogramVec(opts prometheus.HistogramOpts, labelNames []string) *HistogramVec {
+	opts.Buckets = slices.Clone(opts.Buckets)
+	return &HistogramVec{histogramVec: r.factory.NewHistogramVec(opts, labelNames)}
+}
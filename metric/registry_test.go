@@ -0,0 +1,73 @@
+package metric
+
+import (
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+func TestNewWithRegistryDuplicateNames(t *testing.T) {
+	tests := []struct {
+		name   string
+		create func(r *Registry)
+	}{
+		{
+			name:   "counter",
+			create: func(r *Registry) { r.NewCounter(prometheus.CounterOpts{Name: "dup_total", Help: "test"}).Inc() },
+		},
+		{
+			name: "counter vec",
+			create: func(r *Registry) {
+				r.NewCounterVec(prometheus.CounterOpts{Name: "dup_total", Help: "test"}, []string{"method"}).Inc("GET")
+			},
+		},
+		{
+			name:   "gauge",
+			create: func(r *Registry) { r.NewGauge(prometheus.GaugeOpts{Name: "dup", Help: "test"}).Inc() },
+		},
+		{
+			name: "gauge vec",
+			create: func(r *Registry) {
+				r.NewGaugeVec(prometheus.GaugeOpts{Name: "dup", Help: "test"}, []string{"method"}).Inc("GET")
+			},
+		},
+		{
+			name:   "histogram",
+			create: func(r *Registry) { r.NewHistogram(prometheus.HistogramOpts{Name: "dup", Help: "test"}).Observe(1) },
+		},
+		{
+			name: "histogram vec",
+			create: func(r *Registry) {
+				r.NewHistogramVec(prometheus.HistogramOpts{Name: "dup", Help: "test"}, []string{"method"}).Observe(1, "GET")
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			defer func() {
+				if r := recover(); r != nil {
+					t.Fatalf("duplicate name in separate registries panicked: %v", r)
+				}
+			}()
+
+			regs := []*prometheus.Registry{prometheus.NewRegistry(), prometheus.NewRegistry()}
+			for _, reg := range regs {
+				tt.create(NewWithRegistry(reg))
+			}
+			// Unregistered metrics never collide
+			tt.create(NewWithRegistry(nil))
+			tt.create(NewWithRegistry(nil))
+
+			for i, reg := range regs {
+				families, err := reg.Gather()
+				if err != nil {
+					t.Fatal(err)
+				}
+				if len(families) != 1 || len(families[0].GetMetric()) != 1 {
+					t.Errorf("registry %d gathered %v, want one series", i, families)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,37 @@
+package metric
+
+import (
+	"sync"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+
+	"github.com/ssgohq/goten-core/logx"
+)
+
+var runtimeMetricsOnce sync.Once
+
+// RegisterRuntimeMetrics registers Go runtime metrics (goroutines, GC pauses,
+// heap and scheduler stats) and process metrics (CPU, resident memory, open
+// file descriptors) with the default registry, prefixed with "goten_", e.g.
+// goten_go_goroutines and goten_process_resident_memory_bytes. The prefix
+// keeps them apart from the unprefixed go_* and process_* metrics the
+// Prometheus client registers by default. It is safe to call more than once;
+// the metrics are registered on the first call only.
+func RegisterRuntimeMetrics() {
+	runtimeMetricsOnce.Do(func() {
+		reg := prometheus.WrapRegistererWithPrefix("goten_", prometheus.DefaultRegisterer)
+		for _, c := range []prometheus.Collector{
+			collectors.NewGoCollector(
+				collectors.WithGoCollectorRuntimeMetrics(
+					collectors.MetricsGC, collectors.MetricsMemory, collectors.MetricsScheduler,
+				),
+			),
+			collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+		} {
+			if err := reg.Register(c); err != nil {
+				logx.Warnw("Failed to register runtime metrics", "error", err)
+			}
+		}
+	})
+}
@@ -0,0 +1,51 @@
+package metric
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+
+	"github.com/ssgohq/goten-core/logx"
+)
+
+func TestRegisterRuntimeMetricsOnce(t *testing.T) {
+	core, logs := observer.New(zapcore.WarnLevel)
+	prev := logx.L()
+	logx.SetLogger(zap.New(core).Sugar())
+	t.Cleanup(func() { logx.SetLogger(prev) })
+
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			RegisterRuntimeMetrics()
+		}()
+	}
+	wg.Wait()
+	RegisterRuntimeMetrics()
+
+	if n := logs.Len(); n != 0 {
+		t.Errorf("%d registration warnings logged: %v", n, logs.All())
+	}
+	tests := []struct {
+		name string
+		want bool
+	}{
+		{name: "goten_go_goroutines", want: true},
+		{name: "goten_go_gc_duration_seconds", want: true},
+		{name: "goten_process_resident_memory_bytes", want: true},
+		{name: "goten_goten_go_goroutines", want: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := hasFamily(t, prometheus.DefaultGatherer, tt.name); got != tt.want {
+				t.Errorf("registered = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
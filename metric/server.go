@@ -3,9 +3,11 @@ package metric
 
 import (
 	"context"
+	"crypto/subtle"
 	"encoding/json"
 	"net/http"
 	"net/http/pprof"
+	"strings"
 	"sync"
 	"sync/atomic"
 	"time"
@@ -21,12 +23,22 @@ var (
 	defaultServer *Server
 )
 
+// ReadinessCheck reports whether the service's dependencies can serve
+// traffic. It is consulted by the readiness endpoint once the server has
+// been marked ready.
+type ReadinessCheck func(ctx context.Context) bool
+
 // Server is a standalone HTTP server for Prometheus metrics.
 type Server struct {
-	config Config
-	mux    *http.ServeMux
-	routes []string
-	ready  atomic.Bool
+	config     Config
+	mux        *http.ServeMux
+	routes     []string
+	routesOnce sync.Once
+	ready      atomic.Bool
+	readyCheck atomic.Pointer[ReadinessCheck]
+
+	mu     sync.Mutex
+	server *http.Server
 }
 
 // NewServer creates a new metrics server.
@@ -51,8 +63,8 @@ func (s *Server) addRoutes() {
 		_, _ = w.Write([]byte(s.config.HealthResponse))
 	})
 
-	s.handleFunc(s.config.ReadyPath, func(w http.ResponseWriter, _ *http.Request) {
-		if s.ready.Load() {
+	s.handleFunc(s.config.ReadyPath, func(w http.ResponseWriter, r *http.Request) {
+		if s.isReady(r.Context()) {
 			w.WriteHeader(http.StatusOK)
 			_, _ = w.Write([]byte("ready"))
 		} else {
@@ -62,18 +74,64 @@ func (s *Server) addRoutes() {
 	})
 
 	if s.config.EnableMetrics {
-		s.handleFunc(s.config.MetricsPath, promhttp.Handler().ServeHTTP)
+		if s.config.EnableRuntimeMetrics {
+			RegisterRuntimeMetrics()
+		}
+		s.handleFunc(s.config.MetricsPath, s.protect(promhttp.Handler().ServeHTTP))
+	}
+
+	if s.config.EnableLatency {
+		latencyEnabled.Store(true)
+		s.handleFunc(s.config.LatencyPath, s.protect(defaultLatency.ServeHTTP))
 	}
 
 	if s.config.EnablePprof {
-		s.handleFunc("/debug/pprof/", pprof.Index)
-		s.handleFunc("/debug/pprof/cmdline", pprof.Cmdline)
-		s.handleFunc("/debug/pprof/profile", pprof.Profile)
-		s.handleFunc("/debug/pprof/symbol", pprof.Symbol)
-		s.handleFunc("/debug/pprof/trace", pprof.Trace)
+		s.handleFunc("/debug/pprof/", s.protect(pprof.Index))
+		s.handleFunc("/debug/pprof/cmdline", s.protect(pprof.Cmdline))
+		s.handleFunc("/debug/pprof/profile", s.protect(pprof.Profile))
+		s.handleFunc("/debug/pprof/symbol", s.protect(pprof.Symbol))
+		s.handleFunc("/debug/pprof/trace", s.protect(pprof.Trace))
+	}
+}
+
+// protect wraps handler with the authentication configured for the server,
+// answering 401 to requests without valid credentials.
+func (s *Server) protect(handler http.HandlerFunc) http.HandlerFunc {
+	if !s.config.authEnabled() {
+		return handler
+	}
+	return func(w http.ResponseWriter, r *http.Request) {
+		if s.authorized(r) {
+			handler(w, r)
+			return
+		}
+		if s.config.BasicAuthUser != "" || s.config.BasicAuthPass != "" {
+			w.Header().Set("WWW-Authenticate", `Basic realm="metrics"`)
+		} else {
+			w.Header().Set("WWW-Authenticate", "Bearer")
+		}
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
 	}
 }
 
+// authorized checks the request's basic or bearer credentials in constant time.
+func (s *Server) authorized(r *http.Request) bool {
+	if token := s.config.BearerToken; token != "" {
+		if got, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer "); ok &&
+			subtle.ConstantTimeCompare([]byte(got), []byte(token)) == 1 {
+			return true
+		}
+	}
+	if s.config.basicAuthEnabled() {
+		if user, pass, ok := r.BasicAuth(); ok {
+			userOK := subtle.ConstantTimeCompare([]byte(user), []byte(s.config.BasicAuthUser)) == 1
+			passOK := subtle.ConstantTimeCompare([]byte(pass), []byte(s.config.BasicAuthPass)) == 1
+			return userOK && passOK
+		}
+	}
+	return false
+}
+
 func (s *Server) handleFunc(pattern string, handler http.HandlerFunc) {
 	s.mux.HandleFunc(pattern, handler)
 	s.routes = append(s.routes, pattern)
@@ -84,32 +142,82 @@ func (s *Server) SetReady(ready bool) {
 	s.ready.Store(ready)
 }
 
+// SetReadinessCheck sets a check that must also pass for the readiness
+// endpoint to report ready. A nil check removes it.
+func (s *Server) SetReadinessCheck(check ReadinessCheck) {
+	if check == nil {
+		s.readyCheck.Store(nil)
+		return
+	}
+	s.readyCheck.Store(&check)
+}
+
+// isReady combines the ready flag with the readiness check, if any.
+func (s *Server) isReady(ctx context.Context) bool {
+	if !s.ready.Load() {
+		return false
+	}
+	if check := s.readyCheck.Load(); check != nil {
+		return (*check)(ctx)
+	}
+	return true
+}
+
 // Start starts the metrics server in a goroutine.
+// It can be called again after Stop to restart the server.
+// An invalid configuration is logged; the server still starts, with
+// half-configured credentials rejecting every request to the protected
+// endpoints.
 func (s *Server) Start() {
-	s.addRoutes()
+	if err := s.config.Validate(); err != nil {
+		logx.Errorw("Invalid metrics server config", "error", err)
+	}
+	s.routesOnce.Do(s.addRoutes)
+
+	addr := s.config.Addr()
+	server := &http.Server{
+		Addr:              addr,
+		Handler:           s.mux,
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+	s.mu.Lock()
+	s.server = server
+	s.mu.Unlock()
+
 	go func() {
-		addr := s.config.Addr()
 		logx.Infow("Starting metrics server",
 			"addr", addr,
 			"metrics", s.config.MetricsPath,
 			"health", s.config.HealthPath,
 			"ready", s.config.ReadyPath,
 		)
-		server := &http.Server{
-			Addr:              addr,
-			Handler:           s.mux,
-			ReadHeaderTimeout: 10 * time.Second,
-		}
 		if err := server.ListenAndServe(); err != nil && err != http.ErrServerClosed {
 			logx.Errorw("Metrics server error", "error", err)
 		}
 	}()
 }
 
-// Stop gracefully shuts down the metrics server.
+// Stop gracefully shuts down the metrics server, waiting for in-flight
+// requests until ctx is done. It does nothing if the server is not running.
+// With Config.Push set, the metrics are pushed to the Pushgateway first;
+// a failed push is logged and does not prevent the shutdown.
 func (s *Server) Stop(ctx context.Context) error {
-	// For now, nothing to do since we start a new server each time
-	return nil
+	s.mu.Lock()
+	server := s.server
+	s.server = nil
+	s.mu.Unlock()
+
+	if server == nil {
+		return nil
+	}
+	if s.config.Push.IsEnabled() {
+		if err := Push(ctx, *s.config.Push); err != nil {
+			logx.Errorw("Failed to push final metrics", "error", err)
+		} else {
+			logx.Infow("Final metrics pushed", "url", s.config.Push.URL, "job", s.config.Push.Job)
+		}
+	}
+	return server.Shutdown(ctx)
 }
 
 // Name returns the service name for lifecycle management.
@@ -131,6 +239,15 @@ func StartAgent(c Config) {
 	})
 }
 
+// StopAgent gracefully shuts down the metric server started by StartAgent.
+// The agent is not started again by later StartAgent calls.
+func StopAgent(ctx context.Context) error {
+	if defaultServer == nil {
+		return nil
+	}
+	return defaultServer.Stop(ctx)
+}
+
 // SetReady marks the default metric server as ready for traffic.
 func SetReady(ready bool) {
 	if defaultServer != nil {
@@ -138,6 +255,13 @@ func SetReady(ready bool) {
 	}
 }
 
+// SetReadinessCheck sets the readiness check of the default metric server.
+func SetReadinessCheck(check ReadinessCheck) {
+	if defaultServer != nil {
+		defaultServer.SetReadinessCheck(check)
+	}
+}
+
 // IsStarted returns true if the metric server has been started.
 func IsStarted() bool {
 	return started.Load()
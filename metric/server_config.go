@@ -1,6 +1,9 @@
 package metric
 
-import "fmt"
+import (
+	"errors"
+	"fmt"
+)
 
 // Config is config for the metric/observability server.
 // This is an alias for compatibility with templates.
@@ -29,8 +32,38 @@ type Config struct {
 	// EnableMetrics enables Prometheus metrics endpoint.
 	EnableMetrics bool `yaml:"enableMetrics,omitempty" json:"enableMetrics,omitempty"`
 
+	// EnableRuntimeMetrics exports Go runtime and process metrics, such as
+	// goroutines, GC pauses and heap size, under the goten_ prefix. They are
+	// served on the metrics endpoint. See RegisterRuntimeMetrics.
+	EnableRuntimeMetrics bool `yaml:"enableRuntimeMetrics,omitempty" json:"enableRuntimeMetrics,omitempty"`
+
 	// EnablePprof enables pprof debug endpoints.
 	EnablePprof bool `yaml:"enablePprof,omitempty" json:"enablePprof,omitempty"`
+
+	// EnableLatency enables recording recent RPC and HTTP latencies and the
+	// JSON endpoint reporting their percentiles.
+	EnableLatency bool `yaml:"enableLatency,omitempty" json:"enableLatency,omitempty"`
+
+	// LatencyPath is the latency percentiles endpoint path.
+	// Default: "/debug/latency"
+	LatencyPath string `yaml:"latencyPath,omitempty" json:"latencyPath,omitempty"`
+
+	// BasicAuthUser and BasicAuthPass require HTTP basic authentication on
+	// the metrics, latency and pprof endpoints. Health and readiness
+	// endpoints stay open for probes. They must be set together; with only
+	// one of them set, Validate fails and the protected endpoints reject
+	// every request.
+	BasicAuthUser string `yaml:"basicAuthUser,omitempty" json:"basicAuthUser,omitempty"`
+	BasicAuthPass string `yaml:"basicAuthPass,omitempty" json:"basicAuthPass,omitempty"`
+
+	// BearerToken, when set, accepts "Authorization: Bearer <token>" on the
+	// same endpoints, alone or as an alternative to basic authentication.
+	BearerToken string `yaml:"bearerToken,omitempty" json:"bearerToken,omitempty"`
+
+	// Push, when set with a URL, pushes the metrics to a Pushgateway once
+	// more when the server stops, so that the final counts of a terminating
+	// instance are kept even if it is not scraped again.
+	Push *PushConfig `yaml:"push,omitempty" json:"push,omitempty"`
 }
 
 // SetDefaults applies default values.
@@ -53,6 +86,37 @@ func (c *Config) SetDefaults() {
 	if c.HealthResponse == "" {
 		c.HealthResponse = "OK"
 	}
+	if c.LatencyPath == "" {
+		c.LatencyPath = "/debug/latency"
+	}
+	if c.Push != nil {
+		c.Push.SetDefaults()
+	}
+}
+
+// Validate checks the configuration for invalid values.
+func (c *Config) Validate() error {
+	if (c.BasicAuthUser == "") != (c.BasicAuthPass == "") {
+		return errors.New("metric: basicAuthUser and basicAuthPass must be set together")
+	}
+	if c.Push != nil {
+		if err := c.Push.Validate(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// authEnabled returns true if the protected endpoints require credentials.
+// Any credential setting enables it, so that a half-configured basic
+// authentication fails closed.
+func (c *Config) authEnabled() bool {
+	return c.BasicAuthUser != "" || c.BasicAuthPass != "" || c.BearerToken != ""
+}
+
+// basicAuthEnabled returns true if basic credentials are accepted.
+func (c *Config) basicAuthEnabled() bool {
+	return c.BasicAuthUser != "" && c.BasicAuthPass != ""
 }
 
 // Addr returns the server address in host:port format.
@@ -0,0 +1,180 @@
+package metric
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+)
+
+// freePort returns a TCP port that was free a moment ago.
+func freePort(t *testing.T) int {
+	t.Helper()
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer l.Close()
+	return l.Addr().(*net.TCPAddr).Port
+}
+
+// waitHealth polls the server's health endpoint until it answers with
+// wantUp, failing the test after a second.
+func waitHealth(t *testing.T, url string, wantUp bool) {
+	t.Helper()
+	client := &http.Client{Timeout: 100 * time.Millisecond}
+	deadline := time.Now().Add(time.Second)
+	for {
+		resp, err := client.Get(url)
+		up := err == nil && resp.StatusCode == http.StatusOK
+		if resp != nil {
+			resp.Body.Close()
+		}
+		if up == wantUp {
+			return
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("GET %s: up = %v, want %v (err %v)", url, up, wantUp, err)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func TestServerStopAndRestart(t *testing.T) {
+	port := freePort(t)
+	s := NewServer(Config{Host: "127.0.0.1", Port: port, EnableMetrics: true})
+	url := "http://127.0.0.1:" + strconv.Itoa(port) + "/healthz"
+
+	for round := 1; round <= 2; round++ {
+		s.Start()
+		waitHealth(t, url, true)
+
+		ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+		err := s.Stop(ctx)
+		cancel()
+		if err != nil {
+			t.Fatalf("round %d: Stop() error = %v", round, err)
+		}
+		waitHealth(t, url, false)
+	}
+
+	// Stopping a stopped server is a no-op
+	if err := s.Stop(context.Background()); err != nil {
+		t.Errorf("Stop() on a stopped server = %v", err)
+	}
+}
+
+func TestServerAuth(t *testing.T) {
+	tests := []struct {
+		name   string
+		cfg    Config
+		path   string
+		header func(r *http.Request)
+		want   int
+	}{
+		{
+			name: "no auth configured",
+			cfg:  Config{EnableMetrics: true},
+			path: "/metrics",
+			want: http.StatusOK,
+		},
+		{
+			name: "basic missing credentials",
+			cfg:  Config{EnableMetrics: true, BasicAuthUser: "prom", BasicAuthPass: "secret"},
+			path: "/metrics",
+			want: http.StatusUnauthorized,
+		},
+		{
+			name:   "basic wrong password",
+			cfg:    Config{EnableMetrics: true, BasicAuthUser: "prom", BasicAuthPass: "secret"},
+			path:   "/metrics",
+			header: func(r *http.Request) { r.SetBasicAuth("prom", "nope") },
+			want:   http.StatusUnauthorized,
+		},
+		{
+			name:   "basic valid credentials",
+			cfg:    Config{EnableMetrics: true, BasicAuthUser: "prom", BasicAuthPass: "secret"},
+			path:   "/metrics",
+			header: func(r *http.Request) { r.SetBasicAuth("prom", "secret") },
+			want:   http.StatusOK,
+		},
+		{
+			name:   "bearer valid token",
+			cfg:    Config{EnableMetrics: true, BearerToken: "tok"},
+			path:   "/metrics",
+			header: func(r *http.Request) { r.Header.Set("Authorization", "Bearer tok") },
+			want:   http.StatusOK,
+		},
+		{
+			name:   "bearer wrong token",
+			cfg:    Config{EnableMetrics: true, BearerToken: "tok"},
+			path:   "/metrics",
+			header: func(r *http.Request) { r.Header.Set("Authorization", "Bearer other") },
+			want:   http.StatusUnauthorized,
+		},
+		{
+			name:   "user without password fails closed",
+			cfg:    Config{EnableMetrics: true, BasicAuthUser: "prom"},
+			path:   "/metrics",
+			header: func(r *http.Request) { r.SetBasicAuth("prom", "") },
+			want:   http.StatusUnauthorized,
+		},
+		{
+			name: "pprof protected",
+			cfg:  Config{EnablePprof: true, BearerToken: "tok"},
+			path: "/debug/pprof/",
+			want: http.StatusUnauthorized,
+		},
+		{
+			name: "health stays open",
+			cfg:  Config{EnableMetrics: true, BasicAuthUser: "prom", BasicAuthPass: "secret"},
+			path: "/healthz",
+			want: http.StatusOK,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := NewServer(tt.cfg)
+			s.routesOnce.Do(s.addRoutes)
+
+			r := httptest.NewRequest(http.MethodGet, tt.path, nil)
+			if tt.header != nil {
+				tt.header(r)
+			}
+			w := httptest.NewRecorder()
+			s.mux.ServeHTTP(w, r)
+
+			if w.Code != tt.want {
+				t.Fatalf("status = %d, want %d", w.Code, tt.want)
+			}
+			if w.Code == http.StatusUnauthorized && w.Header().Get("WWW-Authenticate") == "" {
+				t.Error("401 without WWW-Authenticate header")
+			}
+		})
+	}
+}
+
+func TestConfigValidateAuth(t *testing.T) {
+	tests := []struct {
+		name    string
+		cfg     Config
+		wantErr bool
+	}{
+		{name: "none", cfg: Config{}},
+		{name: "both", cfg: Config{BasicAuthUser: "u", BasicAuthPass: "p"}},
+		{name: "bearer only", cfg: Config{BearerToken: "t"}},
+		{name: "user only", cfg: Config{BasicAuthUser: "u"}, wantErr: true},
+		{name: "password only", cfg: Config{BasicAuthPass: "p", BearerToken: "t"}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.cfg.Validate(); (err != nil) != tt.wantErr {
+				t.Fatalf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,134 @@
+package metric
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// unregistered creates metrics without registering them anywhere.
+var unregistered = NewWithRegistry(nil)
+
+// NewUnregisteredCounter creates a Counter that is not registered. Call
+// Register to expose it, e.g. once a feature is enabled or with a custom
+// registry.
+//
+// Example:
+//
+//	hits := metric.NewUnregisteredCounter(prometheus.CounterOpts{Name: "cache_hits_total"})
+//	if cfg.EnableCache {
+//	    hits.MustRegister(nil)
+//	}
+func NewUnregisteredCounter(opts prometheus.CounterOpts) *Counter {
+	return unregistered.NewCounter(opts)
+}
+
+// NewUnregisteredCounterVec creates a CounterVec that is not registered.
+func NewUnregisteredCounterVec(opts prometheus.CounterOpts, labelNames []string) *CounterVec {
+	return unregistered.NewCounterVec(opts, labelNames)
+}
+
+// NewUnregisteredGauge creates a Gauge that is not registered.
+func NewUnregisteredGauge(opts prometheus.GaugeOpts) *Gauge {
+	return unregistered.NewGauge(opts)
+}
+
+// NewUnregisteredGaugeVec creates a GaugeVec that is not registered.
+func NewUnregisteredGaugeVec(opts prometheus.GaugeOpts, labelNames []string) *GaugeVec {
+	return unregistered.NewGaugeVec(opts, labelNames)
+}
+
+// NewUnregisteredHistogram creates a Histogram that is not registered.
+func NewUnregisteredHistogram(opts prometheus.HistogramOpts) *Histogram {
+	return unregistered.NewHistogram(opts)
+}
+
+// NewUnregisteredHistogramVec creates a HistogramVec that is not registered.
+func NewUnregisteredHistogramVec(opts prometheus.HistogramOpts, labelNames []string) *HistogramVec {
+	return unregistered.NewHistogramVec(opts, labelNames)
+}
+
+// register registers c with reg, or with the default registerer when reg is
+// nil.
+func register(reg prometheus.Registerer, c prometheus.Collector) error {
+	if reg == nil {
+		reg = prometheus.DefaultRegisterer
+	}
+	return reg.Register(c)
+}
+
+// Register registers the counter with reg, or with the default registerer
+// when reg is nil. It returns an error if the counter is already registered.
+func (c *Counter) Register(reg prometheus.Registerer) error {
+	return register(reg, c.counter)
+}
+
+// MustRegister is like Register but panics on error.
+func (c *Counter) MustRegister(reg prometheus.Registerer) {
+	if err := c.Register(reg); err != nil {
+		panic(err)
+	}
+}
+
+// Register registers the counter with reg, or with the default registerer
+// when reg is nil. It returns an error if the counter is already registered.
+func (c *CounterVec) Register(reg prometheus.Registerer) error {
+	return register(reg, c.counterVec)
+}
+
+// MustRegister is like Register but panics on error.
+func (c *CounterVec) MustRegister(reg prometheus.Registerer) {
+	if err := c.Register(reg); err != nil {
+		panic(err)
+	}
+}
+
+// Register registers the gauge with reg, or with the default registerer
+// when reg is nil. It returns an error if the gauge is already registered.
+func (g *Gauge) Register(reg prometheus.Registerer) error {
+	return register(reg, g.gauge)
+}
+
+// MustRegister is like Register but panics on error.
+func (g *Gauge) MustRegister(reg prometheus.Registerer) {
+	if err := g.Register(reg); err != nil {
+		panic(err)
+	}
+}
+
+// Register registers the gauge with reg, or with the default registerer
+// when reg is nil. It returns an error if the gauge is already registered.
+func (g *GaugeVec) Register(reg prometheus.Registerer) error {
+	return register(reg, g.gaugeVec)
+}
+
+// MustRegister is like Register but panics on error.
+func (g *GaugeVec) MustRegister(reg prometheus.Registerer) {
+	if err := g.Register(reg); err != nil {
+		panic(err)
+	}
+}
+
+// Register registers the histogram with reg, or with the default registerer
+// when reg is nil. It returns an error if the histogram is already registered.
+func (h *Histogram) Register(reg prometheus.Registerer) error {
+	return register(reg, h.histogram)
+}
+
+// MustRegister is like Register but panics on error.
+func (h *Histogram) MustRegister(reg prometheus.Registerer) {
+	if err := h.Register(reg); err != nil {
+		panic(err)
+	}
+}
+
+// Register registers the histogram with reg, or with the default registerer
+// when reg is nil. It returns an error if the histogram is already registered.
+func (h *HistogramVec) Register(reg prometheus.Registerer) error {
+	return register(reg, h.histogramVec)
+}
+
+// MustRegister is like Register but panics on error.
+func (h *HistogramVec) MustRegister(reg prometheus.Registerer) {
+	if err := h.Register(reg); err != nil {
+		panic(err)
+	}
+}
@@ -0,0 +1,90 @@
+package metric
+
+import (
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// registerer is the Register method shared by every metric wrapper.
+type registerer interface {
+	Register(reg prometheus.Registerer) error
+}
+
+func TestUnregisteredMetrics(t *testing.T) {
+	counter := NewUnregisteredCounter(prometheus.CounterOpts{Name: "test_unregistered_counter_total", Help: "test"})
+	counterVec := NewUnregisteredCounterVec(
+		prometheus.CounterOpts{Name: "test_unregistered_counter_vec_total", Help: "test"}, []string{"l"})
+	gauge := NewUnregisteredGauge(prometheus.GaugeOpts{Name: "test_unregistered_gauge", Help: "test"})
+	gaugeVec := NewUnregisteredGaugeVec(
+		prometheus.GaugeOpts{Name: "test_unregistered_gauge_vec", Help: "test"}, []string{"l"})
+	histogram := NewUnregisteredHistogram(prometheus.HistogramOpts{Name: "test_unregistered_histogram", Help: "test"})
+	histogramVec := NewUnregisteredHistogramVec(
+		prometheus.HistogramOpts{Name: "test_unregistered_histogram_vec", Help: "test"}, []string{"l"})
+
+	tests := []struct {
+		name   string
+		family string
+		metric registerer
+		use    func()
+	}{
+		{"counter", "test_unregistered_counter_total", counter, func() { counter.Inc() }},
+		{"counter vec", "test_unregistered_counter_vec_total", counterVec, func() { counterVec.Inc("a") }},
+		{"gauge", "test_unregistered_gauge", gauge, func() { gauge.Set(1) }},
+		{"gauge vec", "test_unregistered_gauge_vec", gaugeVec, func() { gaugeVec.Set(1, "a") }},
+		{"histogram", "test_unregistered_histogram", histogram, func() { histogram.Observe(1) }},
+		{"histogram vec", "test_unregistered_histogram_vec", histogramVec, func() { histogramVec.Observe(1, "a") }},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			reg := prometheus.NewRegistry()
+			tt.use()
+			if hasFamily(t, prometheus.DefaultGatherer, tt.family) {
+				t.Fatal("unregistered metric found in the default registry")
+			}
+
+			if err := tt.metric.Register(reg); err != nil {
+				t.Fatalf("Register() error = %v", err)
+			}
+			if !hasFamily(t, reg, tt.family) {
+				t.Error("metric missing from the registry after Register")
+			}
+			if err := tt.metric.Register(reg); err == nil {
+				t.Error("second Register() succeeded, want an already registered error")
+			}
+		})
+	}
+}
+
+func TestUnregisteredMustRegisterDefault(t *testing.T) {
+	c := NewUnregisteredCounter(prometheus.CounterOpts{Name: "test_unregistered_default_total", Help: "test"})
+	c.MustRegister(nil)
+	t.Cleanup(func() { prometheus.DefaultRegisterer.Unregister(c.counter) })
+	c.Inc()
+
+	if !hasFamily(t, prometheus.DefaultGatherer, "test_unregistered_default_total") {
+		t.Error("metric missing from the default registry after MustRegister(nil)")
+	}
+	defer func() {
+		if recover() == nil {
+			t.Error("second MustRegister did not panic")
+		}
+	}()
+	c.MustRegister(nil)
+}
+
+// hasFamily reports whether g gathers a metric family called name.
+func hasFamily(t *testing.T, g prometheus.Gatherer, name string) bool {
+	t.Helper()
+	families, err := g.Gather()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, mf := range families {
+		if mf.GetName() == name {
+			return true
+		}
+	}
+	return false
+}
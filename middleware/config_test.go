@@ -0,0 +1,33 @@
+package middleware
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestSetDefaultsIdempotent(t *testing.T) {
+	tests := []struct {
+		name string
+		cfg  interface{ SetDefaults() }
+	}{
+		{name: "cors", cfg: &CORSConfig{}},
+		{name: "cors with origin func", cfg: &CORSConfig{AllowOriginFunc: func(string) bool { return true }}},
+		{name: "gzip", cfg: &GzipConfig{}},
+		{name: "jwt", cfg: &JWTConfig{}},
+		{name: "metrics", cfg: &MetricsConfig{}},
+		{name: "rate limit", cfg: &RateLimitConfig{Rate: 2.5}},
+		{name: "secure", cfg: &SecureConfig{}},
+		{name: "force trace", cfg: &ForceTraceConfig{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.cfg.SetDefaults()
+			first := fmt.Sprintf("%+v", tt.cfg)
+			tt.cfg.SetDefaults()
+			if second := fmt.Sprintf("%+v", tt.cfg); second != first {
+				t.Errorf("second SetDefaults changed the config:\n first: %s\nsecond: %s", first, second)
+			}
+		})
+	}
+}
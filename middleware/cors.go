@@ -4,16 +4,23 @@ package middleware
 import (
 	"context"
 	"fmt"
+	"strings"
 
 	"github.com/cloudwego/hertz/pkg/app"
 )
 
 // CORSConfig represents CORS middleware configuration.
 type CORSConfig struct {
-	// AllowOrigins is a list of origins that may access the resource.
-	// Default: ["*"]
+	// AllowOrigins is a list of origins that may access the resource. An
+	// origin may hold one "*" standing for one or more subdomain labels,
+	// such as "https://*.example.com".
+	// Default: ["*"], unless AllowOriginFunc is set
 	AllowOrigins []string `yaml:"allowOrigins,omitempty" json:"allowOrigins,omitempty"`
 
+	// AllowOriginFunc reports whether an origin not listed in AllowOrigins
+	// may access the resource.
+	AllowOriginFunc func(origin string) bool `yaml:"-" json:"-"`
+
 	// AllowMethods is a list of methods allowed for the resource.
 	// Default: ["GET", "POST", "PUT", "PATCH", "DELETE", "HEAD", "OPTIONS"]
 	AllowMethods []string `yaml:"allowMethods,omitempty" json:"allowMethods,omitempty"`
@@ -35,7 +42,7 @@ type CORSConfig struct {
 
 // SetDefaults applies default values.
 func (c *CORSConfig) SetDefaults() {
-	if len(c.AllowOrigins) == 0 {
+	if len(c.AllowOrigins) == 0 && c.AllowOriginFunc == nil {
 		c.AllowOrigins = []string{"*"}
 	}
 	if len(c.AllowMethods) == 0 {
@@ -49,7 +56,16 @@ func (c *CORSConfig) SetDefaults() {
 	}
 }
 
-// CORS returns a CORS middleware handler.
+// CORS returns a CORS middleware handler. Unless every origin is allowed
+// with "*" and without credentials, the request origin is echoed in
+// Access-Control-Allow-Origin and responses get "Vary: Origin".
+//
+// Example:
+//
+//	h.Use(middleware.CORS(middleware.CORSConfig{
+//	    AllowOrigins:     []string{"https://example.com", "https://*.example.com"},
+//	    AllowCredentials: true,
+//	}))
 func CORS(cfg CORSConfig) app.HandlerFunc {
 	cfg.SetDefaults()
 
@@ -57,20 +73,45 @@ func CORS(cfg CORSConfig) app.HandlerFunc {
 	allowHeaders := joinStrings(cfg.AllowHeaders)
 	exposeHeaders := joinStrings(cfg.ExposeHeaders)
 
+	allowAll := false
+	origins := make(map[string]struct{}, len(cfg.AllowOrigins))
+	var patterns []originPattern
+	for _, o := range cfg.AllowOrigins {
+		switch {
+		case o == "*":
+			allowAll = true
+		case strings.Contains(o, "*"):
+			patterns = append(patterns, newOriginPattern(o))
+		default:
+			origins[strings.ToLower(o)] = struct{}{}
+		}
+	}
+	// "*" cannot be sent with credentials, so the origin is echoed instead
+	wildcard := allowAll && !cfg.AllowCredentials
+
 	return func(ctx context.Context, c *app.RequestContext) {
 		origin := string(c.Request.Header.Peek("Origin"))
 
 		// Check if origin is allowed
-		allowed := false
-		for _, o := range cfg.AllowOrigins {
-			if o == "*" || o == origin {
+		allowed := allowAll
+		if !allowed && origin != "" {
+			if _, ok := origins[strings.ToLower(origin)]; ok {
 				allowed = true
-				break
 			}
 		}
+		for i := 0; !allowed && origin != "" && i < len(patterns); i++ {
+			allowed = patterns[i].match(origin)
+		}
+		if !allowed && origin != "" && cfg.AllowOriginFunc != nil {
+			allowed = cfg.AllowOriginFunc(origin)
+		}
+
+		if !wildcard {
+			addVary(c, "Origin")
+		}
 
 		if allowed {
-			if cfg.AllowOrigins[0] == "*" && !cfg.AllowCredentials {
+			if wildcard {
 				c.Header("Access-Control-Allow-Origin", "*")
 			} else {
 				c.Header("Access-Control-Allow-Origin", origin)
@@ -98,6 +139,38 @@ func CORS(cfg CORSConfig) app.HandlerFunc {
 	}
 }
 
+// originPattern matches origins against an AllowOrigins entry holding a
+// "*", such as "https://*.example.com".
+type originPattern struct {
+	prefix, suffix string
+}
+
+// newOriginPattern parses pattern, panicking if it holds more than one "*".
+func newOriginPattern(pattern string) originPattern {
+	prefix, suffix, _ := strings.Cut(strings.ToLower(pattern), "*")
+	if strings.Contains(suffix, "*") {
+		panic(fmt.Errorf("invalid CORS origin pattern %q: only one \"*\" is allowed", pattern))
+	}
+	return originPattern{prefix: prefix, suffix: suffix}
+}
+
+// match reports whether origin matches the pattern. The "*" stands for
+// one or more characters of a host name, so that it cannot match across
+// the scheme, a port or user info.
+func (p originPattern) match(origin string) bool {
+	origin = strings.ToLower(origin)
+	if len(origin) <= len(p.prefix)+len(p.suffix) ||
+		!strings.HasPrefix(origin, p.prefix) || !strings.HasSuffix(origin, p.suffix) {
+		return false
+	}
+	for _, c := range origin[len(p.prefix) : len(origin)-len(p.suffix)] {
+		if c != '.' && c != '-' && (c < 'a' || c > 'z') && (c < '0' || c > '9') {
+			return false
+		}
+	}
+	return true
+}
+
 func joinStrings(strs []string) string {
 	if len(strs) == 0 {
 		return ""
@@ -0,0 +1,130 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/cloudwego/hertz/pkg/app"
+	"github.com/cloudwego/hertz/pkg/app/server"
+	"github.com/cloudwego/hertz/pkg/common/ut"
+)
+
+func TestCORSOrigins(t *testing.T) {
+	subdomains := CORSConfig{AllowOrigins: []string{"https://example.com", "https://*.example.com"}}
+
+	tests := []struct {
+		name            string
+		cfg             CORSConfig
+		origin          string
+		wantOrigin      string
+		wantCredentials bool
+		wantVary        bool
+	}{
+		{
+			name:       "exact origin",
+			cfg:        subdomains,
+			origin:     "https://example.com",
+			wantOrigin: "https://example.com",
+			wantVary:   true,
+		},
+		{
+			name:       "subdomain",
+			cfg:        subdomains,
+			origin:     "https://app.example.com",
+			wantOrigin: "https://app.example.com",
+			wantVary:   true,
+		},
+		{
+			name:       "nested subdomain, mixed case",
+			cfg:        subdomains,
+			origin:     "https://EU.App.example.com",
+			wantOrigin: "https://EU.App.example.com",
+			wantVary:   true,
+		},
+		{name: "other domain", cfg: subdomains, origin: "https://example.org", wantVary: true},
+		{name: "suffix trick", cfg: subdomains, origin: "https://evilexample.com", wantVary: true},
+		{name: "other scheme", cfg: subdomains, origin: "http://app.example.com", wantVary: true},
+		{name: "port", cfg: subdomains, origin: "https://app.example.com:8443", wantVary: true},
+		{name: "user info", cfg: subdomains, origin: "https://evil.com@app.example.com", wantVary: true},
+		{name: "empty label", cfg: subdomains, origin: "https://.example.com", wantVary: true},
+		{
+			name: "origin func",
+			cfg: CORSConfig{AllowOriginFunc: func(origin string) bool {
+				return strings.HasSuffix(origin, ".internal")
+			}},
+			origin:     "https://admin.internal",
+			wantOrigin: "https://admin.internal",
+			wantVary:   true,
+		},
+		{
+			name: "origin func rejects",
+			cfg: CORSConfig{AllowOriginFunc: func(origin string) bool {
+				return strings.HasSuffix(origin, ".internal")
+			}},
+			origin:   "https://example.com",
+			wantVary: true,
+		},
+		{name: "any origin", cfg: CORSConfig{}, origin: "https://example.com", wantOrigin: "*"},
+		{
+			name:            "any origin with credentials",
+			cfg:             CORSConfig{AllowCredentials: true},
+			origin:          "https://example.com",
+			wantOrigin:      "https://example.com",
+			wantCredentials: true,
+			wantVary:        true,
+		},
+		{
+			name:            "subdomain with credentials",
+			cfg:             CORSConfig{AllowOrigins: subdomains.AllowOrigins, AllowCredentials: true},
+			origin:          "https://app.example.com",
+			wantOrigin:      "https://app.example.com",
+			wantCredentials: true,
+			wantVary:        true,
+		},
+		{
+			name:     "rejected with credentials",
+			cfg:      CORSConfig{AllowOrigins: subdomains.AllowOrigins, AllowCredentials: true},
+			origin:   "https://example.org",
+			wantVary: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := server.New()
+			h.Use(CORS(tt.cfg))
+			h.GET("/", func(_ context.Context, c *app.RequestContext) {
+				c.String(http.StatusOK, "ok")
+			})
+
+			for _, method := range []string{http.MethodGet, http.MethodOptions} {
+				resp := ut.PerformRequest(h.Engine, method, "/", nil, ut.Header{Key: "Origin", Value: tt.origin}).Result()
+
+				if got := string(resp.Header.Peek("Access-Control-Allow-Origin")); got != tt.wantOrigin {
+					t.Errorf("%s: Access-Control-Allow-Origin = %q, want %q", method, got, tt.wantOrigin)
+				}
+				gotCredentials := string(resp.Header.Peek("Access-Control-Allow-Credentials")) == "true"
+				if gotCredentials != tt.wantCredentials {
+					t.Errorf("%s: credentials allowed = %v, want %v", method, gotCredentials, tt.wantCredentials)
+				}
+				if gotVary := strings.Contains(string(resp.Header.Peek("Vary")), "Origin"); gotVary != tt.wantVary {
+					t.Errorf("%s: Vary has Origin = %v, want %v", method, gotVary, tt.wantVary)
+				}
+				if method == http.MethodOptions && tt.wantOrigin != "" && resp.StatusCode() != http.StatusNoContent {
+					t.Errorf("preflight status = %d, want %d", resp.StatusCode(), http.StatusNoContent)
+				}
+			}
+		})
+	}
+}
+
+func TestCORSInvalidPattern(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Error("CORS() did not panic for a pattern with two \"*\"")
+		}
+	}()
+	CORS(CORSConfig{AllowOrigins: []string{"https://*.*.example.com"}})
+}
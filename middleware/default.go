@@ -0,0 +1,94 @@
+package middleware
+
+import "github.com/cloudwego/hertz/pkg/app"
+
+// DefaultConfig selects the middlewares installed by Default.
+type DefaultConfig struct {
+	// Recovery enables panic recovery. Default: true
+	Recovery *bool `yaml:"recovery,omitempty" json:"recovery,omitempty"`
+
+	// RequestID enables request ID propagation. Default: true
+	RequestID *bool `yaml:"requestId,omitempty" json:"requestId,omitempty"`
+
+	// AccessLog enables access logging. Default: true
+	AccessLog *bool `yaml:"accessLog,omitempty" json:"accessLog,omitempty"`
+
+	// Logging configures the access log.
+	Logging LoggingConfig `yaml:"logging,omitempty" json:"logging,omitempty"`
+
+	// EnableSecureHeaders enables the security headers middleware.
+	// Default: false
+	EnableSecureHeaders bool `yaml:"enableSecureHeaders,omitempty" json:"enableSecureHeaders,omitempty"`
+
+	// SecureHeaders configures the security headers middleware.
+	SecureHeaders SecureConfig `yaml:"secureHeaders,omitempty" json:"secureHeaders,omitempty"`
+
+	// EnableCORS enables the CORS middleware. Default: false
+	EnableCORS bool `yaml:"enableCors,omitempty" json:"enableCors,omitempty"`
+
+	// CORS configures the CORS middleware.
+	CORS CORSConfig `yaml:"cors,omitempty" json:"cors,omitempty"`
+}
+
+// IsRecoveryEnabled returns true unless Recovery is explicitly false.
+func (c DefaultConfig) IsRecoveryEnabled() bool {
+	return c.Recovery == nil || *c.Recovery
+}
+
+// IsRequestIDEnabled returns true unless RequestID is explicitly false.
+func (c DefaultConfig) IsRequestIDEnabled() bool {
+	return c.RequestID == nil || *c.RequestID
+}
+
+// IsAccessLogEnabled returns true unless AccessLog is explicitly false.
+func (c DefaultConfig) IsAccessLogEnabled() bool {
+	return c.AccessLog == nil || *c.AccessLog
+}
+
+// Default returns the recommended middleware chain, in order:
+//
+//  1. Recovery, outermost so that panics in any later middleware are caught
+//  2. RequestID, so that the access log and handlers see the ID
+//  3. AccessLog
+//  4. SecureHeaders, set before the handlers run so that they can override
+//     them, and also sent on CORS preflight responses
+//  5. CORS, so that preflight responses are logged too
+//
+// Disabled middlewares are left out.
+//
+// Example:
+//
+//	h.Use(middleware.Default(middleware.DefaultConfig{
+//	    EnableSecureHeaders: true,
+//	    EnableCORS:          true,
+//	})...)
+func Default(cfg DefaultConfig) []app.HandlerFunc {
+	handlers := make([]app.HandlerFunc, 0, 5)
+
+	// 1. Recovery
+	if cfg.IsRecoveryEnabled() {
+		handlers = append(handlers, Recovery())
+	}
+
+	// 2. Request ID
+	if cfg.IsRequestIDEnabled() {
+		handlers = append(handlers, RequestID())
+	}
+
+	// 3. Access log
+	if cfg.IsAccessLogEnabled() {
+		handlers = append(handlers, AccessLogWithConfig(cfg.Logging))
+	}
+
+	// 4. Security headers
+	if cfg.EnableSecureHeaders {
+		handlers = append(handlers, SecureHeaders(cfg.SecureHeaders))
+	}
+
+	// 5. CORS
+	if cfg.EnableCORS {
+		handlers = append(handlers, CORS(cfg.CORS))
+	}
+
+	return handlers
+}
@@ -0,0 +1,92 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/cloudwego/hertz/pkg/app"
+	"github.com/cloudwego/hertz/pkg/app/server"
+	"github.com/cloudwego/hertz/pkg/common/ut"
+)
+
+func TestDefault(t *testing.T) {
+	off := false
+	tests := []struct {
+		name         string
+		cfg          DefaultConfig
+		wantHandlers int
+		wantID       bool
+		wantSecure   bool
+		wantCORS     bool
+	}{
+		{
+			name:         "defaults",
+			cfg:          DefaultConfig{},
+			wantHandlers: 3,
+			wantID:       true,
+		},
+		{
+			name:         "everything",
+			cfg:          DefaultConfig{EnableSecureHeaders: true, EnableCORS: true},
+			wantHandlers: 5,
+			wantID:       true,
+			wantSecure:   true,
+			wantCORS:     true,
+		},
+		{
+			name:         "request id and access log off",
+			cfg:          DefaultConfig{RequestID: &off, AccessLog: &off, EnableSecureHeaders: true},
+			wantHandlers: 2,
+			wantSecure:   true,
+		},
+		{
+			name:         "everything off",
+			cfg:          DefaultConfig{Recovery: &off, RequestID: &off, AccessLog: &off},
+			wantHandlers: 0,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handlers := Default(tt.cfg)
+			if len(handlers) != tt.wantHandlers {
+				t.Fatalf("handlers = %d, want %d", len(handlers), tt.wantHandlers)
+			}
+
+			h := server.New()
+			h.Use(handlers...)
+			h.GET("/ok", func(_ context.Context, c *app.RequestContext) {
+				c.String(http.StatusOK, "ok")
+			})
+			h.GET("/panic", func(context.Context, *app.RequestContext) {
+				panic("boom")
+			})
+
+			// A preflight is answered by CORS, the last middleware, so the
+			// headers of the earlier ones show the chain order
+			w := ut.PerformRequest(h.Engine, http.MethodOptions, "/ok", nil,
+				ut.Header{Key: "Origin", Value: "https://example.com"})
+			resp := w.Result()
+			if got := resp.Header.Get("X-Request-ID") != ""; got != tt.wantID {
+				t.Errorf("X-Request-ID present = %v, want %v", got, tt.wantID)
+			}
+			if got := resp.Header.Get("X-Content-Type-Options") == "nosniff"; got != tt.wantSecure {
+				t.Errorf("security headers present = %v, want %v", got, tt.wantSecure)
+			}
+			if got := resp.Header.Get("Access-Control-Allow-Origin") != ""; got != tt.wantCORS {
+				t.Errorf("CORS headers present = %v, want %v", got, tt.wantCORS)
+			}
+			if tt.wantCORS && resp.StatusCode() != http.StatusNoContent {
+				t.Errorf("preflight status = %d, want 204", resp.StatusCode())
+			}
+
+			if tt.cfg.IsRecoveryEnabled() {
+				w = ut.PerformRequest(h.Engine, http.MethodGet, "/panic", nil)
+				if got := w.Result().StatusCode(); got != http.StatusInternalServerError {
+					t.Errorf("panic status = %d, want 500", got)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,53 @@
+package middleware
+
+import (
+	"context"
+
+	"github.com/cloudwego/hertz/pkg/app"
+
+	"github.com/ssgohq/goten-core/trace"
+)
+
+// ForceTraceConfig configures the force-trace middleware.
+type ForceTraceConfig struct {
+	// Header is the request header that asks for the request to be traced.
+	// Default: "X-Force-Trace"
+	Header string `yaml:"header,omitempty" json:"header,omitempty"`
+
+	// Values are the header values that force sampling, such as tokens
+	// handed to operators. Requests with any other value are sampled as
+	// usual. The middleware does nothing when Values is empty.
+	Values []string `yaml:"values,omitempty" json:"values,omitempty"`
+}
+
+// SetDefaults applies default values.
+func (c *ForceTraceConfig) SetDefaults() {
+	if c.Header == "" {
+		c.Header = trace.ForceSampleHeader
+	}
+}
+
+// ForceTrace returns a middleware that forces the trace of a request to be
+// sampled, regardless of the configured sampler and rate, when its header
+// carries one of the allowed values. It must run before the tracing
+// middleware, which NewHertzServer does with app.WithForceTrace.
+func ForceTrace(cfg ForceTraceConfig) app.HandlerFunc {
+	cfg.SetDefaults()
+
+	allowed := make(map[string]struct{}, len(cfg.Values))
+	for _, v := range cfg.Values {
+		allowed[v] = struct{}{}
+	}
+
+	return func(ctx context.Context, c *app.RequestContext) {
+		value := c.Request.Header.Peek(cfg.Header)
+		if len(value) == 0 {
+			c.Next(ctx)
+			return
+		}
+		if _, ok := allowed[string(value)]; ok {
+			ctx = trace.WithForceSample(ctx)
+		}
+		c.Next(ctx)
+	}
+}
@@ -0,0 +1,65 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"testing"
+
+	"github.com/cloudwego/hertz/pkg/app"
+	"github.com/cloudwego/hertz/pkg/app/server"
+	"github.com/cloudwego/hertz/pkg/common/ut"
+
+	"github.com/ssgohq/goten-core/trace"
+)
+
+func TestForceTrace(t *testing.T) {
+	tests := []struct {
+		name   string
+		cfg    ForceTraceConfig
+		header ut.Header
+		want   bool
+	}{
+		{
+			name:   "allowed value",
+			cfg:    ForceTraceConfig{Values: []string{"op-token"}},
+			header: ut.Header{Key: trace.ForceSampleHeader, Value: "op-token"},
+			want:   true,
+		},
+		{
+			name:   "other value",
+			cfg:    ForceTraceConfig{Values: []string{"op-token"}},
+			header: ut.Header{Key: trace.ForceSampleHeader, Value: "guess"},
+		},
+		{
+			name:   "no header",
+			cfg:    ForceTraceConfig{Values: []string{"op-token"}},
+			header: ut.Header{Key: "X-Other", Value: "op-token"},
+		},
+		{
+			name:   "no values",
+			header: ut.Header{Key: trace.ForceSampleHeader, Value: "op-token"},
+		},
+		{
+			name:   "custom header",
+			cfg:    ForceTraceConfig{Header: "X-Debug", Values: []string{"1"}},
+			header: ut.Header{Key: "X-Debug", Value: "1"},
+			want:   true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := server.New()
+			h.Use(ForceTrace(tt.cfg))
+			h.GET("/", func(ctx context.Context, c *app.RequestContext) {
+				c.String(http.StatusOK, strconv.FormatBool(trace.IsForceSampled(ctx)))
+			})
+
+			w := ut.PerformRequest(h.Engine, http.MethodGet, "/", nil, tt.header)
+			if got := string(w.Result().Body()); got != strconv.FormatBool(tt.want) {
+				t.Errorf("forced = %s, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,152 @@
+package middleware
+
+import (
+	"compress/gzip"
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/cloudwego/hertz/pkg/app"
+	"github.com/cloudwego/hertz/pkg/common/compress"
+)
+
+// GzipConfig represents gzip compression middleware configuration.
+type GzipConfig struct {
+	// Level is the compression level, from gzip.NoCompression (0) through
+	// gzip.BestSpeed (1) to gzip.BestCompression (9), or gzip.HuffmanOnly
+	// (-2). It is a pointer so that an unset level can be told apart from
+	// gzip.NoCompression.
+	// Default: gzip.DefaultCompression (-1)
+	Level *int `yaml:"level,omitempty" json:"level,omitempty"`
+
+	// MinLength is the smallest response body, in bytes, that is
+	// compressed. Smaller bodies gain little and cost CPU.
+	// Default: 1024
+	MinLength int `yaml:"minLength,omitempty" json:"minLength,omitempty"`
+
+	// ExcludedContentTypes lists content type prefixes that are never
+	// compressed, typically formats that are compressed already.
+	// Default: images, audio, video, fonts and common archives
+	ExcludedContentTypes []string `yaml:"excludedContentTypes,omitempty" json:"excludedContentTypes,omitempty"`
+
+	// Skipper determines whether to skip compression.
+	Skipper func(ctx context.Context, c *app.RequestContext) bool
+}
+
+// SetDefaults applies default values.
+func (c *GzipConfig) SetDefaults() {
+	if c.MinLength == 0 {
+		c.MinLength = 1024
+	}
+	if len(c.ExcludedContentTypes) == 0 {
+		c.ExcludedContentTypes = []string{
+			"image/", "audio/", "video/", "font/",
+			"application/gzip", "application/x-gzip", "application/zip",
+			"application/x-7z-compressed", "application/x-rar-compressed",
+			"application/x-bzip2", "application/zstd",
+		}
+	}
+}
+
+// CompressionLevel returns Level, or gzip.DefaultCompression when unset.
+func (c GzipConfig) CompressionLevel() int {
+	if c.Level == nil {
+		return gzip.DefaultCompression
+	}
+	return *c.Level
+}
+
+// Gzip returns a middleware that gzips response bodies of at least
+// MinLength bytes for clients sending "Accept-Encoding: gzip". Responses
+// that are streamed, already carry a Content-Encoding or have an excluded
+// content type are sent as they are. Responses that could be compressed get
+// "Vary: Accept-Encoding", so that caches keep both variants apart.
+//
+// Example:
+//
+//	level := gzip.BestSpeed
+//	h.Use(middleware.Gzip(middleware.GzipConfig{
+//	    Level:     &level,
+//	    MinLength: 2048,
+//	}))
+func Gzip(cfg GzipConfig) app.HandlerFunc {
+	cfg.SetDefaults()
+	level := cfg.CompressionLevel()
+	if level < gzip.HuffmanOnly || level > gzip.BestCompression {
+		panic(fmt.Errorf("invalid gzip compression level %d", level))
+	}
+
+	return func(ctx context.Context, c *app.RequestContext) {
+		// Check skipper
+		if cfg.Skipper != nil && cfg.Skipper(ctx, c) {
+			c.Next(ctx)
+			return
+		}
+
+		c.Next(ctx)
+
+		resp := &c.Response
+		if resp.IsBodyStream() || len(resp.Header.ContentEncoding()) > 0 {
+			return
+		}
+		if status := resp.StatusCode(); status < 200 || status == 204 || status == 304 {
+			return
+		}
+		body := resp.Body()
+		if len(body) < cfg.MinLength || excludedContentType(string(resp.Header.ContentType()), cfg.ExcludedContentTypes) {
+			return
+		}
+
+		addVary(c, "Accept-Encoding")
+		if !acceptsGzip(string(c.Request.Header.Peek("Accept-Encoding"))) {
+			return
+		}
+		resp.SetBody(compress.AppendGzipBytesLevel(nil, body, level))
+		resp.Header.SetContentEncoding("gzip")
+	}
+}
+
+// acceptsGzip reports whether an Accept-Encoding header value allows gzip.
+func acceptsGzip(header string) bool {
+	for _, part := range strings.Split(header, ",") {
+		coding, params, _ := strings.Cut(strings.TrimSpace(part), ";")
+		coding = strings.ToLower(strings.TrimSpace(coding))
+		if coding != "gzip" && coding != "*" {
+			continue
+		}
+		// A zero quality value refuses the coding
+		q := strings.ReplaceAll(strings.ToLower(params), " ", "")
+		if q == "q=0" || strings.HasPrefix(q, "q=0.") && strings.Trim(q[4:], "0") == "" {
+			continue
+		}
+		return true
+	}
+	return false
+}
+
+// excludedContentType reports whether contentType starts with one of the
+// excluded prefixes.
+func excludedContentType(contentType string, excluded []string) bool {
+	contentType = strings.ToLower(contentType)
+	for _, prefix := range excluded {
+		if strings.HasPrefix(contentType, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// addVary adds value to the Vary response header unless it is listed.
+func addVary(c *app.RequestContext, value string) {
+	vary := string(c.Response.Header.Peek("Vary"))
+	for _, v := range strings.Split(vary, ",") {
+		if strings.EqualFold(strings.TrimSpace(v), value) {
+			return
+		}
+	}
+	if vary == "" {
+		c.Response.Header.Set("Vary", value)
+		return
+	}
+	c.Response.Header.Set("Vary", vary+", "+value)
+}
@@ -0,0 +1,156 @@
+package middleware
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/cloudwego/hertz/pkg/app"
+	"github.com/cloudwego/hertz/pkg/app/server"
+	"github.com/cloudwego/hertz/pkg/common/ut"
+)
+
+func TestGzip(t *testing.T) {
+	large := `{"items":"` + strings.Repeat("goten ", 400) + `"}`
+	small := `{"ok":true}`
+
+	h := server.New()
+	h.Use(Gzip(GzipConfig{}))
+	h.GET("/large", func(_ context.Context, c *app.RequestContext) {
+		c.Data(http.StatusOK, "application/json", []byte(large))
+	})
+	h.GET("/small", func(_ context.Context, c *app.RequestContext) {
+		c.Data(http.StatusOK, "application/json", []byte(small))
+	})
+	h.GET("/image", func(_ context.Context, c *app.RequestContext) {
+		c.Data(http.StatusOK, "image/png", []byte(large))
+	})
+	h.GET("/encoded", func(_ context.Context, c *app.RequestContext) {
+		c.Response.Header.SetContentEncoding("br")
+		c.Data(http.StatusOK, "application/json", []byte(large))
+	})
+
+	tests := []struct {
+		name           string
+		path           string
+		acceptEncoding string
+		wantGzip       bool
+		wantVary       bool
+		wantBody       string
+	}{
+		{name: "large JSON", path: "/large", acceptEncoding: "gzip", wantGzip: true, wantVary: true, wantBody: large},
+		{
+			name:           "large JSON with qualities",
+			path:           "/large",
+			acceptEncoding: "br;q=1.0, gzip;q=0.5",
+			wantGzip:       true,
+			wantVary:       true,
+			wantBody:       large,
+		},
+		{name: "small JSON", path: "/small", acceptEncoding: "gzip", wantBody: small},
+		{name: "gzip not accepted", path: "/large", acceptEncoding: "br", wantVary: true, wantBody: large},
+		{name: "gzip refused", path: "/large", acceptEncoding: "gzip;q=0", wantVary: true, wantBody: large},
+		{name: "no Accept-Encoding", path: "/large", wantVary: true, wantBody: large},
+		{name: "excluded content type", path: "/image", acceptEncoding: "gzip", wantBody: large},
+		{name: "already encoded", path: "/encoded", acceptEncoding: "gzip", wantBody: large},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var headers []ut.Header
+			if tt.acceptEncoding != "" {
+				headers = append(headers, ut.Header{Key: "Accept-Encoding", Value: tt.acceptEncoding})
+			}
+			resp := ut.PerformRequest(h.Engine, http.MethodGet, tt.path, nil, headers...).Result()
+
+			gzipped := string(resp.Header.ContentEncoding()) == "gzip"
+			if gzipped != tt.wantGzip {
+				t.Fatalf("gzipped = %v, want %v", gzipped, tt.wantGzip)
+			}
+			if vary := string(resp.Header.Peek("Vary")) == "Accept-Encoding"; vary != tt.wantVary {
+				t.Errorf("Vary = %q, want Accept-Encoding: %v", resp.Header.Peek("Vary"), tt.wantVary)
+			}
+
+			body := resp.Body()
+			if gzipped {
+				if len(body) >= len(tt.wantBody) {
+					t.Errorf("gzipped body is %d bytes, not smaller than %d", len(body), len(tt.wantBody))
+				}
+				r, err := gzip.NewReader(bytes.NewReader(body))
+				if err != nil {
+					t.Fatal(err)
+				}
+				if body, err = io.ReadAll(r); err != nil {
+					t.Fatal(err)
+				}
+			}
+			if string(body) != tt.wantBody {
+				t.Errorf("body = %.40q..., want %.40q...", body, tt.wantBody)
+			}
+		})
+	}
+}
+
+func TestGzipLevel(t *testing.T) {
+	large := strings.Repeat("goten ", 400)
+	level := func(l int) *int { return &l }
+
+	tests := []struct {
+		name       string
+		level      *int
+		wantStored bool
+		wantPanic  bool
+	}{
+		{name: "default"},
+		{name: "no compression", level: level(gzip.NoCompression), wantStored: true},
+		{name: "best speed", level: level(gzip.BestSpeed)},
+		{name: "huffman only", level: level(gzip.HuffmanOnly)},
+		{name: "too high", level: level(10), wantPanic: true},
+		{name: "too low", level: level(-3), wantPanic: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var mw app.HandlerFunc
+			func() {
+				defer func() {
+					if r := recover(); (r != nil) != tt.wantPanic {
+						t.Errorf("panic = %v, want panic %v", r, tt.wantPanic)
+					}
+				}()
+				mw = Gzip(GzipConfig{Level: tt.level})
+			}()
+			if tt.wantPanic {
+				return
+			}
+
+			h := server.New()
+			h.Use(mw)
+			h.GET("/", func(_ context.Context, c *app.RequestContext) {
+				c.Data(http.StatusOK, "text/plain", []byte(large))
+			})
+			resp := ut.PerformRequest(h.Engine, http.MethodGet, "/", nil,
+				ut.Header{Key: "Accept-Encoding", Value: "gzip"}).Result()
+			if string(resp.Header.ContentEncoding()) != "gzip" {
+				t.Fatal("response not gzipped")
+			}
+
+			// Stored blocks keep the body as it is, plus framing
+			body := resp.Body()
+			if stored := len(body) > len(large); stored != tt.wantStored {
+				t.Errorf("gzipped body is %d bytes for %d, want stored %v", len(body), len(large), tt.wantStored)
+			}
+			r, err := gzip.NewReader(bytes.NewReader(body))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if body, err = io.ReadAll(r); err != nil || string(body) != large {
+				t.Errorf("decompressed body = %.40q..., %v, want %.40q...", body, err, large)
+			}
+		})
+	}
+}
@@ -0,0 +1,238 @@
+package middleware
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"net/http"
+	"sync"
+	"time"
+
+	"golang.org/x/sync/singleflight"
+
+	"github.com/ssgohq/goten-core/logx"
+)
+
+// ErrUnknownKeyID is returned when no key of the JWKS matches a token's kid.
+var ErrUnknownKeyID = errors.New("unknown JWT key id")
+
+const (
+	// jwksFetchTimeout bounds a JWKS request.
+	jwksFetchTimeout = 5 * time.Second
+	// jwksMinRefresh is the least time between two fetch attempts, failed
+	// or not, so that tokens with made-up kids or an unreachable identity
+	// provider cannot turn every request into a fetch.
+	jwksMinRefresh = 10 * time.Second
+	// jwksMaxBody bounds the size of a JWKS document, far above any real key
+	// set, so that a misbehaving endpoint cannot exhaust memory.
+	jwksMaxBody = 1 << 20
+)
+
+// jwks caches the public keys of a JSON Web Key Set by key id.
+type jwks struct {
+	url    string
+	ttl    time.Duration
+	client *http.Client
+	group  singleflight.Group
+
+	mu          sync.Mutex
+	keys        map[string]interface{}
+	fetchedAt   time.Time
+	attemptedAt time.Time
+	lastErr     error
+}
+
+func newJWKS(url string, ttl time.Duration) *jwks {
+	return &jwks{
+		url:    url,
+		ttl:    ttl,
+		client: &http.Client{Timeout: jwksFetchTimeout},
+	}
+}
+
+// key returns the public key with the given id. The key set is fetched
+// again when the cache is older than the TTL, or when the id is unknown so
+// that rotated keys are picked up, at most every jwksMinRefresh.
+//
+// Fetches run outside the lock, one at a time, with their own timeout. A
+// stale key is returned at once while the fetch runs in the background; an
+// unknown id waits for the fetch until ctx is done.
+func (s *jwks) key(ctx context.Context, kid string) (interface{}, error) {
+	s.mu.Lock()
+	key, ok := s.keys[kid]
+	stale := time.Since(s.fetchedAt) > s.ttl
+	due := time.Since(s.attemptedAt) > jwksMinRefresh
+	lastErr := s.lastErr
+	s.mu.Unlock()
+
+	if ok && !stale {
+		return key, nil
+	}
+	if !due {
+		// Keep serving cached keys while the provider is unreachable
+		if ok {
+			return key, nil
+		}
+		if lastErr != nil {
+			return nil, lastErr
+		}
+		return nil, fmt.Errorf("%w %q", ErrUnknownKeyID, kid)
+	}
+
+	done := s.group.DoChan("refresh", s.refreshOnce)
+	if ok {
+		return key, nil
+	}
+	select {
+	case res := <-done:
+		if res.Err != nil {
+			return nil, res.Err
+		}
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+
+	s.mu.Lock()
+	key, ok = s.keys[kid]
+	s.mu.Unlock()
+	if ok {
+		return key, nil
+	}
+	return nil, fmt.Errorf("%w %q", ErrUnknownKeyID, kid)
+}
+
+// refreshOnce records the attempt and fetches the key set, unless another
+// caller attempted it while this one was waiting for the singleflight slot.
+func (s *jwks) refreshOnce() (interface{}, error) {
+	s.mu.Lock()
+	if time.Since(s.attemptedAt) <= jwksMinRefresh {
+		err := s.lastErr
+		s.mu.Unlock()
+		return nil, err
+	}
+	s.attemptedAt = time.Now()
+	s.mu.Unlock()
+
+	ctx, cancel := context.WithTimeout(context.Background(), jwksFetchTimeout)
+	defer cancel()
+	keys, err := s.fetch(ctx)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.lastErr = err
+	if err != nil {
+		logx.Warnw("Failed to refresh JWKS", "url", s.url, "error", err)
+		return nil, err
+	}
+	s.keys = keys
+	s.fetchedAt = time.Now()
+	return nil, nil
+}
+
+// jsonWebKey holds the JWK members used for RSA and EC signature keys.
+type jsonWebKey struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Use string `json:"use"`
+	N   string `json:"n"`
+	E   string `json:"e"`
+	Crv string `json:"crv"`
+	X   string `json:"x"`
+	Y   string `json:"y"`
+}
+
+// fetch downloads and parses the key set. Keys that are not signature keys
+// or cannot be parsed are skipped.
+func (s *jwks) fetch(ctx context.Context) (map[string]interface{}, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, s.url, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("jwks: unexpected status %d from %s", resp.StatusCode, s.url)
+	}
+
+	var set struct {
+		Keys []jsonWebKey `json:"keys"`
+	}
+	if err := json.NewDecoder(io.LimitReader(resp.Body, jwksMaxBody)).Decode(&set); err != nil {
+		return nil, fmt.Errorf("jwks: decode %s: %w", s.url, err)
+	}
+
+	keys := make(map[string]interface{}, len(set.Keys))
+	for _, jwk := range set.Keys {
+		if jwk.Use != "" && jwk.Use != "sig" {
+			continue
+		}
+		key, err := jwk.publicKey()
+		if err != nil {
+			logx.Warnw("Skipping JWKS key", "kid", jwk.Kid, "error", err)
+			continue
+		}
+		keys[jwk.Kid] = key
+	}
+	return keys, nil
+}
+
+// publicKey decodes the key as an *rsa.PublicKey or *ecdsa.PublicKey.
+func (k jsonWebKey) publicKey() (interface{}, error) {
+	switch k.Kty {
+	case "RSA":
+		n, err := decodeBigInt(k.N)
+		if err != nil {
+			return nil, err
+		}
+		e, err := decodeBigInt(k.E)
+		if err != nil {
+			return nil, err
+		}
+		if !e.IsInt64() || e.Int64() > 1<<31-1 {
+			return nil, errors.New("jwks: RSA exponent too large")
+		}
+		return &rsa.PublicKey{N: n, E: int(e.Int64())}, nil
+	case "EC":
+		var curve elliptic.Curve
+		switch k.Crv {
+		case "P-256":
+			curve = elliptic.P256()
+		case "P-384":
+			curve = elliptic.P384()
+		case "P-521":
+			curve = elliptic.P521()
+		default:
+			return nil, fmt.Errorf("jwks: unsupported curve %q", k.Crv)
+		}
+		x, err := decodeBigInt(k.X)
+		if err != nil {
+			return nil, err
+		}
+		y, err := decodeBigInt(k.Y)
+		if err != nil {
+			return nil, err
+		}
+		return &ecdsa.PublicKey{Curve: curve, X: x, Y: y}, nil
+	default:
+		return nil, fmt.Errorf("jwks: unsupported key type %q", k.Kty)
+	}
+}
+
+// decodeBigInt decodes a base64url-encoded big-endian integer.
+func decodeBigInt(s string) (*big.Int, error) {
+	b, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return nil, fmt.Errorf("jwks: invalid key parameter: %w", err)
+	}
+	return new(big.Int).SetBytes(b), nil
+}
@@ -0,0 +1,280 @@
+package middleware
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"io"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/cloudwego/hertz/pkg/app"
+	"github.com/cloudwego/hertz/pkg/app/server"
+	"github.com/cloudwego/hertz/pkg/common/ut"
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// jwksServer serves a JWKS document whose keys can be swapped.
+type jwksServer struct {
+	*httptest.Server
+	hits atomic.Int32
+
+	mu     sync.Mutex
+	keys   []map[string]string
+	status int
+}
+
+func newJWKSServer(t *testing.T) *jwksServer {
+	s := &jwksServer{status: http.StatusOK}
+	s.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		s.hits.Add(1)
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		if s.status != http.StatusOK {
+			w.WriteHeader(s.status)
+			return
+		}
+		_ = json.NewEncoder(w).Encode(map[string]any{"keys": s.keys})
+	}))
+	t.Cleanup(s.Close)
+	return s
+}
+
+func (s *jwksServer) set(status int, keys ...map[string]string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.status = status
+	s.keys = keys
+}
+
+func b64(i *big.Int) string {
+	return base64.RawURLEncoding.EncodeToString(i.Bytes())
+}
+
+func rsaJWK(kid string, key *rsa.PublicKey) map[string]string {
+	return map[string]string{
+		"kty": "RSA", "kid": kid, "use": "sig",
+		"n": b64(key.N), "e": b64(big.NewInt(int64(key.E))),
+	}
+}
+
+func ecJWK(kid string, key *ecdsa.PublicKey) map[string]string {
+	return map[string]string{
+		"kty": "EC", "kid": kid, "crv": "P-256",
+		"x": b64(key.X), "y": b64(key.Y),
+	}
+}
+
+func signToken(t *testing.T, method jwt.SigningMethod, kid string, key any) string {
+	t.Helper()
+	token := jwt.NewWithClaims(method, jwt.MapClaims{
+		"sub": "user-1",
+		"exp": time.Now().Add(time.Hour).Unix(),
+	})
+	token.Header["kid"] = kid
+	s, err := token.SignedString(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return s
+}
+
+func TestJWTWithJWKS(t *testing.T) {
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ecKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	otherKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	srv := newJWKSServer(t)
+	srv.set(http.StatusOK, rsaJWK("rsa-1", &rsaKey.PublicKey), ecJWK("ec-1", &ecKey.PublicKey))
+
+	h := server.New()
+	h.Use(JWT(JWTConfig{JWKSURL: srv.URL}))
+	h.GET("/me", func(_ context.Context, c *app.RequestContext) {
+		c.String(http.StatusOK, "ok")
+	})
+
+	tests := []struct {
+		name  string
+		token string
+		want  int
+	}{
+		{name: "RS256", token: signToken(t, jwt.SigningMethodRS256, "rsa-1", rsaKey), want: http.StatusOK},
+		{name: "ES256", token: signToken(t, jwt.SigningMethodES256, "ec-1", ecKey), want: http.StatusOK},
+		{
+			name:  "wrong key for kid",
+			token: signToken(t, jwt.SigningMethodRS256, "rsa-1", otherKey),
+			want:  http.StatusUnauthorized,
+		},
+		{name: "unknown kid", token: signToken(t, jwt.SigningMethodRS256, "rsa-2", otherKey), want: http.StatusUnauthorized},
+		{
+			name:  "HS256 rejected",
+			token: signToken(t, jwt.SigningMethodHS256, "rsa-1", []byte("secret")),
+			want:  http.StatusUnauthorized,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := ut.PerformRequest(h.Engine, http.MethodGet, "/me", nil,
+				ut.Header{Key: "Authorization", Value: "Bearer " + tt.token})
+			if got := w.Result().StatusCode(); got != tt.want {
+				t.Fatalf("status = %d, want %d: %s", got, tt.want, w.Result().Body())
+			}
+		})
+	}
+}
+
+func TestJWKSRotation(t *testing.T) {
+	oldKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	newKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	srv := newJWKSServer(t)
+	srv.set(http.StatusOK, rsaJWK("old", &oldKey.PublicKey))
+	s := newJWKS(srv.URL, time.Hour)
+	ctx := context.Background()
+
+	if _, err := s.key(ctx, "old"); err != nil {
+		t.Fatalf("old key: %v", err)
+	}
+
+	// The provider rotates; within jwksMinRefresh the new kid is unknown
+	srv.set(http.StatusOK, rsaJWK("new", &newKey.PublicKey))
+	if _, err := s.key(ctx, "new"); !errors.Is(err, ErrUnknownKeyID) {
+		t.Fatalf("new key before refresh: err = %v, want ErrUnknownKeyID", err)
+	}
+	if got := srv.hits.Load(); got != 1 {
+		t.Fatalf("fetches = %d, want 1", got)
+	}
+
+	// Once jwksMinRefresh has passed, the unknown kid triggers a fetch
+	s.mu.Lock()
+	s.attemptedAt = time.Now().Add(-2 * jwksMinRefresh)
+	s.mu.Unlock()
+	key, err := s.key(ctx, "new")
+	if err != nil {
+		t.Fatalf("new key after refresh: %v", err)
+	}
+	if pub, ok := key.(*rsa.PublicKey); !ok || pub.N.Cmp(newKey.N) != 0 {
+		t.Fatalf("got key %v, want the rotated key", key)
+	}
+	if _, err := s.key(ctx, "old"); !errors.Is(err, ErrUnknownKeyID) {
+		t.Fatalf("old key after rotation: err = %v, want ErrUnknownKeyID", err)
+	}
+}
+
+func TestJWKSProviderDown(t *testing.T) {
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	srv := newJWKSServer(t)
+	srv.set(http.StatusOK, rsaJWK("k1", &rsaKey.PublicKey))
+	s := newJWKS(srv.URL, time.Millisecond)
+	ctx := context.Background()
+	if _, err := s.key(ctx, "k1"); err != nil {
+		t.Fatal(err)
+	}
+
+	// The cache goes stale while the provider is down
+	srv.set(http.StatusInternalServerError)
+	time.Sleep(5 * time.Millisecond)
+	s.mu.Lock()
+	s.attemptedAt = time.Time{}
+	s.mu.Unlock()
+
+	var wg sync.WaitGroup
+	for i := 0; i < 50; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, err := s.key(ctx, "k1"); err != nil {
+				t.Errorf("stale key not served: %v", err)
+			}
+			_, _ = s.key(ctx, "unknown")
+		}()
+	}
+	wg.Wait()
+
+	// Wait for the background refresh to finish
+	deadline := time.Now().Add(time.Second)
+	for {
+		s.mu.Lock()
+		failed := s.lastErr != nil
+		s.mu.Unlock()
+		if failed || time.Now().After(deadline) {
+			break
+		}
+		time.Sleep(time.Millisecond)
+	}
+	if got := srv.hits.Load(); got != 2 {
+		t.Fatalf("fetches = %d, want 2: one initial and one failed refresh", got)
+	}
+	if _, err := s.key(ctx, "unknown"); err == nil || errors.Is(err, ErrUnknownKeyID) {
+		t.Fatalf("unknown kid while provider down: err = %v, want the fetch error", err)
+	}
+}
+
+func TestJWKSBodyLimit(t *testing.T) {
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	key, err := json.Marshal(rsaJWK("k1", &rsaKey.PublicKey))
+	if err != nil {
+		t.Fatal(err)
+	}
+	head := `{"keys":[` + string(key) + `]`
+
+	tests := []struct {
+		name    string
+		size    int
+		wantErr bool
+	}{
+		{name: "at the limit", size: jwksMaxBody},
+		{name: "over the limit", size: jwksMaxBody + 1, wantErr: true},
+		{name: "far over the limit", size: 8 * jwksMaxBody, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Pad the document with whitespace up to size bytes
+			body := head + strings.Repeat(" ", tt.size-len(head)-1) + "}"
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+				_, _ = io.WriteString(w, body)
+			}))
+			t.Cleanup(srv.Close)
+
+			keys, err := newJWKS(srv.URL, time.Minute).fetch(context.Background())
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("fetch() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && keys["k1"] == nil {
+				t.Errorf("fetch() keys = %v, want k1", keys)
+			}
+		})
+	}
+}
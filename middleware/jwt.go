@@ -8,13 +8,46 @@ import (
 
 	"github.com/cloudwego/hertz/pkg/app"
 	"github.com/golang-jwt/jwt/v5"
+
+	"github.com/ssgohq/goten-core/internal/ctxkeys"
 )
 
 // JWTConfig represents JWT middleware configuration.
 type JWTConfig struct {
 	// Secret is the signing key for HS256 algorithm.
+	// New tokens should be signed with it.
 	Secret string `yaml:"secret,omitempty" json:"secret,omitempty"`
 
+	// Secrets are further HS256 keys accepted when verifying tokens, such
+	// as the previous Secret while it is being rotated out. Each key is
+	// tried in turn after Secret, so tokens signed before a rotation stay
+	// valid until they expire.
+	Secrets []string `yaml:"secrets,omitempty" json:"secrets,omitempty"`
+
+	// JWKSURL is the URL of a JSON Web Key Set, such as an OIDC provider's
+	// jwks_uri. When set, tokens are verified with RS256/384/512 or
+	// ES256/384/512 against the key selected by their kid header, and
+	// Secret and Secrets are not used.
+	JWKSURL string `yaml:"jwksURL,omitempty" json:"jwksURL,omitempty"`
+
+	// JWKSCacheTTL is how long fetched keys are used before the key set is
+	// fetched again. A token with an unknown kid also triggers a fetch, at
+	// most every 10 seconds, to pick up rotated keys.
+	// Default: 10m
+	JWKSCacheTTL time.Duration `yaml:"jwksCacheTTL,omitempty" json:"jwksCacheTTL,omitempty"`
+
+	// ExpectedIssuer, when set, rejects tokens whose iss claim differs or
+	// is missing.
+	ExpectedIssuer string `yaml:"expectedIssuer,omitempty" json:"expectedIssuer,omitempty"`
+
+	// ExpectedAudience, when set, rejects tokens whose aud claim does not
+	// contain it or is missing.
+	ExpectedAudience string `yaml:"expectedAudience,omitempty" json:"expectedAudience,omitempty"`
+
+	// RequireNotBefore rejects tokens without an nbf claim. A present nbf
+	// claim is always checked, so tokens are rejected before that time.
+	RequireNotBefore bool `yaml:"requireNotBefore,omitempty" json:"requireNotBefore,omitempty"`
+
 	// TokenLookup specifies where to find the token.
 	// Format: "<source>:<name>" where source is "header", "query", or "cookie".
 	// Default: "header:Authorization"
@@ -45,7 +78,10 @@ func (c *JWTConfig) SetDefaults() {
 		c.AuthScheme = "Bearer"
 	}
 	if c.ContextKey == "" {
-		c.ContextKey = "jwt"
+		c.ContextKey = ctxkeys.JWTClaimsString
+	}
+	if c.JWKSCacheTTL == 0 {
+		c.JWKSCacheTTL = 10 * time.Minute
 	}
 }
 
@@ -56,6 +92,11 @@ var (
 	ErrTokenExpired  = errors.New("JWT token has expired")
 	ErrMissingSecret = errors.New("missing JWT secret")
 	ErrInvalidLookup = errors.New("invalid token lookup format")
+
+	ErrTokenNotValidYet = errors.New("JWT token is not valid yet")
+	ErrInvalidIssuer    = errors.New("JWT token has an unexpected issuer")
+	ErrInvalidAudience  = errors.New("JWT token has an unexpected audience")
+	ErrMissingClaim     = errors.New("JWT token is missing a required claim")
 )
 
 // JWT returns a JWT authentication middleware.
@@ -69,6 +110,33 @@ func JWT(cfg JWTConfig) app.HandlerFunc {
 	}
 	source, name := parts[0], parts[1]
 
+	// Key resolution: JWKS when configured, else the shared secrets
+	var keySet *jwks
+	var secrets jwt.VerificationKeySet
+	var parserOpts []jwt.ParserOption
+	for _, secret := range append([]string{cfg.Secret}, cfg.Secrets...) {
+		if secret != "" {
+			secrets.Keys = append(secrets.Keys, []byte(secret))
+		}
+	}
+	if cfg.JWKSURL != "" {
+		keySet = newJWKS(cfg.JWKSURL, cfg.JWKSCacheTTL)
+		parserOpts = append(parserOpts, jwt.WithValidMethods([]string{
+			"RS256", "RS384", "RS512", "ES256", "ES384", "ES512",
+		}))
+	}
+
+	// Registered claim checks, run by the parser after the signature
+	if cfg.ExpectedIssuer != "" {
+		parserOpts = append(parserOpts, jwt.WithIssuer(cfg.ExpectedIssuer))
+	}
+	if cfg.ExpectedAudience != "" {
+		parserOpts = append(parserOpts, jwt.WithAudience(cfg.ExpectedAudience))
+	}
+	if cfg.RequireNotBefore {
+		parserOpts = append(parserOpts, jwt.WithNotBeforeRequired())
+	}
+
 	return func(ctx context.Context, c *app.RequestContext) {
 		// Check skipper
 		if cfg.Skipper != nil && cfg.Skipper(ctx, c) {
@@ -106,18 +174,18 @@ func JWT(cfg JWTConfig) app.HandlerFunc {
 			claims = cfg.Claims
 		}
 
-		token, err := jwt.ParseWithClaims(tokenString, claims, func(_ *jwt.Token) (interface{}, error) {
-			if cfg.Secret == "" {
+		token, err := jwt.ParseWithClaims(tokenString, claims, func(t *jwt.Token) (interface{}, error) {
+			if keySet != nil {
+				kid, _ := t.Header["kid"].(string)
+				return keySet.key(ctx, kid)
+			}
+			if len(secrets.Keys) == 0 {
 				return nil, ErrMissingSecret
 			}
-			return []byte(cfg.Secret), nil
-		})
+			return secrets, nil
+		}, parserOpts...)
 		if err != nil {
-			if errors.Is(err, jwt.ErrTokenExpired) {
-				c.AbortWithMsg(ErrTokenExpired.Error(), 401)
-				return
-			}
-			c.AbortWithMsg(ErrInvalidToken.Error(), 401)
+			c.AbortWithMsg(tokenError(err).Error(), 401)
 			return
 		}
 
@@ -128,14 +196,32 @@ func JWT(cfg JWTConfig) app.HandlerFunc {
 
 		// Store claims in context
 		c.Set(cfg.ContextKey, token.Claims)
-		c.Next(ctx)
+		c.Next(ctxkeys.WithJWTClaims(ctx, token.Claims))
+	}
+}
+
+// tokenError maps a parser error to the error reported to the client.
+func tokenError(err error) error {
+	switch {
+	case errors.Is(err, jwt.ErrTokenExpired):
+		return ErrTokenExpired
+	case errors.Is(err, jwt.ErrTokenNotValidYet):
+		return ErrTokenNotValidYet
+	case errors.Is(err, jwt.ErrTokenInvalidIssuer):
+		return ErrInvalidIssuer
+	case errors.Is(err, jwt.ErrTokenInvalidAudience):
+		return ErrInvalidAudience
+	case errors.Is(err, jwt.ErrTokenRequiredClaimMissing):
+		return ErrMissingClaim
+	default:
+		return ErrInvalidToken
 	}
 }
 
 // GetClaims extracts JWT claims from the request context.
 func GetClaims(c *app.RequestContext, key string) jwt.Claims {
 	if key == "" {
-		key = "jwt"
+		key = ctxkeys.JWTClaimsString
 	}
 	if claims, exists := c.Get(key); exists {
 		if jwtClaims, ok := claims.(jwt.Claims); ok {
@@ -145,6 +231,13 @@ func GetClaims(c *app.RequestContext, key string) jwt.Claims {
 	return nil
 }
 
+// ClaimsFromContext returns the JWT claims stored by the JWT middleware in
+// the context passed to later handlers, or nil if none.
+func ClaimsFromContext(ctx context.Context) jwt.Claims {
+	claims, _ := ctxkeys.JWTClaims(ctx).(jwt.Claims)
+	return claims
+}
+
 // GenerateToken generates a new JWT token.
 func GenerateToken(secret string, claims jwt.MapClaims, expiry time.Duration) (string, error) {
 	if claims == nil {
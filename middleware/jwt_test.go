@@ -0,0 +1,172 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/cloudwego/hertz/pkg/app"
+	"github.com/cloudwego/hertz/pkg/app/server"
+	"github.com/cloudwego/hertz/pkg/common/ut"
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// performJWT sends a request with token through the JWT middleware built
+// from cfg and returns the status and body.
+func performJWT(t *testing.T, cfg JWTConfig, token string) (int, string) {
+	t.Helper()
+	h := server.New()
+	h.Use(JWT(cfg))
+	h.GET("/me", func(_ context.Context, c *app.RequestContext) {
+		c.String(http.StatusOK, "ok")
+	})
+	w := ut.PerformRequest(h.Engine, http.MethodGet, "/me", nil,
+		ut.Header{Key: "Authorization", Value: "Bearer " + token})
+	return w.Result().StatusCode(), string(w.Result().Body())
+}
+
+func TestJWTRegisteredClaims(t *testing.T) {
+	const secret = "test-secret"
+	now := time.Now()
+	strict := JWTConfig{
+		Secret:           secret,
+		ExpectedIssuer:   "https://issuer.example.com",
+		ExpectedAudience: "orders",
+		RequireNotBefore: true,
+	}
+	valid := func() jwt.MapClaims {
+		return jwt.MapClaims{
+			"iss": "https://issuer.example.com",
+			"aud": []string{"billing", "orders"},
+			"nbf": now.Add(-time.Minute).Unix(),
+		}
+	}
+
+	tests := []struct {
+		name     string
+		cfg      JWTConfig
+		claims   func(jwt.MapClaims)
+		wantCode int
+		wantBody string
+	}{
+		{name: "all claims valid", cfg: strict, wantCode: http.StatusOK, wantBody: "ok"},
+		{
+			name:     "wrong issuer",
+			cfg:      strict,
+			claims:   func(c jwt.MapClaims) { c["iss"] = "https://evil.example.com" },
+			wantCode: http.StatusUnauthorized,
+			wantBody: ErrInvalidIssuer.Error(),
+		},
+		{
+			name:     "missing issuer",
+			cfg:      strict,
+			claims:   func(c jwt.MapClaims) { delete(c, "iss") },
+			wantCode: http.StatusUnauthorized,
+			wantBody: ErrMissingClaim.Error(),
+		},
+		{
+			name:     "wrong audience",
+			cfg:      strict,
+			claims:   func(c jwt.MapClaims) { c["aud"] = "billing" },
+			wantCode: http.StatusUnauthorized,
+			wantBody: ErrInvalidAudience.Error(),
+		},
+		{
+			name:     "missing audience",
+			cfg:      strict,
+			claims:   func(c jwt.MapClaims) { delete(c, "aud") },
+			wantCode: http.StatusUnauthorized,
+			wantBody: ErrMissingClaim.Error(),
+		},
+		{
+			name:     "not valid yet",
+			cfg:      strict,
+			claims:   func(c jwt.MapClaims) { c["nbf"] = now.Add(time.Hour).Unix() },
+			wantCode: http.StatusUnauthorized,
+			wantBody: ErrTokenNotValidYet.Error(),
+		},
+		{
+			name:     "missing required nbf",
+			cfg:      strict,
+			claims:   func(c jwt.MapClaims) { delete(c, "nbf") },
+			wantCode: http.StatusUnauthorized,
+			wantBody: ErrMissingClaim.Error(),
+		},
+		{
+			name:     "future nbf checked without RequireNotBefore",
+			cfg:      JWTConfig{Secret: secret},
+			claims:   func(c jwt.MapClaims) { c["nbf"] = now.Add(time.Hour).Unix() },
+			wantCode: http.StatusUnauthorized,
+			wantBody: ErrTokenNotValidYet.Error(),
+		},
+		{
+			name:     "claims not checked when not configured",
+			cfg:      JWTConfig{Secret: secret},
+			claims:   func(c jwt.MapClaims) { delete(c, "iss"); delete(c, "aud"); delete(c, "nbf") },
+			wantCode: http.StatusOK,
+			wantBody: "ok",
+		},
+		{
+			name:     "expired",
+			cfg:      strict,
+			claims:   func(c jwt.MapClaims) { c["exp"] = now.Add(-time.Minute).Unix() },
+			wantCode: http.StatusUnauthorized,
+			wantBody: ErrTokenExpired.Error(),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			claims := valid()
+			if tt.claims != nil {
+				tt.claims(claims)
+			}
+			token, err := GenerateToken(secret, claims, time.Hour)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			code, body := performJWT(t, tt.cfg, token)
+			if code != tt.wantCode || body != tt.wantBody {
+				t.Errorf("response = %d %q, want %d %q", code, body, tt.wantCode, tt.wantBody)
+			}
+		})
+	}
+}
+
+func TestJWTSecretRotation(t *testing.T) {
+	const current, previous, older = "current-secret", "previous-secret", "older-secret"
+	rotating := JWTConfig{Secret: current, Secrets: []string{previous}}
+
+	tests := []struct {
+		name     string
+		cfg      JWTConfig
+		signWith string
+		wantCode int
+	}{
+		{name: "current secret", cfg: rotating, signWith: current, wantCode: http.StatusOK},
+		{name: "previous secret", cfg: rotating, signWith: previous, wantCode: http.StatusOK},
+		{
+			name:     "any listed secret",
+			cfg:      JWTConfig{Secret: current, Secrets: []string{previous, older}},
+			signWith: older,
+			wantCode: http.StatusOK,
+		},
+		{name: "retired secret", cfg: rotating, signWith: older, wantCode: http.StatusUnauthorized},
+		{name: "secrets only", cfg: JWTConfig{Secrets: []string{previous}}, signWith: previous, wantCode: http.StatusOK},
+		{name: "no secret", cfg: JWTConfig{}, signWith: current, wantCode: http.StatusUnauthorized},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			token, err := GenerateToken(tt.signWith, jwt.MapClaims{"sub": "user-1"}, time.Hour)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if code, body := performJWT(t, tt.cfg, token); code != tt.wantCode {
+				t.Errorf("status = %d (%s), want %d", code, body, tt.wantCode)
+			}
+		})
+	}
+}
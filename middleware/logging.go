@@ -3,17 +3,22 @@ package middleware
 import (
 	"context"
 	"fmt"
+	"os"
 	"runtime/debug"
 	"time"
 
 	"github.com/cloudwego/hertz/pkg/app"
 	"github.com/google/uuid"
 
+	"github.com/ssgohq/goten-core/internal/ctxkeys"
 	"github.com/ssgohq/goten-core/logx"
+	"github.com/ssgohq/goten-core/metric"
 	"github.com/ssgohq/goten-core/trace"
 )
 
 // RequestID returns a middleware that adds a request ID to the context and response headers.
+// The ID is available to later handlers through RequestIDFromContext and,
+// for compatibility, under the "requestID" RequestContext key.
 func RequestID() app.HandlerFunc {
 	return func(ctx context.Context, c *app.RequestContext) {
 		// Check for existing request ID
@@ -23,13 +28,19 @@ func RequestID() app.HandlerFunc {
 		}
 
 		// Set request ID in context and response
-		c.Set("requestID", requestID)
+		c.Set(ctxkeys.RequestIDString, requestID)
 		c.Header("X-Request-ID", requestID)
 
-		c.Next(ctx)
+		c.Next(ctxkeys.WithRequestID(ctx, requestID))
 	}
 }
 
+// RequestIDFromContext returns the request ID set by the RequestID
+// middleware, or "" if none.
+func RequestIDFromContext(ctx context.Context) string {
+	return ctxkeys.RequestID(ctx)
+}
+
 // AccessLog returns a middleware that logs HTTP requests.
 func AccessLog() app.HandlerFunc {
 	return func(ctx context.Context, c *app.RequestContext) {
@@ -44,24 +55,8 @@ func AccessLog() app.HandlerFunc {
 		duration := time.Since(start)
 		status := c.Response.StatusCode()
 
-		fields := []interface{}{
-			"method", method,
-			"path", path,
-			"status", status,
-			"duration", duration.String(),
-			"duration_ms", duration.Milliseconds(),
-			"client_ip", c.ClientIP(),
-		}
-
-		// Add request ID if present
-		if requestID, exists := c.Get("requestID"); exists {
-			fields = append(fields, "request_id", requestID)
-		}
-
-		// Add trace ID if present
-		if traceID := trace.TraceIDFromContext(ctx); traceID != "" {
-			fields = append(fields, "trace_id", traceID)
-		}
+		metric.RecordLatency(latencySeries(c), duration)
+		fields := accessLogFields(ctx, c, method, path, status, duration)
 
 		// Log based on status code
 		if status >= 500 {
@@ -74,7 +69,47 @@ func AccessLog() app.HandlerFunc {
 	}
 }
 
+// latencySeries returns the name of the latency series of a request, such
+// as "http GET /users/:id", keyed by the matched route like Metrics so that
+// the series stay bounded.
+func latencySeries(c *app.RequestContext) string {
+	return "http " + string(c.Request.Method()) + " " + routePath(c)
+}
+
+// accessLogFieldsCap is the number of key-value entries an access log line
+// can hold, so that the fields slice is allocated once per request.
+const accessLogFieldsCap = 16
+
+// accessLogFields returns the fields of an HTTP access log line.
+func accessLogFields(
+	ctx context.Context, c *app.RequestContext, method, path string, status int, duration time.Duration,
+) []interface{} {
+	fields := make([]interface{}, 0, accessLogFieldsCap)
+	fields = append(fields,
+		"method", method,
+		"path", path,
+		"status", status,
+		"duration", duration.String(),
+		"duration_ms", duration.Milliseconds(),
+		"client_ip", c.ClientIP(),
+	)
+
+	// Add request ID if present
+	if requestID, exists := c.Get(ctxkeys.RequestIDString); exists {
+		fields = append(fields, "request_id", requestID)
+	}
+
+	// Add trace ID if present
+	if traceID := trace.TraceIDFromContext(ctx); traceID != "" {
+		fields = append(fields, "trace_id", traceID)
+	}
+
+	return fields
+}
+
 // Recovery returns a middleware that recovers from panics.
+// After logging the panic it dumps the recent log entries to stderr when
+// logx keeps a ring buffer (see logx.DumpRecentForPanic).
 func Recovery() app.HandlerFunc {
 	return func(ctx context.Context, c *app.RequestContext) {
 		defer func() {
@@ -86,6 +121,7 @@ func Recovery() app.HandlerFunc {
 					"path", string(c.Request.URI().Path()),
 					"method", string(c.Request.Method()),
 				)
+				_ = logx.DumpRecentForPanic(os.Stderr)
 				c.AbortWithStatus(500)
 			}
 		}()
@@ -128,24 +164,8 @@ func AccessLogWithConfig(cfg LoggingConfig) app.HandlerFunc {
 		duration := time.Since(start)
 		status := c.Response.StatusCode()
 
-		fields := []interface{}{
-			"method", method,
-			"path", path,
-			"status", status,
-			"duration", duration.String(),
-			"duration_ms", duration.Milliseconds(),
-			"client_ip", c.ClientIP(),
-		}
-
-		// Add request ID if present
-		if requestID, exists := c.Get("requestID"); exists {
-			fields = append(fields, "request_id", requestID)
-		}
-
-		// Add trace ID if present
-		if traceID := trace.TraceIDFromContext(ctx); traceID != "" {
-			fields = append(fields, "trace_id", traceID)
-		}
+		metric.RecordLatency(latencySeries(c), duration)
+		fields := accessLogFields(ctx, c, method, path, status, duration)
 
 		// Check slow threshold
 		if cfg.SlowThreshold > 0 && duration > cfg.SlowThreshold {
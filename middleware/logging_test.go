@@ -0,0 +1,156 @@
+package middleware
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/cloudwego/hertz/pkg/app"
+	"github.com/cloudwego/hertz/pkg/app/server"
+	"github.com/cloudwego/hertz/pkg/common/ut"
+	"go.uber.org/zap"
+
+	"github.com/ssgohq/goten-core/internal/ctxkeys"
+	"github.com/ssgohq/goten-core/logx"
+)
+
+// newLoggedContext returns a RequestContext for a request to path, with
+// the request ID key set when requestID is not empty.
+func newLoggedContext(path, requestID string) *app.RequestContext {
+	c := app.NewContext(0)
+	c.Request.SetRequestURI(path)
+	c.Request.Header.SetMethod("GET")
+	if requestID != "" {
+		c.Set(ctxkeys.RequestIDString, requestID)
+	}
+	return c
+}
+
+func TestAccessLogFields(t *testing.T) {
+	tests := []struct {
+		name      string
+		requestID string
+		wantLen   int
+	}{
+		{name: "without request id", wantLen: 12},
+		{name: "with request id", requestID: "req-1", wantLen: 14},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := newLoggedContext("/users", tt.requestID)
+			fields := accessLogFields(context.Background(), c, "GET", "/users", 200, 1500*time.Millisecond)
+
+			if len(fields) != tt.wantLen {
+				t.Fatalf("len(fields) = %d, want %d: %v", len(fields), tt.wantLen, fields)
+			}
+			if cap(fields) != accessLogFieldsCap {
+				t.Errorf("cap(fields) = %d, want %d", cap(fields), accessLogFieldsCap)
+			}
+			got := make(map[string]interface{}, len(fields)/2)
+			for i := 0; i < len(fields); i += 2 {
+				got[fields[i].(string)] = fields[i+1]
+			}
+			want := map[string]interface{}{
+				"method":      "GET",
+				"path":        "/users",
+				"status":      200,
+				"duration":    "1.5s",
+				"duration_ms": int64(1500),
+			}
+			if tt.requestID != "" {
+				want["request_id"] = tt.requestID
+			}
+			for k, v := range want {
+				if got[k] != v {
+					t.Errorf("%s = %v, want %v", k, got[k], v)
+				}
+			}
+		})
+	}
+}
+
+func TestAccessLogFieldsConcurrent(t *testing.T) {
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				id := fmt.Sprintf("req-%d-%d", i, j)
+				path := fmt.Sprintf("/items/%d", i)
+				c := newLoggedContext(path, id)
+				fields := accessLogFields(context.Background(), c, "GET", path, 200, time.Millisecond)
+				if fields[3] != path || fields[len(fields)-1] != id {
+					t.Errorf("fields = %v, want path %s and request id %s", fields, path, id)
+					return
+				}
+			}
+		}(i)
+	}
+	wg.Wait()
+}
+
+func BenchmarkAccessLogFields(b *testing.B) {
+	c := newLoggedContext("/users", "req-1")
+	ctx := context.Background()
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		_ = accessLogFields(ctx, c, "GET", "/users", 200, time.Millisecond)
+	}
+}
+
+func BenchmarkAccessLog(b *testing.B) {
+	previous := logx.L()
+	logx.SetLogger(zap.NewNop().Sugar())
+	b.Cleanup(func() { logx.SetLogger(previous) })
+
+	handler := AccessLog()
+	ctx := context.Background()
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		c := newLoggedContext("/users", "req-1")
+		handler(ctx, c)
+	}
+}
+
+func TestLatencySeries(t *testing.T) {
+	tests := []struct {
+		name   string
+		method string
+		path   string
+		want   string
+	}{
+		{name: "static route", method: http.MethodGet, path: "/health", want: "http GET /health"},
+		{name: "parameterized route", method: http.MethodGet, path: "/users/123", want: "http GET /users/:id"},
+		{name: "other method", method: http.MethodPost, path: "/users/7", want: "http POST /users/:id"},
+		{name: "unmatched", method: http.MethodGet, path: "/missing/42", want: "http GET unmatched"},
+	}
+
+	var got string
+	h := server.New()
+	h.Use(func(ctx context.Context, c *app.RequestContext) {
+		c.Next(ctx)
+		got = latencySeries(c)
+	})
+	h.GET("/health", func(context.Context, *app.RequestContext) {})
+	h.GET("/users/:id", func(context.Context, *app.RequestContext) {})
+	h.POST("/users/:id", func(context.Context, *app.RequestContext) {})
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got = ""
+			ut.PerformRequest(h.Engine, tt.method, tt.path, nil)
+			if got != tt.want {
+				t.Errorf("latencySeries() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
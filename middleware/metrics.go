@@ -0,0 +1,108 @@
+package middleware
+
+import (
+	"context"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/cloudwego/hertz/pkg/app"
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/ssgohq/goten-core/metric"
+)
+
+var (
+	httpMetricsOnce      sync.Once
+	httpRequestsTotal    *metric.CounterVec
+	httpRequestsDuration *metric.HistogramVec
+)
+
+func initHTTPMetrics() {
+	httpMetricsOnce.Do(func() {
+		httpRequestsTotal = metric.NewCounterVec(prometheus.CounterOpts{
+			Namespace: "goten",
+			Subsystem: "http_server",
+			Name:      "requests_total",
+			Help:      "Total number of HTTP requests handled by the server",
+		}, []string{"method", "path", "status"})
+		httpRequestsDuration = metric.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: "goten",
+			Subsystem: "http_server",
+			Name:      "request_duration_seconds",
+			Help:      "HTTP request latency in seconds",
+			Buckets:   prometheus.DefBuckets,
+		}, []string{"method", "path", "status"})
+	})
+}
+
+// unmatchedPath is the path label of requests that matched no route.
+const unmatchedPath = "unmatched"
+
+// MetricsConfig configures the metrics middleware.
+type MetricsConfig struct {
+	// PathNormalizer returns the path label of a request. It must map
+	// request paths to a bounded set of values, such as "/users/123" to
+	// "/users/:id", to keep the metrics' cardinality low.
+	// Default: the matched route, or "unmatched"
+	PathNormalizer func(c *app.RequestContext) string
+
+	// Skipper determines whether to skip recording a request.
+	Skipper func(ctx context.Context, c *app.RequestContext) bool
+}
+
+// SetDefaults applies default values.
+func (c *MetricsConfig) SetDefaults() {
+	if c.PathNormalizer == nil {
+		c.PathNormalizer = routePath
+	}
+}
+
+// routePath returns the route a request matched, such as "/users/:id".
+func routePath(c *app.RequestContext) string {
+	if path := c.FullPath(); path != "" {
+		return path
+	}
+	return unmatchedPath
+}
+
+// Metrics returns a middleware that records the RED metrics of HTTP
+// requests: goten_http_server_requests_total and
+// goten_http_server_request_duration_seconds, labeled by method, matched
+// route and status. Installed ahead of Recovery, it also counts the 500
+// responses of recovered panics.
+func Metrics() app.HandlerFunc {
+	return MetricsWithConfig(MetricsConfig{})
+}
+
+// MetricsWithConfig returns a customized metrics middleware.
+//
+// Example:
+//
+//	idSegment := regexp.MustCompile(`/[0-9]+`)
+//	h.Use(middleware.MetricsWithConfig(middleware.MetricsConfig{
+//	    PathNormalizer: func(c *app.RequestContext) string {
+//	        return idSegment.ReplaceAllString(string(c.Request.URI().Path()), "/:id")
+//	    },
+//	}))
+func MetricsWithConfig(cfg MetricsConfig) app.HandlerFunc {
+	cfg.SetDefaults()
+	initHTTPMetrics()
+
+	return func(ctx context.Context, c *app.RequestContext) {
+		if cfg.Skipper != nil && cfg.Skipper(ctx, c) {
+			c.Next(ctx)
+			return
+		}
+
+		start := time.Now()
+		c.Next(ctx)
+		duration := time.Since(start)
+
+		method := string(c.Request.Method())
+		path := cfg.PathNormalizer(c)
+		status := strconv.Itoa(c.Response.StatusCode())
+		httpRequestsTotal.Inc(method, path, status)
+		httpRequestsDuration.Observe(duration.Seconds(), method, path, status)
+	}
+}
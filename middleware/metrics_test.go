@@ -0,0 +1,138 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/cloudwego/hertz/pkg/app"
+	"github.com/cloudwego/hertz/pkg/app/server"
+	"github.com/cloudwego/hertz/pkg/common/ut"
+	"github.com/prometheus/client_golang/prometheus"
+	"go.uber.org/zap"
+
+	"github.com/ssgohq/goten-core/logx"
+)
+
+// httpRequestsCount returns the value of goten_http_server_requests_total
+// for the given labels, or 0 when the series does not exist.
+func httpRequestsCount(t *testing.T, method, path, status string) float64 {
+	t.Helper()
+	families, err := prometheus.DefaultGatherer.Gather()
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := map[string]string{"method": method, "path": path, "status": status}
+	for _, mf := range families {
+		if mf.GetName() != "goten_http_server_requests_total" {
+			continue
+		}
+	metrics:
+		for _, m := range mf.GetMetric() {
+			for _, lp := range m.GetLabel() {
+				if want[lp.GetName()] != lp.GetValue() {
+					continue metrics
+				}
+			}
+			return m.GetCounter().GetValue()
+		}
+	}
+	return 0
+}
+
+func TestMetricsLabels(t *testing.T) {
+	prev := logx.L()
+	logx.SetLogger(zap.NewNop().Sugar())
+	t.Cleanup(func() { logx.SetLogger(prev) })
+
+	tests := []struct {
+		name       string
+		cfg        MetricsConfig
+		method     string
+		url        string
+		wantPath   string
+		wantStatus string
+		wantCount  float64
+	}{
+		{
+			name:       "route with parameter",
+			method:     http.MethodGet,
+			url:        "/metrics-test/users/123",
+			wantPath:   "/metrics-test/users/:id",
+			wantStatus: "200",
+			wantCount:  1,
+		},
+		{
+			name:       "other method",
+			method:     http.MethodPost,
+			url:        "/metrics-test/users/456",
+			wantPath:   "/metrics-test/users/:id",
+			wantStatus: "201",
+			wantCount:  1,
+		},
+		{
+			name:       "unmatched",
+			method:     http.MethodGet,
+			url:        "/metrics-test/nope/789",
+			wantPath:   unmatchedPath,
+			wantStatus: "404",
+			wantCount:  1,
+		},
+		{
+			name:       "recovered panic",
+			method:     http.MethodGet,
+			url:        "/metrics-test/panic",
+			wantPath:   "/metrics-test/panic",
+			wantStatus: "500",
+			wantCount:  1,
+		},
+		{
+			name: "custom normalizer",
+			cfg: MetricsConfig{PathNormalizer: func(c *app.RequestContext) string {
+				return strings.Replace(string(c.Request.URI().Path()), "/123", "/{id}", 1)
+			}},
+			method:     http.MethodGet,
+			url:        "/metrics-test/users/123",
+			wantPath:   "/metrics-test/users/{id}",
+			wantStatus: "200",
+			wantCount:  1,
+		},
+		{
+			name: "skipped",
+			cfg: MetricsConfig{
+				Skipper: func(context.Context, *app.RequestContext) bool { return true },
+			},
+			method:     http.MethodGet,
+			url:        "/metrics-test/users/123",
+			wantPath:   "/metrics-test/users/:id",
+			wantStatus: "200",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := server.New()
+			h.Use(MetricsWithConfig(tt.cfg), Recovery())
+			h.GET("/metrics-test/users/:id", func(_ context.Context, c *app.RequestContext) {
+				c.String(http.StatusOK, "ok")
+			})
+			h.POST("/metrics-test/users/:id", func(_ context.Context, c *app.RequestContext) {
+				c.String(http.StatusCreated, "created")
+			})
+			h.GET("/metrics-test/panic", func(context.Context, *app.RequestContext) {
+				panic("boom")
+			})
+
+			before := httpRequestsCount(t, tt.method, tt.wantPath, tt.wantStatus)
+			ut.PerformRequest(h.Engine, tt.method, tt.url, nil)
+			if got := httpRequestsCount(t, tt.method, tt.wantPath, tt.wantStatus) - before; got != tt.wantCount {
+				t.Errorf("requests{%s %s %s} increased by %v, want %v",
+					tt.method, tt.wantPath, tt.wantStatus, got, tt.wantCount)
+			}
+			if got := httpRequestsCount(t, tt.method, tt.url, tt.wantStatus); tt.url != tt.wantPath && got != 0 {
+				t.Errorf("raw request path %s recorded as a label", tt.url)
+			}
+		})
+	}
+}
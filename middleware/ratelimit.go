@@ -0,0 +1,195 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"math"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/cloudwego/hertz/pkg/app"
+	"golang.org/x/time/rate"
+
+	"github.com/ssgohq/goten-core/logx"
+)
+
+// ErrInvalidRate is raised by RateLimit when the configured rate is not
+// positive.
+var ErrInvalidRate = errors.New("invalid rate limit: rate must be > 0")
+
+// ErrRateLimited is the message of 429 responses sent by RateLimit.
+var ErrRateLimited = errors.New("rate limit exceeded")
+
+// RateLimitStore keeps the token buckets of the RateLimit middleware. The
+// default is an in-memory store; implement it on a shared store such as
+// Redis to enforce the limit across instances.
+type RateLimitStore interface {
+	// Take takes a token from the bucket of key, which refills at limit
+	// tokens per second up to burst. It reports whether a token was
+	// available and, if not, how long until one will be.
+	Take(ctx context.Context, key string, limit float64, burst int) (ok bool, retryAfter time.Duration, err error)
+}
+
+// RateLimitConfig represents rate limiting middleware configuration.
+type RateLimitConfig struct {
+	// Rate is the steady number of requests per second allowed per key.
+	// Required.
+	Rate float64 `yaml:"rate,omitempty" json:"rate,omitempty"`
+
+	// Burst is the number of requests a key may make at once before being
+	// held to Rate.
+	// Default: Rate rounded up
+	Burst int `yaml:"burst,omitempty" json:"burst,omitempty"`
+
+	// KeyFunc returns the key requests are limited by.
+	// Default: the client IP
+	KeyFunc func(ctx context.Context, c *app.RequestContext) string
+
+	// Store keeps the token buckets.
+	// Default: an in-memory store (see NewMemoryRateLimitStore)
+	Store RateLimitStore
+
+	// Skipper determines whether to skip rate limiting.
+	Skipper func(ctx context.Context, c *app.RequestContext) bool
+}
+
+// SetDefaults applies default values.
+func (c *RateLimitConfig) SetDefaults() {
+	if c.Burst == 0 {
+		c.Burst = int(math.Ceil(c.Rate))
+	}
+	if c.KeyFunc == nil {
+		c.KeyFunc = func(ctx context.Context, c *app.RequestContext) string {
+			return c.ClientIP()
+		}
+	}
+	if c.Store == nil {
+		c.Store = NewMemoryRateLimitStore()
+	}
+}
+
+// RateLimit returns a middleware that limits requests per client IP, or per
+// KeyFunc key, with a token bucket. Requests over the limit are aborted with
+// 429 and a Retry-After header. If the store fails, the request is let
+// through and the error logged.
+//
+// Example:
+//
+//	h.Use(middleware.RateLimit(middleware.RateLimitConfig{
+//	    Rate:  10,
+//	    Burst: 20,
+//	}))
+func RateLimit(cfg RateLimitConfig) app.HandlerFunc {
+	cfg.SetDefaults()
+	if cfg.Rate <= 0 || cfg.Burst <= 0 {
+		panic(ErrInvalidRate)
+	}
+
+	return func(ctx context.Context, c *app.RequestContext) {
+		// Check skipper
+		if cfg.Skipper != nil && cfg.Skipper(ctx, c) {
+			c.Next(ctx)
+			return
+		}
+
+		key := cfg.KeyFunc(ctx, c)
+		ok, retryAfter, err := cfg.Store.Take(ctx, key, cfg.Rate, cfg.Burst)
+		if err != nil {
+			logx.Ctx(ctx).Warnw("Rate limit store failed, allowing request",
+				"key", key,
+				"error", err,
+			)
+			c.Next(ctx)
+			return
+		}
+		if !ok {
+			// Retry-After is in whole seconds; round up so that a retry
+			// at that time finds a token. AbortWithMsg resets the response,
+			// so the header is set after it.
+			seconds := int(math.Ceil(retryAfter.Seconds()))
+			if seconds < 1 {
+				seconds = 1
+			}
+			c.AbortWithMsg(ErrRateLimited.Error(), 429)
+			c.Header("Retry-After", strconv.Itoa(seconds))
+			return
+		}
+		c.Next(ctx)
+	}
+}
+
+// memoryRateLimitIdleTTL is how long a bucket may go unused before it is
+// garbage-collected.
+const memoryRateLimitIdleTTL = 10 * time.Minute
+
+// MemoryRateLimitStore is a RateLimitStore keeping buckets in process
+// memory. Buckets are created with the limit and burst of their first Take.
+type MemoryRateLimitStore struct {
+	mu        sync.Mutex
+	buckets   map[string]*memoryBucket
+	lastSweep time.Time
+}
+
+type memoryBucket struct {
+	limiter  *rate.Limiter
+	lastUsed time.Time
+	// refill is how long an empty bucket takes to fill up
+	refill time.Duration
+}
+
+// NewMemoryRateLimitStore creates an empty in-memory store.
+func NewMemoryRateLimitStore() *MemoryRateLimitStore {
+	return &MemoryRateLimitStore{
+		buckets:   make(map[string]*memoryBucket),
+		lastSweep: time.Now(),
+	}
+}
+
+// Take implements RateLimitStore.
+func (s *MemoryRateLimitStore) Take(
+	_ context.Context,
+	key string,
+	limit float64,
+	burst int,
+) (bool, time.Duration, error) {
+	now := time.Now()
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.sweep(now)
+
+	b, ok := s.buckets[key]
+	if !ok {
+		b = &memoryBucket{
+			limiter: rate.NewLimiter(rate.Limit(limit), burst),
+			refill:  time.Duration(float64(burst) / limit * float64(time.Second)),
+		}
+		s.buckets[key] = b
+	}
+	b.lastUsed = now
+
+	r := b.limiter.ReserveN(now, 1)
+	if delay := r.DelayFrom(now); delay > 0 {
+		r.CancelAt(now)
+		return false, delay, nil
+	}
+	return true, 0, nil
+}
+
+// sweep drops buckets that have been idle for memoryRateLimitIdleTTL and
+// long enough to refill. An idle bucket is full, so recreating it later does
+// not change behavior.
+func (s *MemoryRateLimitStore) sweep(now time.Time) {
+	if now.Sub(s.lastSweep) < memoryRateLimitIdleTTL {
+		return
+	}
+	for key, b := range s.buckets {
+		idle := now.Sub(b.lastUsed)
+		if idle >= memoryRateLimitIdleTTL && idle >= b.refill {
+			delete(s.buckets, key)
+		}
+	}
+	s.lastSweep = now
+}
@@ -0,0 +1,150 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/cloudwego/hertz/pkg/app"
+	"github.com/cloudwego/hertz/pkg/app/server"
+	"github.com/cloudwego/hertz/pkg/common/ut"
+)
+
+// failingRateLimitStore fails every Take.
+type failingRateLimitStore struct{}
+
+func (failingRateLimitStore) Take(context.Context, string, float64, int) (bool, time.Duration, error) {
+	return false, 0, errors.New("store down")
+}
+
+// newRateLimitServer returns a server limited by cfg, keyed by the X-Key
+// header unless cfg sets KeyFunc.
+func newRateLimitServer(cfg RateLimitConfig) *server.Hertz {
+	if cfg.KeyFunc == nil {
+		cfg.KeyFunc = func(_ context.Context, c *app.RequestContext) string {
+			return string(c.GetHeader("X-Key"))
+		}
+	}
+	h := server.New()
+	h.Use(RateLimit(cfg))
+	h.GET("/", func(_ context.Context, c *app.RequestContext) {
+		c.String(http.StatusOK, "ok")
+	})
+	return h
+}
+
+func TestRateLimit(t *testing.T) {
+	tests := []struct {
+		name           string
+		cfg            RateLimitConfig
+		keys           []string
+		wantCodes      []int
+		wantRetryAfter string
+	}{
+		{
+			name:      "within burst",
+			cfg:       RateLimitConfig{Rate: 0.1, Burst: 3},
+			keys:      []string{"a", "a", "a"},
+			wantCodes: []int{200, 200, 200},
+		},
+		{
+			name:           "over burst",
+			cfg:            RateLimitConfig{Rate: 0.1, Burst: 2},
+			keys:           []string{"a", "a", "a"},
+			wantCodes:      []int{200, 200, 429},
+			wantRetryAfter: "10",
+		},
+		{
+			name:      "keys are limited separately",
+			cfg:       RateLimitConfig{Rate: 0.1, Burst: 1},
+			keys:      []string{"a", "b", "a", "b"},
+			wantCodes: []int{200, 200, 429, 429},
+		},
+		{
+			name:      "burst defaults to rate rounded up",
+			cfg:       RateLimitConfig{Rate: 1.5},
+			keys:      []string{"a", "a", "a"},
+			wantCodes: []int{200, 200, 429},
+		},
+		{
+			name: "skipped",
+			cfg: RateLimitConfig{
+				Rate: 0.1, Burst: 1,
+				Skipper: func(context.Context, *app.RequestContext) bool { return true },
+			},
+			keys:      []string{"a", "a"},
+			wantCodes: []int{200, 200},
+		},
+		{
+			name:      "store failure allows requests",
+			cfg:       RateLimitConfig{Rate: 0.1, Burst: 1, Store: failingRateLimitStore{}},
+			keys:      []string{"a", "a"},
+			wantCodes: []int{200, 200},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := newRateLimitServer(tt.cfg)
+			for i, key := range tt.keys {
+				w := ut.PerformRequest(h.Engine, http.MethodGet, "/", nil, ut.Header{Key: "X-Key", Value: key})
+				resp := w.Result()
+				if got := resp.StatusCode(); got != tt.wantCodes[i] {
+					t.Fatalf("request %d: status = %d, want %d", i, got, tt.wantCodes[i])
+				}
+				if got := string(resp.Header.Peek("Retry-After")); tt.wantRetryAfter != "" &&
+					resp.StatusCode() == http.StatusTooManyRequests && got != tt.wantRetryAfter {
+					t.Errorf("request %d: Retry-After = %q, want %q", i, got, tt.wantRetryAfter)
+				}
+			}
+		})
+	}
+}
+
+// TestRateLimitConcurrent checks that concurrent requests never take more
+// tokens than the burst; run it with -race.
+func TestRateLimitConcurrent(t *testing.T) {
+	const burst, requests = 10, 100
+	tests := []struct {
+		name string
+		keys []string
+	}{
+		{name: "one key", keys: []string{"a"}},
+		{name: "three keys", keys: []string{"a", "b", "c"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := newRateLimitServer(RateLimitConfig{Rate: 0.01, Burst: burst})
+
+			var allowed, limited atomic.Int32
+			var wg sync.WaitGroup
+			for i := 0; i < requests; i++ {
+				wg.Add(1)
+				go func(key string) {
+					defer wg.Done()
+					w := ut.PerformRequest(h.Engine, http.MethodGet, "/", nil, ut.Header{Key: "X-Key", Value: key})
+					switch w.Result().StatusCode() {
+					case http.StatusOK:
+						allowed.Add(1)
+					case http.StatusTooManyRequests:
+						limited.Add(1)
+					}
+				}(tt.keys[i%len(tt.keys)])
+			}
+			wg.Wait()
+
+			want := int32(burst * len(tt.keys))
+			if got := allowed.Load(); got != want {
+				t.Errorf("allowed = %d, want %d", got, want)
+			}
+			if got := limited.Load(); got != requests-want {
+				t.Errorf("limited = %d, want %d", got, requests-want)
+			}
+		})
+	}
+}
@@ -0,0 +1,130 @@
+package middleware
+
+import (
+	"context"
+	"strconv"
+	"strings"
+
+	"github.com/cloudwego/hertz/pkg/app"
+)
+
+// SecureHeaderOff disables a SecureConfig header that has a default.
+const SecureHeaderOff = "-"
+
+// SecureConfig represents security headers middleware configuration.
+// String headers default to the values below; set one to SecureHeaderOff
+// to leave the header out.
+type SecureConfig struct {
+	// ContentTypeOptions is the X-Content-Type-Options value.
+	// Default: "nosniff"
+	ContentTypeOptions string `yaml:"contentTypeOptions,omitempty" json:"contentTypeOptions,omitempty"`
+
+	// FrameOptions is the X-Frame-Options value.
+	// Default: "DENY"
+	FrameOptions string `yaml:"frameOptions,omitempty" json:"frameOptions,omitempty"`
+
+	// ContentSecurityPolicy is the Content-Security-Policy value.
+	// Default: "default-src 'self'"
+	ContentSecurityPolicy string `yaml:"contentSecurityPolicy,omitempty" json:"contentSecurityPolicy,omitempty"`
+
+	// ReferrerPolicy is the Referrer-Policy value.
+	// Default: "strict-origin-when-cross-origin"
+	ReferrerPolicy string `yaml:"referrerPolicy,omitempty" json:"referrerPolicy,omitempty"`
+
+	// HSTSMaxAge is the Strict-Transport-Security max-age in seconds. The
+	// header is only sent on HTTPS requests; behind a proxy terminating TLS,
+	// set HSTSTrustForwardedProto. A negative value disables it.
+	// Default: 31536000 (one year)
+	HSTSMaxAge int `yaml:"hstsMaxAge,omitempty" json:"hstsMaxAge,omitempty"`
+
+	// HSTSIncludeSubdomains adds includeSubDomains to the HSTS header.
+	HSTSIncludeSubdomains bool `yaml:"hstsIncludeSubdomains,omitempty" json:"hstsIncludeSubdomains,omitempty"`
+
+	// HSTSPreload adds preload to the HSTS header.
+	HSTSPreload bool `yaml:"hstsPreload,omitempty" json:"hstsPreload,omitempty"`
+
+	// HSTSTrustForwardedProto treats requests whose X-Forwarded-Proto is
+	// "https" as HTTPS when deciding whether to send HSTS. Enable it only
+	// behind a proxy that sets the header, since clients can forge it.
+	HSTSTrustForwardedProto bool `yaml:"hstsTrustForwardedProto,omitempty" json:"hstsTrustForwardedProto,omitempty"`
+}
+
+// SetDefaults applies default values.
+func (c *SecureConfig) SetDefaults() {
+	if c.ContentTypeOptions == "" {
+		c.ContentTypeOptions = "nosniff"
+	}
+	if c.FrameOptions == "" {
+		c.FrameOptions = "DENY"
+	}
+	if c.ContentSecurityPolicy == "" {
+		c.ContentSecurityPolicy = "default-src 'self'"
+	}
+	if c.ReferrerPolicy == "" {
+		c.ReferrerPolicy = "strict-origin-when-cross-origin"
+	}
+	if c.HSTSMaxAge == 0 {
+		c.HSTSMaxAge = 31536000
+	}
+}
+
+// SecureHeaders returns a middleware that sets security response headers
+// before calling the next handler, so that handlers may still override
+// them.
+//
+// Example:
+//
+//	h.Use(middleware.SecureHeaders(middleware.SecureConfig{
+//	    FrameOptions:          "SAMEORIGIN",
+//	    ContentSecurityPolicy: middleware.SecureHeaderOff,
+//	}))
+func SecureHeaders(cfg SecureConfig) app.HandlerFunc {
+	cfg.SetDefaults()
+
+	headers := make([][2]string, 0, 4)
+	for _, h := range [][2]string{
+		{"X-Content-Type-Options", cfg.ContentTypeOptions},
+		{"X-Frame-Options", cfg.FrameOptions},
+		{"Content-Security-Policy", cfg.ContentSecurityPolicy},
+		{"Referrer-Policy", cfg.ReferrerPolicy},
+	} {
+		if h[1] != SecureHeaderOff {
+			headers = append(headers, h)
+		}
+	}
+
+	var hsts string
+	if cfg.HSTSMaxAge > 0 {
+		hsts = "max-age=" + strconv.Itoa(cfg.HSTSMaxAge)
+		if cfg.HSTSIncludeSubdomains {
+			hsts += "; includeSubDomains"
+		}
+		if cfg.HSTSPreload {
+			hsts += "; preload"
+		}
+	}
+
+	return func(ctx context.Context, c *app.RequestContext) {
+		for _, h := range headers {
+			c.Header(h[0], h[1])
+		}
+		if hsts != "" && isHTTPS(c, cfg.HSTSTrustForwardedProto) {
+			c.Header("Strict-Transport-Security", hsts)
+		}
+		c.Next(ctx)
+	}
+}
+
+// isHTTPS reports whether the request reached the service, or with
+// trustForwarded the proxy in front of it, over HTTPS.
+func isHTTPS(c *app.RequestContext, trustForwarded bool) bool {
+	if string(c.Request.URI().Scheme()) == "https" {
+		return true
+	}
+	if !trustForwarded {
+		return false
+	}
+	// The first entry is the protocol the client used with the outermost proxy
+	proto, _, _ := strings.Cut(string(c.Request.Header.Peek("X-Forwarded-Proto")), ",")
+	return strings.EqualFold(strings.TrimSpace(proto), "https")
+}
@@ -0,0 +1,125 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/cloudwego/hertz/pkg/app"
+	"github.com/cloudwego/hertz/pkg/app/server"
+	"github.com/cloudwego/hertz/pkg/common/ut"
+)
+
+func newSecureServer(cfg SecureConfig) *server.Hertz {
+	h := server.New()
+	h.Use(SecureHeaders(cfg))
+	h.GET("/ok", func(_ context.Context, c *app.RequestContext) {
+		c.String(http.StatusOK, "ok")
+	})
+	h.GET("/framed", func(_ context.Context, c *app.RequestContext) {
+		c.Header("X-Frame-Options", "SAMEORIGIN")
+		c.String(http.StatusOK, "ok")
+	})
+	return h
+}
+
+func TestSecureHeadersDefaults(t *testing.T) {
+	tests := []struct {
+		name string
+		cfg  SecureConfig
+		path string
+		want map[string]string
+	}{
+		{
+			name: "defaults",
+			path: "/ok",
+			want: map[string]string{
+				"X-Content-Type-Options":    "nosniff",
+				"X-Frame-Options":           "DENY",
+				"Content-Security-Policy":   "default-src 'self'",
+				"Referrer-Policy":           "strict-origin-when-cross-origin",
+				"Strict-Transport-Security": "",
+			},
+		},
+		{
+			name: "header off",
+			cfg:  SecureConfig{ContentSecurityPolicy: SecureHeaderOff},
+			path: "/ok",
+			want: map[string]string{
+				"X-Content-Type-Options":  "nosniff",
+				"Content-Security-Policy": "",
+			},
+		},
+		{
+			name: "handler overrides",
+			path: "/framed",
+			want: map[string]string{"X-Frame-Options": "SAMEORIGIN"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := ut.PerformRequest(newSecureServer(tt.cfg).Engine, http.MethodGet, tt.path, nil)
+			resp := w.Result()
+			for k, v := range tt.want {
+				if got := resp.Header.Get(k); got != v {
+					t.Errorf("%s = %q, want %q", k, got, v)
+				}
+			}
+		})
+	}
+}
+
+func TestSecureHeadersHSTS(t *testing.T) {
+	tests := []struct {
+		name           string
+		cfg            SecureConfig
+		url            string
+		forwardedProto string
+		want           string
+	}{
+		{name: "plain http", url: "/ok"},
+		{name: "https", url: "https://example.com/ok", want: "max-age=31536000"},
+		{
+			name: "https with options",
+			cfg:  SecureConfig{HSTSMaxAge: 60, HSTSIncludeSubdomains: true, HSTSPreload: true},
+			url:  "https://example.com/ok",
+			want: "max-age=60; includeSubDomains; preload",
+		},
+		{name: "disabled", cfg: SecureConfig{HSTSMaxAge: -1}, url: "https://example.com/ok"},
+		{name: "forwarded https untrusted", url: "/ok", forwardedProto: "https"},
+		{
+			name:           "forwarded https trusted",
+			cfg:            SecureConfig{HSTSTrustForwardedProto: true},
+			url:            "/ok",
+			forwardedProto: "HTTPS",
+			want:           "max-age=31536000",
+		},
+		{
+			name:           "forwarded list trusted",
+			cfg:            SecureConfig{HSTSTrustForwardedProto: true},
+			url:            "/ok",
+			forwardedProto: "https, http",
+			want:           "max-age=31536000",
+		},
+		{
+			name:           "forwarded http trusted",
+			cfg:            SecureConfig{HSTSTrustForwardedProto: true},
+			url:            "/ok",
+			forwardedProto: "http",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var headers []ut.Header
+			if tt.forwardedProto != "" {
+				headers = append(headers, ut.Header{Key: "X-Forwarded-Proto", Value: tt.forwardedProto})
+			}
+			w := ut.PerformRequest(newSecureServer(tt.cfg).Engine, http.MethodGet, tt.url, nil, headers...)
+			if got := w.Result().Header.Get("Strict-Transport-Security"); got != tt.want {
+				t.Errorf("Strict-Transport-Security = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
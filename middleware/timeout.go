@@ -0,0 +1,131 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/cloudwego/hertz/pkg/app"
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/ssgohq/goten-core/logx"
+	"github.com/ssgohq/goten-core/metric"
+)
+
+// ErrRequestTimeout is the message of 503 responses sent by Timeout.
+var ErrRequestTimeout = errors.New("request timed out")
+
+var (
+	timeoutMetricsOnce sync.Once
+	requestTimeouts    *metric.CounterVec
+)
+
+func initTimeoutMetrics() {
+	timeoutMetricsOnce.Do(func() {
+		requestTimeouts = metric.NewCounterVec(prometheus.CounterOpts{
+			Namespace: "goten",
+			Subsystem: "http_server",
+			Name:      "timeouts_total",
+			Help:      "Total number of HTTP requests that exceeded the Timeout middleware deadline",
+		}, []string{"method", "route"})
+	})
+}
+
+// Timeout returns a middleware that bounds each request by d. The handlers
+// after it run in their own goroutine on a copy of the RequestContext and
+// receive a context that is cancelled after d. If they return in time,
+// their response and keys are copied back; otherwise the client gets a 503
+// as soon as the deadline fires, the timeout is logged and counted in
+// goten_http_server_timeouts_total, and the abandoned handlers finish on
+// their copy, which is never reused.
+//
+// A panic in the handlers is re-raised on the request goroutine when they
+// return in time, so Recovery still handles it; after the deadline it is
+// logged. Handlers that stream or hijack the connection must not run
+// behind Timeout, since the copy shares the connection. A non-positive d
+// disables the middleware.
+//
+// Example:
+//
+//	h.Use(middleware.Timeout(5 * time.Second))
+//
+//	h.GET("/users/:id", func(ctx context.Context, c *app.RequestContext) {
+//	    user, err := repo.Get(ctx, c.Param("id")) // fails once ctx is done
+//	    // ...
+//	})
+func Timeout(d time.Duration) app.HandlerFunc {
+	if d <= 0 {
+		return func(ctx context.Context, c *app.RequestContext) {
+			c.Next(ctx)
+		}
+	}
+	initTimeoutMetrics()
+
+	return func(ctx context.Context, c *app.RequestContext) {
+		timeoutCtx, cancel := context.WithTimeout(ctx, d)
+		defer cancel()
+
+		// 1. Run the rest of the chain on a copy, which the server does not
+		// recycle, so that it can be abandoned
+		cp := c.Copy()
+		cp.SetHandlers(c.Handlers())
+		cp.SetIndex(c.GetIndex())
+		c.Abort()
+
+		done := make(chan interface{}, 1)
+		go func() {
+			var panicked interface{}
+			defer func() {
+				if r := recover(); r != nil {
+					panicked = r
+				}
+				done <- panicked
+			}()
+			cp.Next(timeoutCtx)
+		}()
+
+		// 2. Wait for the handlers or the deadline
+		select {
+		case panicked := <-done:
+			if panicked != nil {
+				panic(panicked)
+			}
+			cp.Response.CopyTo(&c.Response)
+			cp.ForEachKey(func(k string, v interface{}) {
+				c.Set(k, v)
+			})
+			return
+		case <-timeoutCtx.Done():
+		}
+
+		// 3. Report the timeout, unless the caller's own context ended first
+		method := string(c.Request.Method())
+		route := c.FullPath()
+		path := string(c.Request.URI().Path())
+		go func() {
+			if r := <-done; r != nil {
+				logx.Errorw("Panic after HTTP request timed out",
+					"panic", fmt.Sprintf("%v", r),
+					"method", method,
+					"route", route,
+					"path", path,
+				)
+			}
+		}()
+		if !errors.Is(timeoutCtx.Err(), context.DeadlineExceeded) || ctx.Err() != nil {
+			c.AbortWithStatus(503)
+			return
+		}
+
+		requestTimeouts.Inc(method, route)
+		logx.Ctx(ctx).Warnw("HTTP request timed out",
+			"method", method,
+			"route", route,
+			"path", path,
+			"timeout", d.String(),
+		)
+		c.AbortWithMsg(ErrRequestTimeout.Error(), 503)
+	}
+}
@@ -0,0 +1,100 @@
+package middleware
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/cloudwego/hertz/pkg/app"
+	"github.com/cloudwego/hertz/pkg/app/server"
+	"github.com/cloudwego/hertz/pkg/common/ut"
+)
+
+func TestTimeout(t *testing.T) {
+	tests := []struct {
+		name       string
+		handler    app.HandlerFunc
+		wantStatus int
+		wantBody   string
+		maxElapsed time.Duration
+	}{
+		{
+			name: "fast handler",
+			handler: func(ctx context.Context, c *app.RequestContext) {
+				c.String(200, "ok")
+			},
+			wantStatus: 200,
+			wantBody:   "ok",
+			maxElapsed: time.Second,
+		},
+		{
+			name: "handler honouring its context",
+			handler: func(ctx context.Context, c *app.RequestContext) {
+				<-ctx.Done()
+				c.String(200, "late")
+			},
+			wantStatus: 503,
+			wantBody:   ErrRequestTimeout.Error(),
+			maxElapsed: time.Second,
+		},
+		{
+			name: "handler ignoring its context",
+			handler: func(ctx context.Context, c *app.RequestContext) {
+				time.Sleep(2 * time.Second)
+				c.String(200, "late")
+			},
+			wantStatus: 503,
+			wantBody:   ErrRequestTimeout.Error(),
+			maxElapsed: time.Second,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := server.New()
+			h.Use(Timeout(50 * time.Millisecond))
+			h.GET("/", tt.handler)
+
+			start := time.Now()
+			w := ut.PerformRequest(h.Engine, "GET", "/", nil)
+			elapsed := time.Since(start)
+
+			resp := w.Result()
+			if resp.StatusCode() != tt.wantStatus {
+				t.Errorf("status = %d, want %d", resp.StatusCode(), tt.wantStatus)
+			}
+			if string(resp.Body()) != tt.wantBody {
+				t.Errorf("body = %q, want %q", resp.Body(), tt.wantBody)
+			}
+			if elapsed > tt.maxElapsed {
+				t.Errorf("request took %v, want at most %v", elapsed, tt.maxElapsed)
+			}
+		})
+	}
+}
+
+func TestTimeoutKeepsKeysAndRecovery(t *testing.T) {
+	h := server.New()
+	var got interface{}
+	h.Use(Recovery(), func(ctx context.Context, c *app.RequestContext) {
+		c.Next(ctx)
+		got, _ = c.Get("user")
+	}, Timeout(time.Second))
+	h.GET("/", func(ctx context.Context, c *app.RequestContext) {
+		c.Set("user", "alice")
+		c.Status(204)
+	})
+	h.GET("/panic", func(ctx context.Context, c *app.RequestContext) {
+		panic("boom")
+	})
+
+	if w := ut.PerformRequest(h.Engine, "GET", "/", nil); w.Code != 204 {
+		t.Errorf("status = %d, want 204", w.Code)
+	}
+	if got != "alice" {
+		t.Errorf("key user = %v, want alice", got)
+	}
+	if w := ut.PerformRequest(h.Engine, "GET", "/panic", nil); w.Code != 500 {
+		t.Errorf("panic status = %d, want 500", w.Code)
+	}
+}
@@ -0,0 +1,78 @@
+package srpc
+
+import (
+	"context"
+	"math/rand/v2"
+	"strconv"
+	"time"
+
+	"github.com/cloudwego/kitex/pkg/endpoint"
+	"github.com/cloudwego/kitex/pkg/retry"
+	"github.com/cloudwego/kitex/pkg/rpcinfo"
+)
+
+// exponentialBackOff is a retry.BackOff whose delay doubles with every
+// retry: Delay before the first, 2·Delay before the second and so on, capped
+// at MaxDelay. With jitter, each delay is drawn from its upper half so that
+// callers failing together do not retry in lockstep.
+type exponentialBackOff struct {
+	base   time.Duration
+	max    time.Duration
+	jitter bool
+}
+
+var _ retry.BackOff = (*exponentialBackOff)(nil)
+
+// newExponentialBackOff creates the backoff configured by cfg.
+func newExponentialBackOff(cfg RetryConfig) *exponentialBackOff {
+	return &exponentialBackOff{base: cfg.Delay, max: cfg.MaxDelay, jitter: cfg.Jitter}
+}
+
+// delay returns the wait before the given retry, counting from 1.
+func (b *exponentialBackOff) delay(retry int) time.Duration {
+	d := b.base
+	for i := 1; i < retry && d > 0 && (b.max <= 0 || d < b.max); i++ {
+		d *= 2
+	}
+	if b.max > 0 && d > b.max {
+		d = b.max
+	}
+	if b.jitter && d > 1 {
+		d = d/2 + rand.N(d/2+1)
+	}
+	return d
+}
+
+// Wait implements the retry.BackOff interface.
+func (b *exponentialBackOff) Wait(callTimes int) {
+	time.Sleep(b.delay(callTimes))
+}
+
+// String prints human readable information.
+func (b *exponentialBackOff) String() string {
+	return "ExponentialBackOff(" + b.base.String() + "-" + b.max.String() + ")"
+}
+
+// retryBackOffMW returns a client middleware that waits before each retry.
+// Kitex only accepts its built-in backoffs in a retry policy, but it runs
+// client middlewares once per attempt and tags retried calls with their
+// number, so the wait happens here instead. It ends early when the call's
+// context is done.
+func retryBackOffMW(bo *exponentialBackOff) endpoint.Middleware {
+	return func(next endpoint.Endpoint) endpoint.Endpoint {
+		return func(ctx context.Context, req, resp interface{}) error {
+			if ri := rpcinfo.GetRPCInfo(ctx); ri != nil && ri.To() != nil {
+				if attempt, _ := strconv.Atoi(ri.To().DefaultTag(rpcinfo.RetryTag, "0")); attempt > 0 {
+					timer := time.NewTimer(bo.delay(attempt))
+					select {
+					case <-ctx.Done():
+						timer.Stop()
+						return ctx.Err()
+					case <-timer.C:
+					}
+				}
+			}
+			return next(ctx, req, resp)
+		}
+	}
+}
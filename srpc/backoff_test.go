@@ -0,0 +1,107 @@
+package srpc
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/cloudwego/kitex/pkg/rpcinfo"
+	"github.com/cloudwego/kitex/pkg/rpcinfo/remoteinfo"
+)
+
+func TestExponentialBackOffDelay(t *testing.T) {
+	tests := []struct {
+		name string
+		cfg  RetryConfig
+		want []time.Duration // delays before retries 1, 2, ...
+	}{
+		{
+			name: "doubles up to max delay",
+			cfg:  RetryConfig{Delay: 100 * time.Millisecond, MaxDelay: time.Second},
+			want: []time.Duration{
+				100 * time.Millisecond, 200 * time.Millisecond, 400 * time.Millisecond,
+				800 * time.Millisecond, time.Second, time.Second,
+			},
+		},
+		{
+			name: "delay equal to max delay",
+			cfg:  RetryConfig{Delay: time.Second, MaxDelay: time.Second},
+			want: []time.Duration{time.Second, time.Second, time.Second},
+		},
+		{
+			name: "no max delay",
+			cfg:  RetryConfig{Delay: 10 * time.Millisecond},
+			want: []time.Duration{10 * time.Millisecond, 20 * time.Millisecond, 40 * time.Millisecond},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			bo := newExponentialBackOff(tt.cfg)
+			for i, want := range tt.want {
+				if got := bo.delay(i + 1); got != want {
+					t.Errorf("delay(%d) = %s, want %s", i+1, got, want)
+				}
+			}
+
+			// With jitter every delay falls in the upper half of the
+			// exponential delay
+			jittered := newExponentialBackOff(RetryConfig{Delay: tt.cfg.Delay, MaxDelay: tt.cfg.MaxDelay, Jitter: true})
+			for i, want := range tt.want {
+				for n := 0; n < 100; n++ {
+					if got := jittered.delay(i + 1); got < want/2 || got > want {
+						t.Fatalf("jittered delay(%d) = %s, want within [%s, %s]", i+1, got, want/2, want)
+					}
+				}
+			}
+		})
+	}
+}
+
+// retryCtx returns a context carrying the RPC info of the given attempt, as
+// set by Kitex on retried calls.
+func retryCtx(ctx context.Context, attempt int) context.Context {
+	to := remoteinfo.NewRemoteInfo(&rpcinfo.EndpointBasicInfo{ServiceName: "user"}, "Get")
+	if attempt > 0 {
+		to.SetTag(rpcinfo.RetryTag, strconv.Itoa(attempt))
+	}
+	ri := rpcinfo.NewRPCInfo(nil, to.ImmutableView(), rpcinfo.NewInvocation("user", "Get"), nil, nil)
+	return rpcinfo.NewCtxWithRPCInfo(ctx, ri)
+}
+
+func TestRetryBackOffMW(t *testing.T) {
+	bo := &exponentialBackOff{base: 20 * time.Millisecond, max: time.Second}
+	ep := retryBackOffMW(bo)(func(context.Context, interface{}, interface{}) error { return nil })
+
+	tests := []struct {
+		name    string
+		attempt int
+		minWait time.Duration
+	}{
+		{name: "first attempt", attempt: 0},
+		{name: "first retry", attempt: 1, minWait: 20 * time.Millisecond},
+		{name: "third retry", attempt: 3, minWait: 80 * time.Millisecond},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			start := time.Now()
+			if err := ep(retryCtx(context.Background(), tt.attempt), nil, nil); err != nil {
+				t.Fatal(err)
+			}
+			if elapsed := time.Since(start); elapsed < tt.minWait {
+				t.Errorf("waited %s, want at least %s", elapsed, tt.minWait)
+			}
+		})
+	}
+
+	t.Run("context done", func(t *testing.T) {
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+		defer cancel()
+		if err := ep(retryCtx(ctx, 6), nil, nil); !errors.Is(err, context.DeadlineExceeded) {
+			t.Fatalf("err = %v, want the context error", err)
+		}
+	})
+}
@@ -2,91 +2,169 @@ package srpc
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"math"
+	"sync"
 
 	"github.com/cloudwego/kitex/client"
 	"github.com/cloudwego/kitex/pkg/circuitbreak"
+	"github.com/cloudwego/kitex/pkg/connpool"
 	"github.com/cloudwego/kitex/pkg/discovery"
 	"github.com/cloudwego/kitex/pkg/endpoint"
+	"github.com/cloudwego/kitex/pkg/kerrors"
 	"github.com/cloudwego/kitex/pkg/loadbalance"
+	"github.com/cloudwego/kitex/pkg/remote/trans/gonet"
 	"github.com/cloudwego/kitex/pkg/retry"
 	"github.com/cloudwego/kitex/pkg/rpcinfo"
-	kitextracing "github.com/kitex-contrib/obs-opentelemetry/tracing"
+	"github.com/cloudwego/kitex/transport"
 	consul "github.com/kitex-contrib/registry-consul"
 
 	"github.com/ssgohq/goten-core/logx"
+	"github.com/ssgohq/goten-core/srpc/middleware"
 )
 
 // ClientBuilder helps construct Kitex client with common options.
 type ClientBuilder struct {
 	config  *ClientConfig
 	options []client.Option
+	err     error
 }
 
 // NewClientBuilder creates a new client builder with the given configuration.
+// An invalid configuration is reported by Err and makes the client built
+// from the builder's options fail to be created.
 func NewClientBuilder(config *ClientConfig) *ClientBuilder {
 	config.SetDefaults()
+	err := config.Validate()
+	if err != nil {
+		logx.Errorw("Invalid client config", "serviceName", config.ServiceName, "error", err)
+	}
 	return &ClientBuilder{
 		config:  config,
 		options: make([]client.Option, 0),
+		err:     err,
 	}
 }
 
+// Err returns the configuration error found by the builder, if any.
+func (b *ClientBuilder) Err() error {
+	return b.err
+}
+
 // Build returns all configured client options ready to pass to the generated
-// Kitex client NewClient function.
+// Kitex client NewClient function. If the configuration is invalid, the
+// options make NewClient return the error from Err.
 //
 // Example:
 //
 //	builder := srpc.NewClientBuilder(&config)
 //	cli, err := userservice.NewClient("user-rpc", builder.Build()...)
 func (b *ClientBuilder) Build() []client.Option {
+	if b.err != nil {
+		return b.invalid(b.err)
+	}
 	opts := make([]client.Option, 0, 10)
 
 	// 1. Service discovery or direct endpoints
-	if resolver := b.buildResolver(); resolver != nil {
+	resolver, err := b.buildResolver()
+	if err != nil {
+		logx.Errorw("Failed to create service resolver", "serviceName", b.config.ServiceName, "error", err)
+		return b.invalid(err)
+	}
+	if resolver != nil {
 		opts = append(opts, client.WithResolver(resolver))
 	} else if len(b.config.Endpoints) > 0 {
 		opts = append(opts, client.WithHostPorts(b.config.Endpoints...))
 	}
 
-	// 2. Timeouts
-	if b.config.Timeout.RPC > 0 {
-		opts = append(opts, client.WithRPCTimeout(b.config.Timeout.RPC))
+	// 2. Timeouts. An exponential retry backoff waits in a middleware ahead
+	// of the timeout middlewares, so that the wait is not part of an attempt.
+	if b.config.Retry.Enabled && b.config.Retry.BackoffType == "exponential" {
+		opts = append(opts, client.WithMiddleware(retryBackOffMW(newExponentialBackOff(b.config.Retry))))
+	}
+	// Kitex bounds each attempt by the earlier of its RPC timeout and the
+	// context deadline, so preferring the context deadline leaves the Kitex
+	// timeout unset and enforces RPC in a middleware.
+	if b.config.Timeout.PreferContextDeadline {
+		opts = append(opts, client.WithMiddleware(middleware.ContextDeadlineTimeout(b.config.Timeout.RPC)))
+	} else if b.config.Timeout.RPC > 0 {
+		opts = append(opts,
+			client.WithRPCTimeout(b.config.Timeout.RPC),
+			client.WithMiddleware(middleware.RPCTimeoutOverrideLog()),
+		)
 	}
 	if b.config.Timeout.Connect > 0 {
 		opts = append(opts, client.WithConnectTimeout(b.config.Timeout.Connect))
 	}
+	// Kitex client transports derive read deadlines from the RPC timeout,
+	// so the read/write timeout is enforced per attempt by a middleware.
+	if rw := b.config.Timeout.ReadWrite; rw > 0 && (b.config.Timeout.RPC <= 0 || rw < b.config.Timeout.RPC) {
+		opts = append(opts, client.WithMiddleware(middleware.ReadWriteTimeout(rw)))
+	}
+
+	// 3. Transport and TLS. Kitex supports TLS for gRPC only; TTHeader
+	// calls use the Go net transport with a TLS dialer instead.
+	if b.config.Transport == "grpc" {
+		opts = append(opts, client.WithTransportProtocol(transport.GRPC))
+	}
+	// A TLS error fails the client rather than letting it call in plaintext.
+	if b.config.TLS.Enabled {
+		tlsCfg, err := b.config.TLS.ClientTLSConfig()
+		if err != nil {
+			logx.Errorw("Failed to load client TLS config", "serviceName", b.config.ServiceName, "error", err)
+			return b.invalid(fmt.Errorf("srpc: %w", err))
+		}
+		if b.config.Transport == "grpc" {
+			opts = append(opts, client.WithGRPCTLSConfig(tlsCfg))
+		} else {
+			opts = append(opts,
+				client.WithTransHandlerFactory(gonet.NewCliTransHandlerFactory()),
+				client.WithDialer(&tlsDialer{config: tlsCfg}),
+			)
+		}
+	}
 
-	// 3. Load balancer
+	// 4. Load balancer
 	if lb := b.buildLoadBalancer(); lb != nil {
 		opts = append(opts, client.WithLoadBalancer(lb))
 	}
 
-	// 4. Retry policy
+	// 5. Retry policy
 	if b.config.Retry.Enabled {
 		opts = append(opts, b.buildRetryPolicy())
 	}
 
-	// 5. Circuit breaker
+	// 6. Circuit breaker
 	if b.config.CircuitBreaker.Enabled {
 		opts = append(opts, b.buildCircuitBreaker())
 	}
 
-	// 6. Connection pool (long connections)
-	// Note: Long connection pooling is handled internally by Kitex
-	// based on transport protocol
+	// 7. Connection pool (long connections)
+	// Applies to TCP transports (TTHeader/framed/buffered). gRPC and
+	// mux transports manage their own connections and ignore these limits.
+	opts = append(opts, client.WithLongConnection(connpool.IdleConfig{
+		MaxIdlePerAddress: b.config.MaxIdlePerAddress,
+		MaxIdleGlobal:     b.config.MaxIdleGlobal,
+		MaxIdleTimeout:    b.config.MaxIdleTimeout,
+	}))
 
-	// 7. OpenTelemetry tracing middleware
-	// This propagates trace context from incoming requests to outgoing RPC calls
-	opts = append(opts, client.WithSuite(kitextracing.NewClientSuite()))
+	// 8. Goten defaults: trace propagation, retry and outbound metrics
+	opts = append(opts, client.WithSuite(ClientSuite(b.config)))
 
-	// 8. User-provided options
+	// 9. User-provided options
 	opts = append(opts, b.options...)
 
 	return opts
 }
 
+// invalid records err and returns options that make client creation fail
+// with it.
+func (b *ClientBuilder) invalid(err error) []client.Option {
+	b.err = err
+	return []client.Option{client.WithProxy(invalidConfig{err: err})}
+}
+
 // WithOption adds a custom client option.
 func (b *ClientBuilder) WithOption(opt client.Option) *ClientBuilder {
 	b.options = append(b.options, opt)
@@ -99,15 +177,19 @@ func (b *ClientBuilder) WithMiddleware(mw endpoint.Middleware) *ClientBuilder {
 	return b
 }
 
-// buildResolver creates a service resolver based on configuration.
-func (b *ClientBuilder) buildResolver() discovery.Resolver {
+// buildResolver creates a service resolver based on configuration. It fails on an
+// unknown discovery type rather than silently running without discovery.
+func (b *ClientBuilder) buildResolver() (discovery.Resolver, error) {
 	switch b.config.Discovery.Type {
 	case "consul":
-		return b.buildConsulResolver()
+		return b.buildConsulResolver(), nil
 	case "etcd":
-		return b.buildEtcdResolver()
+		return b.buildEtcdResolver(), nil
+	case "", "none", "direct":
+		return nil, nil
 	default:
-		return nil
+		return nil, fmt.Errorf("srpc: discovery: %w %q (expected consul, etcd, direct or none)",
+			ErrUnknownDiscoveryType, b.config.Discovery.Type)
 	}
 }
 
@@ -115,29 +197,44 @@ func (b *ClientBuilder) buildResolver() discovery.Resolver {
 func (b *ClientBuilder) buildConsulResolver() discovery.Resolver {
 	cfg := b.config.Discovery.Consul
 
-	r, err := consul.NewConsulResolver(cfg.Address)
+	r, err := consul.NewConsulResolverWithConfig(consulAPIConfig(cfg))
 	if err != nil {
 		logx.Errorw("Failed to create Consul resolver", "address", cfg.Address, "error", err)
 		return nil
 	}
 
-	logx.Debugw("Consul resolver created", "address", cfg.Address)
+	logx.Debugw("Consul resolver created", "address", cfg.Address, "datacenter", cfg.Datacenter)
 	return r
 }
 
 // buildEtcdResolver creates an etcd resolver.
+// It reads instances stored using the kitex-contrib/registry-etcd key layout.
 func (b *ClientBuilder) buildEtcdResolver() discovery.Resolver {
-	// TODO: Implement etcd resolver when needed
-	logx.Warnw("Etcd resolver is not yet implemented")
-	return nil
+	cfg := b.config.Discovery.Etcd
+
+	r, err := newEtcdResolver(cfg)
+	if err != nil {
+		logx.Errorw("Failed to create etcd resolver", "hosts", cfg.Hosts, "error", err)
+		return nil
+	}
+
+	logx.Debugw("Etcd resolver created", "hosts", cfg.Hosts)
+	return r
 }
 
 // buildLoadBalancer creates a load balancer based on configuration.
 func (b *ClientBuilder) buildLoadBalancer() loadbalance.Loadbalancer {
-	switch b.config.LoadBalancer {
+	return newLoadBalancer(b.config.LoadBalancer)
+}
+
+// newLoadBalancer creates a load balancer of the given type.
+// Unknown types fall back to weighted round-robin.
+func newLoadBalancer(lbType string) loadbalance.Loadbalancer {
+	switch lbType {
 	case "roundrobin":
 		return loadbalance.NewWeightedRoundRobinBalancer()
-	case "random":
+	case "random", "weightedrandom":
+		// Kitex's random balancer honors instance weights.
 		return loadbalance.NewWeightedRandomBalancer()
 	case "consistenthash":
 		return loadbalance.NewConsistBalancer(
@@ -158,30 +255,113 @@ func (b *ClientBuilder) buildLoadBalancer() loadbalance.Loadbalancer {
 
 // buildRetryPolicy creates a retry policy based on configuration.
 func (b *ClientBuilder) buildRetryPolicy() client.Option {
+	return client.WithFailureRetry(newFailurePolicy(b.config.Retry))
+}
+
+// newFailurePolicy maps a retry configuration to a Kitex failure policy.
+func newFailurePolicy(cfg RetryConfig) *retry.FailurePolicy {
 	fp := retry.NewFailurePolicy()
-	fp.WithMaxRetryTimes(b.config.Retry.MaxRetries)
+	fp.WithMaxRetryTimes(cfg.MaxRetries)
+
+	delayMs := int(cfg.Delay.Milliseconds())
+	maxDelayMs := int(cfg.MaxDelay.Milliseconds())
+	switch {
+	case cfg.BackoffType == "exponential":
+		// Waits in retryBackOffMW; see ClientBuilder.Build.
+	case cfg.BackoffType == "random" && maxDelayMs > delayMs:
+		fp.WithRandomBackOff(delayMs, maxDelayMs)
+	case delayMs > 0:
+		fp.WithFixedBackOff(delayMs)
+	}
+	maxDurationMs := cfg.MaxDuration.Milliseconds()
+	if maxDurationMs > 0 && maxDurationMs <= math.MaxUint32 {
+		fp.WithMaxDurationMS(uint32(maxDurationMs))
+	}
+
+	if rr := buildResultRetry(cfg.RetryOn); rr != nil {
+		fp.WithSpecifiedResultRetry(rr)
+	}
+
+	return fp
+}
 
-	if b.config.Retry.Delay > 0 {
-		fp.WithFixedBackOff(int(b.config.Retry.Delay.Milliseconds()))
+// buildResultRetry creates a retry predicate restricted to the given error
+// categories. It returns nil for an empty list so that the Kitex default
+// behavior is preserved.
+func buildResultRetry(retryOn []string) *retry.ShouldResultRetry {
+	if len(retryOn) == 0 {
+		return nil
 	}
-	if b.config.Retry.MaxDelay > 0 {
-		maxDelayMs := b.config.Retry.MaxDelay.Milliseconds()
-		if maxDelayMs > 0 && maxDelayMs <= math.MaxUint32 {
-			fp.WithMaxDurationMS(uint32(maxDelayMs))
+
+	var onTimeout, onConnection, onServerError bool
+	for _, r := range retryOn {
+		switch r {
+		case "timeout":
+			onTimeout = true
+		case "connection":
+			onConnection = true
+		case "server_error":
+			onServerError = true
+		default:
+			logx.Warnw("Unknown retryOn value ignored", "value", r)
 		}
 	}
 
-	return client.WithFailureRetry(fp)
+	return &retry.ShouldResultRetry{
+		ErrorRetryWithCtx: func(_ context.Context, err error, _ rpcinfo.RPCInfo) bool {
+			return isRetryableError(err, onTimeout, onConnection, onServerError)
+		},
+		NotRetryForTimeout: !onTimeout,
+	}
+}
+
+// isRetryableError maps an RPC error to the enabled retry categories.
+func isRetryableError(err error, onTimeout, onConnection, onServerError bool) bool {
+	switch {
+	case err == nil:
+		return false
+	case kerrors.IsTimeoutError(err):
+		return onTimeout
+	case errors.Is(err, kerrors.ErrGetConnection):
+		return onConnection
+	case errors.Is(err, kerrors.ErrRemoteOrNetwork), errors.Is(err, kerrors.ErrInternalException):
+		return onServerError
+	default:
+		return false
+	}
 }
 
 // buildCircuitBreaker creates a circuit breaker based on configuration.
 func (b *ClientBuilder) buildCircuitBreaker() client.Option {
-	cbSuite := circuitbreak.NewCBSuite(func(ri rpcinfo.RPCInfo) string {
+	return client.WithCircuitBreaker(newCBSuite(b.config.CircuitBreaker))
+}
+
+// newCBSuite creates a per-method circuit breaker suite that applies the
+// configured error rate and minimum sample count. Kitex stores the default
+// config for a key the first time it is seen, so the configured values are
+// installed from the key function before that happens.
+func newCBSuite(cfg CircuitBreakerConfig) *circuitbreak.CBSuite {
+	cbConfig := circuitbreak.CBConfig{
+		Enable:    true,
+		ErrRate:   cfg.ErrorRate,
+		MinSample: cfg.MinSamples,
+	}
+
+	var (
+		cbSuite *circuitbreak.CBSuite
+		seen    sync.Map
+	)
+	cbSuite = circuitbreak.NewCBSuite(func(ri rpcinfo.RPCInfo) string {
 		// Key by service/method for per-method circuit breaking
-		return ri.To().ServiceName() + "/" + ri.To().Method()
+		key := ri.To().ServiceName() + "/" + ri.To().Method()
+		if _, loaded := seen.LoadOrStore(key, struct{}{}); !loaded {
+			cbSuite.UpdateServiceCBConfig(key, cbConfig)
+		}
+		return key
 	})
+	cbSuite.UpdateInstanceCBConfig(cbConfig)
 
-	return client.WithCircuitBreaker(cbSuite)
+	return cbSuite
 }
 
 // BuildClient is a convenience function that creates options for a client.
@@ -244,18 +424,9 @@ func WithCircuitBreaker() client.Option {
 }
 
 // WithLoadBalancer returns a client option for the specified load balancer type.
-// Supported types: "roundrobin", "random", "consistenthash"
+// Supported types: "roundrobin", "random", "weightedrandom", "consistenthash"
 func WithLoadBalancer(lbType string) client.Option {
-	var lb loadbalance.Loadbalancer
-	switch lbType {
-	case "roundrobin":
-		lb = loadbalance.NewWeightedRoundRobinBalancer()
-	case "random":
-		lb = loadbalance.NewWeightedRandomBalancer()
-	default:
-		lb = loadbalance.NewWeightedRoundRobinBalancer()
-	}
-	return client.WithLoadBalancer(lb)
+	return client.WithLoadBalancer(newLoadBalancer(lbType))
 }
 
 // MustNewClient creates a new RPC client using the provided factory function and configuration.
@@ -291,15 +462,20 @@ func MustNewClient[T any](
 	if cfg == nil {
 		panic("srpc.MustNewClient: config is nil")
 	}
-	cfg.SetDefaults()
-	opts := BuildClient(cfg)
+	builder := NewClientBuilder(cfg)
+	opts := builder.Build()
+	if err := builder.Err(); err != nil {
+		panic(fmt.Sprintf("srpc.MustNewClient: invalid config for %s: %v", cfg.ServiceName, err))
+	}
 	cli, err := newClientFn(cfg.ServiceName, opts...)
 	if err != nil {
 		panic(fmt.Sprintf("srpc.MustNewClient: failed to create client for %s: %v", cfg.ServiceName, err))
 	}
+	recordClientInfo(cfg)
 	logx.Infow("RPC client created",
 		"serviceName", cfg.ServiceName,
 		"discoveryType", cfg.Discovery.Type,
+		"loadBalancer", cfg.LoadBalancer,
 	)
 	return cli
 }
@@ -321,15 +497,20 @@ func NewClientWithConfig[T any](
 	if cfg == nil {
 		return zero, fmt.Errorf("srpc.NewClientWithConfig: config is nil")
 	}
-	cfg.SetDefaults()
-	opts := BuildClient(cfg)
+	builder := NewClientBuilder(cfg)
+	opts := builder.Build()
+	if err := builder.Err(); err != nil {
+		return zero, fmt.Errorf("srpc.NewClientWithConfig: invalid config for %s: %w", cfg.ServiceName, err)
+	}
 	cli, err := newClientFn(cfg.ServiceName, opts...)
 	if err != nil {
 		return zero, fmt.Errorf("srpc.NewClientWithConfig: failed to create client for %s: %w", cfg.ServiceName, err)
 	}
+	recordClientInfo(cfg)
 	logx.Infow("RPC client created",
 		"serviceName", cfg.ServiceName,
 		"discoveryType", cfg.Discovery.Type,
+		"loadBalancer", cfg.LoadBalancer,
 	)
 	return cli, nil
 }
@@ -0,0 +1,262 @@
+package srpc
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/cloudwego/kitex/client"
+	"github.com/cloudwego/kitex/client/genericclient"
+	"github.com/cloudwego/kitex/pkg/generic"
+	"github.com/cloudwego/kitex/pkg/kerrors"
+	"github.com/cloudwego/kitex/pkg/retry"
+	"github.com/cloudwego/kitex/pkg/rpcinfo"
+)
+
+func TestClientBuilderInvalidConfig(t *testing.T) {
+	tests := []struct {
+		name    string
+		cfg     ClientConfig
+		wantErr bool
+	}{
+		{name: "valid", cfg: ClientConfig{ServiceName: "user", Endpoints: []string{"127.0.0.1:8888"}}},
+		{name: "invalid", cfg: ClientConfig{ServiceName: "user", LoadBalancer: "leastconn"}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			builder := NewClientBuilder(&tt.cfg)
+			if err := builder.Err(); (err != nil) != tt.wantErr {
+				t.Fatalf("Err() = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			cli, err := genericclient.NewClient("user", generic.BinaryThriftGeneric(), builder.Build()...)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("NewClient() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if cli != nil {
+				_ = cli.Close()
+			}
+
+			called := false
+			newClient := func(string, ...client.Option) (genericclient.Client, error) {
+				called = true
+				return nil, nil
+			}
+			if _, err := NewClientWithConfig(newClient, &tt.cfg); (err != nil) != tt.wantErr {
+				t.Fatalf("NewClientWithConfig() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if called == tt.wantErr {
+				t.Errorf("client factory called = %v with wantErr %v", called, tt.wantErr)
+			}
+			if tt.wantErr {
+				defer func() {
+					if recover() == nil {
+						t.Error("MustNewClient did not panic on an invalid config")
+					}
+				}()
+				MustNewClient(newClient, &tt.cfg)
+			}
+		})
+	}
+}
+
+func TestNewCBSuite(t *testing.T) {
+	tests := []struct {
+		name     string
+		cfg      CircuitBreakerConfig
+		fail     func(call int) bool
+		wantOpen int // calls that pass before the breaker opens; 0 if it stays closed
+	}{
+		{
+			name:     "opens after min samples",
+			cfg:      CircuitBreakerConfig{ErrorRate: 0.5, MinSamples: 10},
+			fail:     func(int) bool { return true },
+			wantOpen: 10,
+		},
+		{
+			name:     "lower min samples opens sooner",
+			cfg:      CircuitBreakerConfig{ErrorRate: 0.5, MinSamples: 4},
+			fail:     func(int) bool { return true },
+			wantOpen: 4,
+		},
+		{
+			name: "error rate below threshold",
+			cfg:  CircuitBreakerConfig{ErrorRate: 0.8, MinSamples: 4},
+			fail: func(call int) bool { return call%2 == 0 },
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			suite := newCBSuite(tt.cfg)
+			t.Cleanup(func() { _ = suite.Close() })
+
+			ri := rpcinfo.NewRPCInfo(nil, rpcinfo.NewEndpointInfo("user", "Get", nil, nil),
+				rpcinfo.NewInvocation("user", "Get"), rpcinfo.NewRPCConfig(), rpcinfo.NewRPCStats())
+			ctx := rpcinfo.NewCtxWithRPCInfo(context.Background(), ri)
+
+			var call int
+			errBackend := errors.New("backend failed")
+			ep := suite.ServiceCBMW()(func(context.Context, interface{}, interface{}) error {
+				call++
+				if tt.fail(call) {
+					return errBackend
+				}
+				return nil
+			})
+
+			passed := 0
+			for i := 0; i < 20; i++ {
+				if err := ep(ctx, nil, nil); errors.Is(err, kerrors.ErrCircuitBreak) {
+					break
+				}
+				passed++
+			}
+			if tt.wantOpen == 0 {
+				if passed != 20 {
+					t.Fatalf("breaker opened after %d calls, want it to stay closed", passed)
+				}
+				return
+			}
+			if passed != tt.wantOpen {
+				t.Fatalf("breaker opened after %d calls, want %d", passed, tt.wantOpen)
+			}
+		})
+	}
+}
+
+func TestBuildResultRetry(t *testing.T) {
+	errs := map[string]error{
+		"timeout":      kerrors.ErrRPCTimeout.WithCause(errors.New("deadline")),
+		"connection":   kerrors.ErrGetConnection.WithCause(errors.New("refused")),
+		"remote":       kerrors.ErrRemoteOrNetwork.WithCause(errors.New("reset")),
+		"internal":     kerrors.ErrInternalException,
+		"business":     errors.New("not found"),
+		"circuitbreak": kerrors.ErrCircuitBreak,
+	}
+
+	tests := []struct {
+		name    string
+		retryOn []string
+		want    []string // keys of errs that are retried
+	}{
+		{name: "timeout", retryOn: []string{"timeout"}, want: []string{"timeout"}},
+		{name: "connection", retryOn: []string{"connection"}, want: []string{"connection"}},
+		{name: "server error", retryOn: []string{"server_error"}, want: []string{"remote", "internal"}},
+		{
+			name:    "all",
+			retryOn: []string{"timeout", "connection", "server_error"},
+			want:    []string{"timeout", "connection", "remote", "internal"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rr := buildResultRetry(tt.retryOn)
+			if rr == nil {
+				t.Fatal("buildResultRetry() = nil")
+			}
+			for name, err := range errs {
+				want := slices.Contains(tt.want, name)
+				if got := rr.ErrorRetryWithCtx(context.Background(), err, nil); got != want {
+					t.Errorf("retry on %s error = %v, want %v", name, got, want)
+				}
+			}
+			if rr.ErrorRetryWithCtx(context.Background(), nil, nil) {
+				t.Error("retry on success")
+			}
+			if got, want := rr.NotRetryForTimeout, !slices.Contains(tt.retryOn, "timeout"); got != want {
+				t.Errorf("NotRetryForTimeout = %v, want %v", got, want)
+			}
+		})
+	}
+
+	if rr := buildResultRetry(nil); rr != nil {
+		t.Error("buildResultRetry(nil) != nil, want the Kitex default")
+	}
+}
+
+func TestNewFailurePolicy(t *testing.T) {
+	tests := []struct {
+		name            string
+		cfg             RetryConfig
+		wantBackOff     retry.BackOffType
+		wantCfg         map[retry.BackOffCfgKey]float64
+		wantMaxDuration uint32
+	}{
+		{
+			name:        "fixed",
+			cfg:         RetryConfig{BackoffType: "fixed", Delay: 100 * time.Millisecond, MaxDelay: time.Second},
+			wantBackOff: retry.FixedBackOffType,
+			wantCfg:     map[retry.BackOffCfgKey]float64{retry.FixMSBackOffCfgKey: 100},
+		},
+		{
+			name:        "random bounded per retry by max delay",
+			cfg:         RetryConfig{BackoffType: "random", Delay: 100 * time.Millisecond, MaxDelay: time.Second},
+			wantBackOff: retry.RandomBackOffType,
+			wantCfg: map[retry.BackOffCfgKey]float64{
+				retry.MinMSBackOffCfgKey: 100,
+				retry.MaxMSBackOffCfgKey: 1000,
+			},
+		},
+		{
+			name:        "exponential waits in a middleware",
+			cfg:         RetryConfig{BackoffType: "exponential", Delay: 100 * time.Millisecond, MaxDelay: time.Second},
+			wantBackOff: retry.NoneBackOffType,
+		},
+		{
+			name: "max duration bounds all retries",
+			cfg: RetryConfig{
+				BackoffType: "fixed",
+				Delay:       100 * time.Millisecond,
+				MaxDelay:    time.Second,
+				MaxDuration: 3 * time.Second,
+			},
+			wantBackOff:     retry.FixedBackOffType,
+			wantCfg:         map[retry.BackOffCfgKey]float64{retry.FixMSBackOffCfgKey: 100},
+			wantMaxDuration: 3000,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fp := newFailurePolicy(tt.cfg)
+			if fp.BackOffPolicy == nil || fp.BackOffPolicy.BackOffType != tt.wantBackOff {
+				t.Fatalf("backoff = %+v, want %s", fp.BackOffPolicy, tt.wantBackOff)
+			}
+			for k, v := range tt.wantCfg {
+				if got := fp.BackOffPolicy.CfgItems[k]; got != v {
+					t.Errorf("backoff %s = %v, want %v", k, got, v)
+				}
+			}
+			if fp.StopPolicy.MaxDurationMS != tt.wantMaxDuration {
+				t.Errorf("max duration = %dms, want %dms", fp.StopPolicy.MaxDurationMS, tt.wantMaxDuration)
+			}
+		})
+	}
+}
+
+func TestNewLoadBalancer(t *testing.T) {
+	tests := []struct {
+		name   string
+		lbType string
+		want   string
+	}{
+		{name: "roundrobin", lbType: "roundrobin", want: "weight_round_robin"},
+		{name: "random", lbType: "random", want: "weight_random"},
+		{name: "weightedrandom", lbType: "weightedrandom", want: "weight_random"},
+		{name: "consistenthash", lbType: "consistenthash", want: "consist"},
+		{name: "default", lbType: "", want: "weight_round_robin"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := newLoadBalancer(tt.lbType).Name(); got != tt.want {
+				t.Errorf("newLoadBalancer(%q) = %s, want %s", tt.lbType, got, tt.want)
+			}
+		})
+	}
+}
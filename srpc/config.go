@@ -4,6 +4,9 @@
 package srpc
 
 import (
+	"errors"
+	"fmt"
+	"math"
 	"time"
 
 	"github.com/ssgohq/goten-core/trace"
@@ -27,6 +30,11 @@ type ServerConfig struct {
 	// Timeout settings for connections.
 	Timeout TimeoutConfig `yaml:"timeout,omitempty" json:"timeout,omitempty"`
 
+	// TLS configuration. When enabled, the server uses the Go net transport,
+	// which serves TTHeader and framed calls but not gRPC, and verifies
+	// client certificates if a CA file is set (mTLS).
+	TLS TLSConfig `yaml:"tls,omitempty" json:"tls,omitempty"`
+
 	// MaxConnections limits the maximum number of concurrent connections.
 	// 0 means unlimited.
 	MaxConnections int `yaml:"maxConnections,omitempty" json:"maxConnections,omitempty"`
@@ -37,9 +45,13 @@ type ServerConfig struct {
 	EnableRecovery bool `yaml:"enableRecovery,omitempty" json:"enableRecovery,omitempty"`
 	// EnableAccessLog enables request/response logging. Default: false
 	EnableAccessLog bool `yaml:"enableAccessLog,omitempty" json:"enableAccessLog,omitempty"`
+	// EnableMetrics enables the Prometheus metrics middleware. Default: false
+	EnableMetrics bool `yaml:"enableMetrics,omitempty" json:"enableMetrics,omitempty"`
 }
 
 // SetDefaults applies sensible defaults to the configuration.
+// It only fills zero-valued fields, including those of nested configs, so
+// it is idempotent: the builder and ServerSuite may both call it.
 func (c *ServerConfig) SetDefaults() {
 	if c.Host == "" {
 		c.Host = "0.0.0.0"
@@ -47,16 +59,42 @@ func (c *ServerConfig) SetDefaults() {
 	if c.Port == 0 {
 		c.Port = 8888
 	}
-	if c.Timeout.Read == 0 {
-		c.Timeout.Read = 3 * time.Second
+	c.Timeout.SetDefaults()
+	c.Discovery.SetDefaults()
+}
+
+// Validate checks the configuration for invalid values.
+func (c *ServerConfig) Validate() error {
+	if c.Name == "" {
+		return errors.New("srpc: server name is required")
+	}
+	if c.Port < 0 || c.Port > 65535 {
+		return fmt.Errorf("srpc: port %d out of range [0, 65535]", c.Port)
 	}
-	if c.Timeout.Write == 0 {
-		c.Timeout.Write = 3 * time.Second
+	if c.MaxConnections < 0 {
+		return fmt.Errorf("srpc: maxConnections must be >= 0, got %d", c.MaxConnections)
 	}
-	if c.Timeout.Idle == 0 {
-		c.Timeout.Idle = 60 * time.Second
+	if c.MaxQPS < 0 {
+		return fmt.Errorf("srpc: maxQps must be >= 0, got %d", c.MaxQPS)
 	}
-	c.Discovery.SetDefaults()
+	if err := c.Timeout.Validate(); err != nil {
+		return fmt.Errorf("srpc: timeout: %w", err)
+	}
+	if err := c.TLS.Validate(); err != nil {
+		return fmt.Errorf("srpc: %w", err)
+	}
+	if c.TLS.Enabled && c.TLS.CertFile == "" {
+		return errors.New("srpc: tls: server requires certFile and keyFile")
+	}
+	if err := c.Discovery.Validate(); err != nil {
+		return fmt.Errorf("srpc: discovery: %w", err)
+	}
+	if c.Trace.IsEnabled() {
+		if err := c.Trace.Validate(); err != nil {
+			return fmt.Errorf("srpc: %w", err)
+		}
+	}
+	return nil
 }
 
 // TimeoutConfig represents timeout settings.
@@ -69,6 +107,33 @@ type TimeoutConfig struct {
 	Idle time.Duration `yaml:"idle,omitempty" json:"idle,omitempty"`
 }
 
+// SetDefaults applies sensible defaults to the timeout configuration.
+func (c *TimeoutConfig) SetDefaults() {
+	if c.Read == 0 {
+		c.Read = 3 * time.Second
+	}
+	if c.Write == 0 {
+		c.Write = 3 * time.Second
+	}
+	if c.Idle == 0 {
+		c.Idle = 60 * time.Second
+	}
+}
+
+// Validate checks the timeout settings for negative values.
+func (c *TimeoutConfig) Validate() error {
+	if c.Read < 0 {
+		return fmt.Errorf("read must be >= 0, got %s", c.Read)
+	}
+	if c.Write < 0 {
+		return fmt.Errorf("write must be >= 0, got %s", c.Write)
+	}
+	if c.Idle < 0 {
+		return fmt.Errorf("idle must be >= 0, got %s", c.Idle)
+	}
+	return nil
+}
+
 // DiscoveryConfig represents service discovery configuration.
 type DiscoveryConfig struct {
 	// Type specifies the discovery backend: "consul", "etcd", "direct", or "none".
@@ -89,6 +154,24 @@ func (c *DiscoveryConfig) SetDefaults() {
 	c.Etcd.SetDefaults()
 }
 
+// ErrUnknownDiscoveryType is returned by Validate when Discovery.Type is not
+// one of the supported values, e.g. because of a typo such as "consol".
+var ErrUnknownDiscoveryType = errors.New("unknown discovery type")
+
+// Validate checks the discovery type and the settings of the selected backend.
+func (c *DiscoveryConfig) Validate() error {
+	switch c.Type {
+	case "", "none", "direct":
+		return nil
+	case "consul":
+		return c.Consul.Validate()
+	case "etcd":
+		return c.Etcd.Validate()
+	default:
+		return fmt.Errorf("%w %q (expected consul, etcd, direct or none)", ErrUnknownDiscoveryType, c.Type)
+	}
+}
+
 // ConsulConfig represents Consul-specific configuration.
 type ConsulConfig struct {
 	// Address is the Consul agent address. Default: "localhost:8500"
@@ -97,8 +180,13 @@ type ConsulConfig struct {
 	Token string `yaml:"token,omitempty" json:"token,omitempty"`
 	// Datacenter specifies the datacenter to use.
 	Datacenter string `yaml:"datacenter,omitempty" json:"datacenter,omitempty"`
-	// HealthCheck enables gRPC health checking. Default: true
-	HealthCheck bool `yaml:"healthCheck,omitempty" json:"healthCheck,omitempty"`
+	// HealthCheck registers a TCP health check with the service. An unset
+	// value enables it. Default: true
+	// Example: healthCheck: false
+	HealthCheck *bool `yaml:"healthCheck,omitempty" json:"healthCheck,omitempty"`
+	// DisableHealthCheck skips the TCP health check registered with the
+	// service and takes precedence over HealthCheck. Default: false
+	DisableHealthCheck bool `yaml:"disableHealthCheck,omitempty" json:"disableHealthCheck,omitempty"`
 	// CheckTimeout is the health check timeout. Default: 5s
 	CheckTimeout time.Duration `yaml:"checkTimeout,omitempty" json:"checkTimeout,omitempty"`
 	// Interval is the health check interval. Default: 10s
@@ -106,7 +194,8 @@ type ConsulConfig struct {
 	// DeregisterAfter is the duration after which a service is deregistered
 	// if it's been critical. Default: 1m
 	DeregisterAfter time.Duration `yaml:"deregisterAfter,omitempty" json:"deregisterAfter,omitempty"`
-	// Tags are the service tags for filtering.
+	// Tags are the service tags for filtering, in "key:value" form.
+	// A tag without a value is registered with an empty value.
 	Tags []string `yaml:"tags,omitempty" json:"tags,omitempty"`
 }
 
@@ -126,6 +215,25 @@ func (c *ConsulConfig) SetDefaults() {
 	}
 }
 
+// IsHealthCheckEnabled reports whether the health check should be registered.
+func (c ConsulConfig) IsHealthCheckEnabled() bool {
+	if c.DisableHealthCheck {
+		return false
+	}
+	return c.HealthCheck == nil || *c.HealthCheck
+}
+
+// Validate checks the Consul configuration.
+func (c *ConsulConfig) Validate() error {
+	if c.Address == "" {
+		return errors.New("consul: address is required")
+	}
+	if c.CheckTimeout < 0 || c.Interval < 0 || c.DeregisterAfter < 0 {
+		return errors.New("consul: checkTimeout, interval and deregisterAfter must be >= 0")
+	}
+	return nil
+}
+
 // EtcdConfig represents etcd-specific configuration.
 type EtcdConfig struct {
 	// Hosts is the list of etcd endpoints. Default: ["localhost:2379"]
@@ -138,11 +246,25 @@ type EtcdConfig struct {
 
 // SetDefaults applies sensible defaults to the etcd configuration.
 func (c *EtcdConfig) SetDefaults() {
+	// Hosts are replaced only when empty, never appended to.
 	if len(c.Hosts) == 0 {
 		c.Hosts = []string{"localhost:2379"}
 	}
 }
 
+// Validate checks the etcd configuration.
+func (c *EtcdConfig) Validate() error {
+	if len(c.Hosts) == 0 {
+		return errors.New("etcd: at least one host is required")
+	}
+	for _, h := range c.Hosts {
+		if h == "" {
+			return errors.New("etcd: hosts must not contain empty entries")
+		}
+	}
+	return nil
+}
+
 // ClientConfig represents RPC client configuration.
 type ClientConfig struct {
 	// ServiceName is the target service name for discovery.
@@ -158,6 +280,15 @@ type ClientConfig struct {
 	// Timeout settings for RPC calls.
 	Timeout ClientTimeoutConfig `yaml:"timeout,omitempty" json:"timeout,omitempty"`
 
+	// TLS configuration for connecting to the target service.
+	TLS TLSConfig `yaml:"tls,omitempty" json:"tls,omitempty"`
+
+	// Transport selects the transport protocol: "ttheader" or "grpc".
+	// With TLS enabled, "grpc" uses Kitex's gRPC TLS support, while
+	// "ttheader" switches the client to the Go net transport.
+	// Default: "ttheader"
+	Transport string `yaml:"transport,omitempty" json:"transport,omitempty"`
+
 	// Retry configuration.
 	Retry RetryConfig `yaml:"retry,omitempty" json:"retry,omitempty"`
 
@@ -169,22 +300,36 @@ type ClientConfig struct {
 	// Default: "roundrobin"
 	LoadBalancer string `yaml:"loadBalancer,omitempty" json:"loadBalancer,omitempty"`
 
-	// Connection pool settings.
+	// Connection pool settings, applied through Kitex long connections.
+	// They take effect for TCP transports only; gRPC and mux transports
+	// multiplex requests over their own connections and ignore them.
 	// MaxIdlePerAddress is the maximum idle connections per address.
 	MaxIdlePerAddress int `yaml:"maxIdlePerAddress,omitempty" json:"maxIdlePerAddress,omitempty"`
 	// MaxIdleGlobal is the maximum idle connections globally.
 	MaxIdleGlobal int `yaml:"maxIdleGlobal,omitempty" json:"maxIdleGlobal,omitempty"`
 	// MaxIdleTimeout is the maximum duration a connection can be idle.
 	MaxIdleTimeout time.Duration `yaml:"maxIdleTimeout,omitempty" json:"maxIdleTimeout,omitempty"`
+
+	// EnableMetrics enables the outbound Prometheus metrics middleware.
+	// Default: false
+	EnableMetrics bool `yaml:"enableMetrics,omitempty" json:"enableMetrics,omitempty"`
+
+	// UntracedMethods lists methods whose calls create no client span, for
+	// high-volume calls such as health pings. See middleware.SkipTracing.
+	UntracedMethods []string `yaml:"untracedMethods,omitempty" json:"untracedMethods,omitempty"`
 }
 
 // SetDefaults applies sensible defaults to the client configuration.
+// Like ServerConfig.SetDefaults, it is idempotent.
 func (c *ClientConfig) SetDefaults() {
 	c.Discovery.SetDefaults()
 	c.Timeout.SetDefaults()
 	c.Retry.SetDefaults()
 	c.CircuitBreaker.SetDefaults()
 
+	if c.Transport == "" {
+		c.Transport = "ttheader"
+	}
 	if c.LoadBalancer == "" {
 		c.LoadBalancer = "roundrobin"
 	}
@@ -199,14 +344,61 @@ func (c *ClientConfig) SetDefaults() {
 	}
 }
 
+// Validate checks the configuration for invalid values.
+func (c *ClientConfig) Validate() error {
+	if err := c.Discovery.Validate(); err != nil {
+		return fmt.Errorf("srpc: discovery: %w", err)
+	}
+	if err := c.Timeout.Validate(); err != nil {
+		return fmt.Errorf("srpc: timeout: %w", err)
+	}
+	if err := c.TLS.Validate(); err != nil {
+		return fmt.Errorf("srpc: %w", err)
+	}
+	switch c.Transport {
+	case "", "ttheader", "grpc":
+	default:
+		return fmt.Errorf("srpc: unknown transport %q (expected ttheader or grpc)", c.Transport)
+	}
+	if err := c.Retry.Validate(); err != nil {
+		return fmt.Errorf("srpc: retry: %w", err)
+	}
+	if err := c.CircuitBreaker.Validate(); err != nil {
+		return fmt.Errorf("srpc: circuitBreaker: %w", err)
+	}
+	switch c.LoadBalancer {
+	case "", "roundrobin", "random", "weightedrandom", "consistenthash":
+	default:
+		return fmt.Errorf("srpc: unknown loadBalancer %q", c.LoadBalancer)
+	}
+	if c.MaxIdlePerAddress < 0 || c.MaxIdleGlobal < 0 || c.MaxIdleTimeout < 0 {
+		return errors.New("srpc: maxIdlePerAddress, maxIdleGlobal and maxIdleTimeout must be >= 0")
+	}
+	return nil
+}
+
 // ClientTimeoutConfig represents client timeout settings.
 type ClientTimeoutConfig struct {
-	// RPC is the timeout for the entire RPC call. Default: 3s
+	// RPC is the timeout for each attempt of an RPC call. Default: 3s
+	//
+	// When the call's context has a deadline, the attempt ends at the earlier
+	// of the two: a tighter context deadline wins, and a looser one is capped
+	// at RPC. Set PreferContextDeadline to let a looser deadline win too.
 	RPC time.Duration `yaml:"rpc,omitempty" json:"rpc,omitempty"`
 	// Connect is the timeout for establishing connection. Default: 1s
 	Connect time.Duration `yaml:"connect,omitempty" json:"connect,omitempty"`
-	// ReadWrite is the timeout for read/write operations.
+	// ReadWrite is the timeout for each attempt to send a request and
+	// receive its response. Default: 0 (disabled)
+	//
+	// RPC bounds every attempt as a whole and Connect bounds dialing within
+	// it. ReadWrite only takes effect when it is shorter than RPC, failing
+	// the attempt early so that a retry can still fit in the call's budget.
 	ReadWrite time.Duration `yaml:"readWrite,omitempty" json:"readWrite,omitempty"`
+	// PreferContextDeadline makes a deadline on the call's context replace
+	// RPC rather than be capped by it, so callers can grant a call more time
+	// than configured. RPC then only bounds calls without a deadline.
+	// Default: false
+	PreferContextDeadline bool `yaml:"preferContextDeadline,omitempty" json:"preferContextDeadline,omitempty"`
 }
 
 // SetDefaults applies sensible defaults to the timeout configuration.
@@ -219,6 +411,20 @@ func (c *ClientTimeoutConfig) SetDefaults() {
 	}
 }
 
+// Validate checks the timeout settings for negative values.
+func (c *ClientTimeoutConfig) Validate() error {
+	if c.RPC < 0 {
+		return fmt.Errorf("rpc must be >= 0, got %s", c.RPC)
+	}
+	if c.Connect < 0 {
+		return fmt.Errorf("connect must be >= 0, got %s", c.Connect)
+	}
+	if c.ReadWrite < 0 {
+		return fmt.Errorf("readWrite must be >= 0, got %s", c.ReadWrite)
+	}
+	return nil
+}
+
 // RetryConfig represents retry configuration.
 type RetryConfig struct {
 	// Enabled enables retry on failure. Default: false
@@ -227,11 +433,25 @@ type RetryConfig struct {
 	MaxRetries int `yaml:"maxRetries,omitempty" json:"maxRetries,omitempty"`
 	// Delay is the initial delay between retries. Default: 100ms
 	Delay time.Duration `yaml:"delay,omitempty" json:"delay,omitempty"`
-	// MaxDelay is the maximum delay between retries. Default: 1s
+	// MaxDelay is the upper bound of the delay before each retry, used by
+	// the random and exponential backoffs. Default: 1s
 	MaxDelay time.Duration `yaml:"maxDelay,omitempty" json:"maxDelay,omitempty"`
+	// MaxDuration bounds the total time spent on a call and its retries; no
+	// retry is started once it has passed. Zero leaves only the call
+	// timeout. Default: 0
+	MaxDuration time.Duration `yaml:"maxDuration,omitempty" json:"maxDuration,omitempty"`
 	// RetryOn specifies which error types to retry on.
 	// Options: "timeout", "connection", "server_error"
 	RetryOn []string `yaml:"retryOn,omitempty" json:"retryOn,omitempty"`
+	// BackoffType selects the delay before each retry: "fixed" waits Delay,
+	// "random" waits a random time between Delay and MaxDelay so that
+	// callers do not retry in lockstep, and "exponential" waits Delay·2^n
+	// before retry n+1, capped at MaxDelay.
+	// Default: "fixed"
+	BackoffType string `yaml:"backoffType,omitempty" json:"backoffType,omitempty"`
+	// Jitter draws each exponential delay at random from its upper half,
+	// between half and all of it. Default: false
+	Jitter bool `yaml:"jitter,omitempty" json:"jitter,omitempty"`
 }
 
 // SetDefaults applies sensible defaults to the retry configuration.
@@ -245,6 +465,41 @@ func (c *RetryConfig) SetDefaults() {
 	if c.MaxDelay == 0 {
 		c.MaxDelay = time.Second
 	}
+	if c.BackoffType == "" {
+		c.BackoffType = "fixed"
+	}
+}
+
+// Validate checks the retry configuration.
+func (c *RetryConfig) Validate() error {
+	if !c.Enabled {
+		return nil
+	}
+	if c.MaxRetries < 0 {
+		return fmt.Errorf("maxRetries must be >= 0, got %d", c.MaxRetries)
+	}
+	if c.Delay < 0 || c.MaxDelay < 0 || c.MaxDuration < 0 {
+		return errors.New("delay, maxDelay and maxDuration must be >= 0")
+	}
+	if c.MaxDuration.Milliseconds() > math.MaxUint32 {
+		return fmt.Errorf("maxDuration must not exceed %dms, got %s", uint32(math.MaxUint32), c.MaxDuration)
+	}
+	if c.MaxDelay > 0 && c.Delay > c.MaxDelay {
+		return fmt.Errorf("delay (%s) must not exceed maxDelay (%s)", c.Delay, c.MaxDelay)
+	}
+	switch c.BackoffType {
+	case "", "fixed", "random", "exponential":
+	default:
+		return fmt.Errorf("unknown backoffType %q (expected fixed, random or exponential)", c.BackoffType)
+	}
+	for _, r := range c.RetryOn {
+		switch r {
+		case "timeout", "connection", "server_error":
+		default:
+			return fmt.Errorf("unknown retryOn value %q (expected timeout, connection or server_error)", r)
+		}
+	}
+	return nil
 }
 
 // CircuitBreakerConfig represents circuit breaker configuration.
@@ -268,3 +523,17 @@ func (c *CircuitBreakerConfig) SetDefaults() {
 		c.MinSamples = 200
 	}
 }
+
+// Validate checks the circuit breaker thresholds.
+func (c *CircuitBreakerConfig) Validate() error {
+	if !c.Enabled {
+		return nil
+	}
+	if c.ErrorRate <= 0 || c.ErrorRate > 1 {
+		return fmt.Errorf("errorRate must be in (0, 1], got %g", c.ErrorRate)
+	}
+	if c.MinSamples <= 0 {
+		return fmt.Errorf("minSamples must be > 0, got %d", c.MinSamples)
+	}
+	return nil
+}
@@ -0,0 +1,155 @@
+package srpc
+
+import (
+	"testing"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+func TestServerConfigValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		cfg     ServerConfig
+		wantErr bool
+	}{
+		{name: "valid", cfg: ServerConfig{Name: "user", Port: 8888}},
+		{name: "missing name", cfg: ServerConfig{Port: 8888}, wantErr: true},
+		{name: "port out of range", cfg: ServerConfig{Name: "user", Port: 70000}, wantErr: true},
+		{name: "negative max connections", cfg: ServerConfig{Name: "user", MaxConnections: -1}, wantErr: true},
+		{name: "negative max qps", cfg: ServerConfig{Name: "user", MaxQPS: -1}, wantErr: true},
+		{
+			name:    "negative read timeout",
+			cfg:     ServerConfig{Name: "user", Timeout: TimeoutConfig{Read: -time.Second}},
+			wantErr: true,
+		},
+		{
+			name:    "unknown discovery type",
+			cfg:     ServerConfig{Name: "user", Discovery: DiscoveryConfig{Type: "zookeeper"}},
+			wantErr: true,
+		},
+		{
+			name:    "consul without address",
+			cfg:     ServerConfig{Name: "user", Discovery: DiscoveryConfig{Type: "consul"}},
+			wantErr: true,
+		},
+		{
+			name:    "etcd with an empty host",
+			cfg:     ServerConfig{Name: "user", Discovery: DiscoveryConfig{Type: "etcd", Etcd: EtcdConfig{Hosts: []string{""}}}},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.cfg.Validate(); (err != nil) != tt.wantErr {
+				t.Errorf("Validate() = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestClientConfigValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		cfg     ClientConfig
+		wantErr bool
+	}{
+		{name: "valid", cfg: ClientConfig{ServiceName: "user", Endpoints: []string{"127.0.0.1:8888"}}},
+		{name: "unknown load balancer", cfg: ClientConfig{LoadBalancer: "leastconn"}, wantErr: true},
+		{
+			name:    "negative rpc timeout",
+			cfg:     ClientConfig{Timeout: ClientTimeoutConfig{RPC: -time.Second}},
+			wantErr: true,
+		},
+		{
+			name:    "negative max retries",
+			cfg:     ClientConfig{Retry: RetryConfig{Enabled: true, MaxRetries: -1}},
+			wantErr: true,
+		},
+		{
+			name:    "unknown retryOn value",
+			cfg:     ClientConfig{Retry: RetryConfig{Enabled: true, RetryOn: []string{"always"}}},
+			wantErr: true,
+		},
+		{
+			name:    "error rate above one",
+			cfg:     ClientConfig{CircuitBreaker: CircuitBreakerConfig{Enabled: true, ErrorRate: 2, MinSamples: 10}},
+			wantErr: true,
+		},
+		{
+			name:    "zero min samples",
+			cfg:     ClientConfig{CircuitBreaker: CircuitBreakerConfig{Enabled: true, ErrorRate: 0.5}},
+			wantErr: true,
+		},
+		{name: "negative idle connections", cfg: ClientConfig{MaxIdleGlobal: -1}, wantErr: true},
+		{
+			name:    "unknown discovery type",
+			cfg:     ClientConfig{Discovery: DiscoveryConfig{Type: "zookeeper"}},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.cfg.Validate(); (err != nil) != tt.wantErr {
+				t.Errorf("Validate() = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestRetryConfigValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		cfg     RetryConfig
+		wantErr bool
+	}{
+		{name: "disabled", cfg: RetryConfig{BackoffType: "bogus"}},
+		{name: "fixed", cfg: RetryConfig{Enabled: true, BackoffType: "fixed"}},
+		{name: "random", cfg: RetryConfig{Enabled: true, BackoffType: "random"}},
+		{name: "exponential", cfg: RetryConfig{Enabled: true, BackoffType: "exponential", Jitter: true}},
+		{name: "unknown backoff", cfg: RetryConfig{Enabled: true, BackoffType: "linear"}, wantErr: true},
+		{name: "negative max duration", cfg: RetryConfig{Enabled: true, MaxDuration: -time.Second}, wantErr: true},
+		{
+			name:    "delay above max delay",
+			cfg:     RetryConfig{Enabled: true, Delay: 2 * time.Second, MaxDelay: time.Second},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.cfg.Validate(); (err != nil) != tt.wantErr {
+				t.Errorf("Validate() = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestConsulConfigHealthCheck(t *testing.T) {
+	tests := []struct {
+		name string
+		yaml string
+		want bool
+	}{
+		{name: "unset", yaml: "address: localhost:8500", want: true},
+		{name: "healthCheck false", yaml: "healthCheck: false", want: false},
+		{name: "healthCheck true", yaml: "healthCheck: true", want: true},
+		{name: "disabled", yaml: "disableHealthCheck: true", want: false},
+		{name: "disabled wins", yaml: "healthCheck: true\ndisableHealthCheck: true", want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var cfg ConsulConfig
+			if err := yaml.Unmarshal([]byte(tt.yaml), &cfg); err != nil {
+				t.Fatal(err)
+			}
+			cfg.SetDefaults()
+			if got := cfg.IsHealthCheckEnabled(); got != tt.want {
+				t.Errorf("IsHealthCheckEnabled() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,44 @@
+package srpc
+
+import (
+	"strings"
+
+	"github.com/hashicorp/consul/api"
+	consul "github.com/kitex-contrib/registry-consul"
+)
+
+// consulAPIConfig returns the Consul client configuration for cfg.
+func consulAPIConfig(cfg ConsulConfig) *api.Config {
+	config := api.DefaultConfig()
+	config.Address = cfg.Address
+	config.Token = cfg.Token
+	config.Datacenter = cfg.Datacenter
+	return config
+}
+
+// consulRegistryOptions returns the registry options for cfg.
+// The registry fills in the TCP target of the check at registration time.
+func consulRegistryOptions(cfg ConsulConfig) []consul.Option {
+	if !cfg.IsHealthCheckEnabled() {
+		return []consul.Option{consul.WithCheck(nil)}
+	}
+	return []consul.Option{consul.WithCheck(&api.AgentServiceCheck{
+		Interval:                       cfg.Interval.String(),
+		Timeout:                        cfg.CheckTimeout.String(),
+		DeregisterCriticalServiceAfter: cfg.DeregisterAfter.String(),
+	})}
+}
+
+// consulTags converts "key:value" tags into the registry tag map.
+// The Consul registry joins the map back into "key:value" tags.
+func consulTags(tags []string) map[string]string {
+	if len(tags) == 0 {
+		return nil
+	}
+	m := make(map[string]string, len(tags))
+	for _, tag := range tags {
+		key, value, _ := strings.Cut(tag, ":")
+		m[key] = value
+	}
+	return m
+}
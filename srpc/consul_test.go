@@ -0,0 +1,106 @@
+package srpc
+
+import (
+	"encoding/json"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/cloudwego/kitex/pkg/registry"
+	"github.com/hashicorp/consul/api"
+	consul "github.com/kitex-contrib/registry-consul"
+)
+
+// fakeConsulAgent records the service registrations sent to it.
+func fakeConsulAgent(t *testing.T) (string, <-chan api.AgentServiceRegistration) {
+	t.Helper()
+	regs := make(chan api.AgentServiceRegistration, 1)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v1/agent/service/register" {
+			http.NotFound(w, r)
+			return
+		}
+		var reg api.AgentServiceRegistration
+		if err := json.NewDecoder(r.Body).Decode(&reg); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		regs <- reg
+	}))
+	t.Cleanup(srv.Close)
+	return strings.TrimPrefix(srv.URL, "http://"), regs
+}
+
+func TestConsulRegistration(t *testing.T) {
+	disabled := false
+	tests := []struct {
+		name      string
+		cfg       ConsulConfig
+		wantCheck bool
+		wantTags  []string
+	}{
+		{
+			name:      "defaults",
+			cfg:       ConsulConfig{},
+			wantCheck: true,
+		},
+		{
+			name:      "custom interval and tags",
+			cfg:       ConsulConfig{Interval: 3 * time.Second, Tags: []string{"env:prod", "canary"}},
+			wantCheck: true,
+			wantTags:  []string{"canary:", "env:prod"},
+		},
+		{name: "healthCheck false", cfg: ConsulConfig{HealthCheck: &disabled}},
+		{name: "disabled", cfg: ConsulConfig{DisableHealthCheck: true}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			addr, regs := fakeConsulAgent(t)
+			cfg := tt.cfg
+			cfg.Address = addr
+			cfg.SetDefaults()
+
+			r, err := consul.NewConsulRegisterWithConfig(consulAPIConfig(cfg), consulRegistryOptions(cfg)...)
+			if err != nil {
+				t.Fatal(err)
+			}
+			info := &registry.Info{
+				ServiceName: "user",
+				Addr:        &net.TCPAddr{IP: net.IPv4(127, 0, 0, 1), Port: 8888},
+				Tags:        consulTags(cfg.Tags),
+			}
+			if err := r.Register(info); err != nil {
+				t.Fatal(err)
+			}
+			reg := <-regs
+
+			slices.Sort(reg.Tags)
+			if !slices.Equal(reg.Tags, tt.wantTags) {
+				t.Errorf("tags = %q, want %q", reg.Tags, tt.wantTags)
+			}
+			if !tt.wantCheck {
+				if reg.Check != nil {
+					t.Errorf("check = %+v, want none", reg.Check)
+				}
+				return
+			}
+			if reg.Check == nil {
+				t.Fatal("no health check registered")
+			}
+			if reg.Check.Interval != cfg.Interval.String() {
+				t.Errorf("check interval = %s, want %s", reg.Check.Interval, cfg.Interval)
+			}
+			if reg.Check.Timeout != cfg.CheckTimeout.String() {
+				t.Errorf("check timeout = %s, want %s", reg.Check.Timeout, cfg.CheckTimeout)
+			}
+			if reg.Check.TCP != "127.0.0.1:8888" {
+				t.Errorf("check target = %s, want 127.0.0.1:8888", reg.Check.TCP)
+			}
+		})
+	}
+}
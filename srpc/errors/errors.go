@@ -3,8 +3,11 @@
 package errors
 
 import (
+	"encoding/json"
 	"errors"
 	"fmt"
+	"maps"
+	"net/http"
 
 	"github.com/cloudwego/kitex/pkg/kerrors"
 )
@@ -45,45 +48,79 @@ const (
 	CodeCancelled = 15
 )
 
+// detailsExtraKey is the Kitex biz error extra key carrying JSON details.
+const detailsExtraKey = "goten-details"
+
 // Error represents an RPC error with code and message.
 type Error struct {
 	Code    int32
 	Message string
+	// Details carries structured information about the error, such as the
+	// invalid field. It is transmitted with the error across RPC calls.
+	Details map[string]any
 	cause   error
+	stack   []uintptr
 }
 
 // New creates a new Error with the given code and message.
 func New(code int32, message string) *Error {
-	return &Error{
+	e := &Error{
 		Code:    code,
 		Message: message,
 	}
+	return e.withStack()
 }
 
 // Newf creates a new Error with the given code and formatted message.
 func Newf(code int32, format string, args ...interface{}) *Error {
-	return &Error{
+	e := &Error{
 		Code:    code,
 		Message: fmt.Sprintf(format, args...),
 	}
+	return e.withStack()
 }
 
 // Wrap wraps an existing error with an RPC error code.
 func Wrap(err error, code int32, message string) *Error {
-	return &Error{
+	e := &Error{
 		Code:    code,
 		Message: message,
 		cause:   err,
 	}
+	return e.withStack()
 }
 
 // Wrapf wraps an existing error with an RPC error code and formatted message.
 func Wrapf(err error, code int32, format string, args ...interface{}) *Error {
-	return &Error{
+	e := &Error{
 		Code:    code,
 		Message: fmt.Sprintf(format, args...),
 		cause:   err,
 	}
+	return e.withStack()
+}
+
+// WithDetail returns a copy of the error with the detail added.
+// The receiver is left unchanged, so shared errors can be annotated safely.
+func (e *Error) WithDetail(key string, value any) *Error {
+	c := e.clone()
+	c.Details[key] = value
+	return c
+}
+
+// WithDetails returns a copy of the error with all details added.
+func (e *Error) WithDetails(details map[string]any) *Error {
+	c := e.clone()
+	maps.Copy(c.Details, details)
+	return c
+}
+
+// clone returns a copy of the error with its own details map.
+func (e *Error) clone() *Error {
+	c := *e
+	c.Details = make(map[string]any, len(e.Details)+1)
+	maps.Copy(c.Details, e.Details)
+	return &c
 }
 
 // Error implements the error interface.
@@ -164,8 +201,69 @@ func DeadlineExceeded(message string) *Error {
 	return New(CodeDeadlineExceeded, message)
 }
 
+// ResourceExhausted returns an error indicating resource exhausted.
+func ResourceExhausted(message string) *Error {
+	return New(CodeResourceExhausted, message)
+}
+
+// ResourceExhaustedf returns an error indicating resource exhausted with formatted message.
+func ResourceExhaustedf(format string, args ...interface{}) *Error {
+	return Newf(CodeResourceExhausted, format, args...)
+}
+
+// FailedPrecondition returns an error indicating a failed precondition.
+func FailedPrecondition(message string) *Error {
+	return New(CodeFailedPrecondition, message)
+}
+
+// FailedPreconditionf returns an error indicating a failed precondition with formatted message.
+func FailedPreconditionf(format string, args ...interface{}) *Error {
+	return Newf(CodeFailedPrecondition, format, args...)
+}
+
+// Aborted returns an error indicating the operation was aborted.
+func Aborted(message string) *Error {
+	return New(CodeAborted, message)
+}
+
+// Abortedf returns an error indicating the operation was aborted with formatted message.
+func Abortedf(format string, args ...interface{}) *Error {
+	return Newf(CodeAborted, format, args...)
+}
+
+// OutOfRange returns an error indicating out of range.
+func OutOfRange(message string) *Error {
+	return New(CodeOutOfRange, message)
+}
+
+// OutOfRangef returns an error indicating out of range with formatted message.
+func OutOfRangef(format string, args ...interface{}) *Error {
+	return Newf(CodeOutOfRange, format, args...)
+}
+
+// Unimplemented returns an error indicating the operation is not implemented.
+func Unimplemented(message string) *Error {
+	return New(CodeUnimplemented, message)
+}
+
+// Unimplementedf returns an error indicating the operation is not implemented with formatted message.
+func Unimplementedf(format string, args ...interface{}) *Error {
+	return Newf(CodeUnimplemented, format, args...)
+}
+
+// Cancelled returns an error indicating the operation was cancelled.
+func Cancelled(message string) *Error {
+	return New(CodeCancelled, message)
+}
+
+// Cancelledf returns an error indicating the operation was cancelled with formatted message.
+func Cancelledf(format string, args ...interface{}) *Error {
+	return Newf(CodeCancelled, format, args...)
+}
+
 // FromError extracts an Error from an error.
-// If the error is not an Error, it returns nil.
+// Kitex biz status errors received by clients are converted with
+// FromKitexError. For any other error, it returns nil.
 func FromError(err error) *Error {
 	if err == nil {
 		return nil
@@ -174,7 +272,7 @@ func FromError(err error) *Error {
 	if errors.As(err, &e) {
 		return e
 	}
-	return nil
+	return FromKitexError(err)
 }
 
 // Code extracts the error code from an error.
@@ -219,11 +317,177 @@ func IsInternal(err error) bool {
 	return IsCode(err, CodeInternal)
 }
 
+// IsAlreadyExists checks if the error indicates resource already exists.
+func IsAlreadyExists(err error) bool {
+	return IsCode(err, CodeAlreadyExists)
+}
+
+// IsUnavailable checks if the error indicates service unavailable.
+func IsUnavailable(err error) bool {
+	return IsCode(err, CodeUnavailable)
+}
+
+// IsDeadlineExceeded checks if the error indicates deadline exceeded.
+func IsDeadlineExceeded(err error) bool {
+	return IsCode(err, CodeDeadlineExceeded)
+}
+
+// IsResourceExhausted checks if the error indicates resource exhausted.
+func IsResourceExhausted(err error) bool {
+	return IsCode(err, CodeResourceExhausted)
+}
+
+// IsFailedPrecondition checks if the error indicates a failed precondition.
+func IsFailedPrecondition(err error) bool {
+	return IsCode(err, CodeFailedPrecondition)
+}
+
+// IsAborted checks if the error indicates the operation was aborted.
+func IsAborted(err error) bool {
+	return IsCode(err, CodeAborted)
+}
+
+// IsOutOfRange checks if the error indicates out of range.
+func IsOutOfRange(err error) bool {
+	return IsCode(err, CodeOutOfRange)
+}
+
+// IsUnimplemented checks if the error indicates the operation is not implemented.
+func IsUnimplemented(err error) bool {
+	return IsCode(err, CodeUnimplemented)
+}
+
+// IsCancelled checks if the error indicates the operation was cancelled.
+func IsCancelled(err error) bool {
+	return IsCode(err, CodeCancelled)
+}
+
+// HTTPStatus returns the HTTP status code corresponding to the error code.
+func (e *Error) HTTPStatus() int {
+	if e == nil {
+		return http.StatusOK
+	}
+	return codeToHTTPStatus(e.Code)
+}
+
+// HTTPStatus returns the HTTP status code for an error.
+// It returns 200 for nil and 500 for errors that carry no code.
+func HTTPStatus(err error) int {
+	if err == nil {
+		return http.StatusOK
+	}
+	if e := FromError(err); e != nil {
+		return e.HTTPStatus()
+	}
+	return http.StatusInternalServerError
+}
+
+// codeToHTTPStatus maps an error code to an HTTP status code, following the
+// mapping used by gRPC gateways.
+func codeToHTTPStatus(code int32) int {
+	switch code {
+	case CodeOK:
+		return http.StatusOK
+	case CodeInvalidArgument, CodeOutOfRange, CodeFailedPrecondition:
+		return http.StatusBadRequest
+	case CodeNotFound:
+		return http.StatusNotFound
+	case CodeAlreadyExists, CodeAborted:
+		return http.StatusConflict
+	case CodePermissionDenied:
+		return http.StatusForbidden
+	case CodeUnauthenticated:
+		return http.StatusUnauthorized
+	case CodeResourceExhausted:
+		return http.StatusTooManyRequests
+	case CodeUnimplemented:
+		return http.StatusNotImplemented
+	case CodeUnavailable:
+		return http.StatusServiceUnavailable
+	case CodeDeadlineExceeded:
+		return http.StatusGatewayTimeout
+	case CodeCancelled:
+		// 499 Client Closed Request, as used by gRPC gateways.
+		return 499
+	default:
+		// CodeUnknown, CodeInternal and unknown codes
+		return http.StatusInternalServerError
+	}
+}
+
+// GRPCCode returns the gRPC status code corresponding to the error code.
+func (e *Error) GRPCCode() uint32 {
+	if e == nil {
+		return 0
+	}
+	return codeToGRPCCode(e.Code)
+}
+
+// GRPCCode returns the gRPC status code for an error.
+// It returns OK (0) for nil and Unknown (2) for errors that carry no code.
+func GRPCCode(err error) uint32 {
+	if err == nil {
+		return 0
+	}
+	if e := FromError(err); e != nil {
+		return e.GRPCCode()
+	}
+	return 2
+}
+
+// codeToGRPCCode maps an error code to the gRPC status code of the same
+// name, which is numbered differently.
+func codeToGRPCCode(code int32) uint32 {
+	switch code {
+	case CodeOK:
+		return 0
+	case CodeCancelled:
+		return 1
+	case CodeInvalidArgument:
+		return 3
+	case CodeDeadlineExceeded:
+		return 4
+	case CodeNotFound:
+		return 5
+	case CodeAlreadyExists:
+		return 6
+	case CodePermissionDenied:
+		return 7
+	case CodeResourceExhausted:
+		return 8
+	case CodeFailedPrecondition:
+		return 9
+	case CodeAborted:
+		return 10
+	case CodeOutOfRange:
+		return 11
+	case CodeUnimplemented:
+		return 12
+	case CodeInternal:
+		return 13
+	case CodeUnavailable:
+		return 14
+	case CodeUnauthenticated:
+		return 16
+	default:
+		// CodeUnknown and unknown codes
+		return 2
+	}
+}
+
 // ToKitexError converts an Error to a Kitex error.
+// Details are serialized as JSON into the biz error's extra info.
 func ToKitexError(err *Error) error {
 	if err == nil {
 		return nil
 	}
+	if len(err.Details) > 0 {
+		if data, jsonErr := json.Marshal(err.Details); jsonErr == nil {
+			return kerrors.NewBizStatusErrorWithExtra(err.Code, err.Message, map[string]string{
+				detailsExtraKey: string(data),
+			})
+		}
+	}
 	return kerrors.NewBizStatusError(err.Code, err.Message)
 }
 
@@ -234,7 +498,14 @@ func FromKitexError(err error) *Error {
 	}
 	var bizErr kerrors.BizStatusErrorIface
 	if errors.As(err, &bizErr) {
-		return New(bizErr.BizStatusCode(), bizErr.BizMessage())
+		e := New(bizErr.BizStatusCode(), bizErr.BizMessage())
+		if data, ok := bizErr.BizExtra()[detailsExtraKey]; ok {
+			var details map[string]any
+			if json.Unmarshal([]byte(data), &details) == nil {
+				e.Details = details
+			}
+		}
+		return e
 	}
 	return nil
 }
@@ -0,0 +1,143 @@
+package errors
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"reflect"
+	"strconv"
+	"testing"
+
+	"github.com/cloudwego/kitex/pkg/kerrors"
+)
+
+func TestKitexErrorDetailsRoundTrip(t *testing.T) {
+	tests := []struct {
+		name    string
+		err     *Error
+		want    map[string]any
+		wantRaw bool
+	}{
+		{name: "no details", err: NotFound("user not found")},
+		{
+			name: "string detail",
+			err:  InvalidArgument("bad email").WithDetail("field", "email"),
+			want: map[string]any{"field": "email"},
+		},
+		{
+			// JSON numbers decode as float64
+			name: "mixed details",
+			err: ResourceExhausted("quota").WithDetails(map[string]any{
+				"limit":  100,
+				"scopes": []any{"read", "write"},
+				"retry":  map[string]any{"after_ms": 250},
+			}),
+			want: map[string]any{
+				"limit":  float64(100),
+				"scopes": []any{"read", "write"},
+				"retry":  map[string]any{"after_ms": float64(250)},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			kerr := ToKitexError(tt.err)
+			var bizErr kerrors.BizStatusErrorIface
+			if !errors.As(kerr, &bizErr) {
+				t.Fatalf("ToKitexError() = %T, want a biz status error", kerr)
+			}
+
+			got := FromError(kerr)
+			if got == nil {
+				t.Fatal("FromError() = nil")
+			}
+			if got.Code != tt.err.Code || got.Message != tt.err.Message {
+				t.Errorf("FromError() = %d %q, want %d %q", got.Code, got.Message, tt.err.Code, tt.err.Message)
+			}
+			if len(tt.want) == 0 && len(got.Details) == 0 {
+				return
+			}
+			if !reflect.DeepEqual(got.Details, tt.want) {
+				t.Errorf("Details = %#v, want %#v", got.Details, tt.want)
+			}
+		})
+	}
+}
+
+func TestWithDetailLeavesReceiverUnchanged(t *testing.T) {
+	base := NotFound("missing")
+	annotated := base.WithDetail("id", "42").WithDetails(map[string]any{"kind": "user"})
+
+	if len(base.Details) != 0 {
+		t.Errorf("base details = %v, want none", base.Details)
+	}
+	want := map[string]any{"id": "42", "kind": "user"}
+	if !reflect.DeepEqual(annotated.Details, want) {
+		t.Errorf("annotated details = %v, want %v", annotated.Details, want)
+	}
+}
+
+func TestHTTPStatus(t *testing.T) {
+	tests := []struct {
+		code int32
+		want int
+	}{
+		{CodeOK, http.StatusOK},
+		{CodeUnknown, http.StatusInternalServerError},
+		{CodeInvalidArgument, http.StatusBadRequest},
+		{CodeNotFound, http.StatusNotFound},
+		{CodeAlreadyExists, http.StatusConflict},
+		{CodePermissionDenied, http.StatusForbidden},
+		{CodeUnauthenticated, http.StatusUnauthorized},
+		{CodeResourceExhausted, http.StatusTooManyRequests},
+		{CodeFailedPrecondition, http.StatusBadRequest},
+		{CodeAborted, http.StatusConflict},
+		{CodeOutOfRange, http.StatusBadRequest},
+		{CodeUnimplemented, http.StatusNotImplemented},
+		{CodeInternal, http.StatusInternalServerError},
+		{CodeUnavailable, http.StatusServiceUnavailable},
+		{CodeDeadlineExceeded, http.StatusGatewayTimeout},
+		{CodeCancelled, 499},
+		{1000, http.StatusInternalServerError},
+	}
+
+	for _, tt := range tests {
+		t.Run(strconv.Itoa(int(tt.code)), func(t *testing.T) {
+			err := New(tt.code, "message")
+			if got := err.HTTPStatus(); got != tt.want {
+				t.Errorf("(*Error).HTTPStatus() = %d, want %d", got, tt.want)
+			}
+			if got := HTTPStatus(fmt.Errorf("handler: %w", err)); got != tt.want {
+				t.Errorf("HTTPStatus(wrapped) = %d, want %d", got, tt.want)
+			}
+			if got := HTTPStatus(ToKitexError(err)); got != tt.want {
+				t.Errorf("HTTPStatus(kitex) = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestHTTPStatusWithoutCode(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want int
+	}{
+		{name: "nil", err: nil, want: http.StatusOK},
+		{name: "plain error", err: errors.New("boom"), want: http.StatusInternalServerError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := HTTPStatus(tt.err); got != tt.want {
+				t.Errorf("HTTPStatus() = %d, want %d", got, tt.want)
+			}
+		})
+	}
+
+	var nilErr *Error
+	if got := nilErr.HTTPStatus(); got != http.StatusOK {
+		t.Errorf("(*Error)(nil).HTTPStatus() = %d, want 200", got)
+	}
+}
@@ -0,0 +1,89 @@
+package errors
+
+import (
+	"fmt"
+	"runtime"
+	"strings"
+	"sync/atomic"
+)
+
+// maxStackDepth bounds the number of frames recorded per error.
+const maxStackDepth = 32
+
+// pkgPrefix identifies the frames of this package, which are dropped from
+// recorded stacks so that they start at the caller.
+const pkgPrefix = "github.com/ssgohq/goten-core/srpc/errors."
+
+var captureStacks atomic.Bool
+
+// SetStackCapture enables or disables stack capture for every Error created
+// by this package. It is disabled by default to keep constructors cheap;
+// NewWithStack and WrapWithStack capture regardless of this setting.
+func SetStackCapture(enabled bool) {
+	captureStacks.Store(enabled)
+}
+
+// NewWithStack is like New but records the call stack.
+func NewWithStack(code int32, message string) *Error {
+	e := New(code, message)
+	e.stack = callers()
+	return e
+}
+
+// WrapWithStack is like Wrap but records the call stack.
+func WrapWithStack(err error, code int32, message string) *Error {
+	e := Wrap(err, code, message)
+	e.stack = callers()
+	return e
+}
+
+// StackTrace returns the program counters of the stack recorded when the
+// error was created, or nil if none was recorded.
+func (e *Error) StackTrace() []uintptr {
+	return e.stack
+}
+
+// Stack returns the recorded stack formatted one frame per line, or "" if
+// none was recorded.
+func (e *Error) Stack() string {
+	if len(e.stack) == 0 {
+		return ""
+	}
+	var b strings.Builder
+	frames := runtime.CallersFrames(e.stack)
+	for {
+		frame, more := frames.Next()
+		fmt.Fprintf(&b, "%s\n\t%s:%d\n", frame.Function, frame.File, frame.Line)
+		if !more {
+			break
+		}
+	}
+	return b.String()
+}
+
+// withStack records the call stack if global stack capture is enabled.
+func (e *Error) withStack() *Error {
+	if captureStacks.Load() {
+		e.stack = callers()
+	}
+	return e
+}
+
+// callers returns the current stack, starting at the first frame outside
+// this package.
+func callers() []uintptr {
+	pcs := make([]uintptr, maxStackDepth)
+	n := runtime.Callers(2, pcs)
+	pcs = pcs[:n]
+
+	frames := runtime.CallersFrames(pcs)
+	skip := 0
+	for {
+		frame, more := frames.Next()
+		if !strings.HasPrefix(frame.Function, pkgPrefix) || !more {
+			break
+		}
+		skip++
+	}
+	return pcs[skip:]
+}
@@ -0,0 +1,355 @@
+package srpc
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/cloudwego/kitex/pkg/discovery"
+	"github.com/cloudwego/kitex/pkg/registry"
+	"github.com/cloudwego/kitex/pkg/rpcinfo"
+	clientv3 "go.etcd.io/etcd/client/v3"
+
+	"github.com/ssgohq/goten-core/logx"
+)
+
+const (
+	// etcdPrefixTpl matches the key layout used by kitex-contrib/registry-etcd,
+	// so instances are interoperable with its registry and resolver.
+	etcdPrefixTpl = "kitex/registry-etcd/%s/"
+	// etcdTTL is the lease TTL in seconds for registered instances.
+	etcdTTL = 60
+	// etcdDialTimeout bounds the initial connection to the etcd cluster.
+	etcdDialTimeout = 5 * time.Second
+	// etcdRetryMin and etcdRetryMax bound the backoff between attempts to
+	// register an instance again after its lease was lost.
+	etcdRetryMin = time.Second
+	etcdRetryMax = 30 * time.Second
+)
+
+// etcdInstance is the value stored for each registered instance.
+type etcdInstance struct {
+	Network string            `json:"network"`
+	Address string            `json:"address"`
+	Weight  int               `json:"weight"`
+	Tags    map[string]string `json:"tags"`
+}
+
+// etcdRegistry implements registry.Registry on top of etcd leases. Its
+// client is closed once the last instance is deregistered and opened again
+// by the next Register, so a stopped server holds no etcd connection.
+type etcdRegistry struct {
+	cfg    EtcdConfig
+	mu     sync.Mutex
+	client *clientv3.Client
+	leases map[string]*etcdLease
+}
+
+// etcdLease tracks the lease of one registered instance. id changes when the
+// lease is lost and the instance is registered again under a new one.
+type etcdLease struct {
+	id     atomic.Int64
+	cancel context.CancelFunc
+	done   chan struct{}
+}
+
+// newEtcdClient connects to the etcd cluster of cfg.
+func newEtcdClient(cfg EtcdConfig) (*clientv3.Client, error) {
+	return clientv3.New(clientv3.Config{
+		Endpoints:   cfg.Hosts,
+		Username:    cfg.Username,
+		Password:    cfg.Password,
+		DialTimeout: etcdDialTimeout,
+	})
+}
+
+// newEtcdRegistry creates an etcd-backed service registry.
+func newEtcdRegistry(cfg EtcdConfig) (*etcdRegistry, error) {
+	client, err := newEtcdClient(cfg)
+	if err != nil {
+		return nil, err
+	}
+	return &etcdRegistry{
+		cfg:    cfg,
+		client: client,
+		leases: make(map[string]*etcdLease),
+	}, nil
+}
+
+// Register puts the instance under a TTL lease and keeps the lease alive
+// until Deregister is called. If the lease is lost, for example after a
+// network partition longer than the TTL, the instance is registered again.
+func (r *etcdRegistry) Register(info *registry.Info) error {
+	key, value, err := etcdEntry(info)
+	if err != nil {
+		return err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.client == nil {
+		if r.client, err = newEtcdClient(r.cfg); err != nil {
+			return fmt.Errorf("etcd connect: %w", err)
+		}
+	}
+
+	if prev, ok := r.leases[key]; ok {
+		prev.cancel()
+		<-prev.done
+		delete(r.leases, key)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	id, ch, err := etcdPutWithLease(ctx, r.client, key, value)
+	if err != nil {
+		cancel()
+		return err
+	}
+	lease := &etcdLease{cancel: cancel, done: make(chan struct{})}
+	lease.id.Store(int64(id))
+	r.leases[key] = lease
+	go keepEtcdLease(ctx, r.client, key, value, lease, ch)
+
+	logx.Infow("Service registered in etcd", "key", key)
+	return nil
+}
+
+// Deregister removes the instance and revokes its lease. The client is
+// closed when no registered instance remains.
+func (r *etcdRegistry) Deregister(info *registry.Info) error {
+	key, _, err := etcdEntry(info)
+	if err != nil {
+		return err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.client == nil {
+		return nil
+	}
+	lease, ok := r.leases[key]
+	delete(r.leases, key)
+	defer func() {
+		if len(r.leases) == 0 {
+			if err := r.client.Close(); err != nil {
+				logx.Warnw("Failed to close etcd client", "error", err)
+			}
+			r.client = nil
+		}
+	}()
+	if ok {
+		// Stop the keepalive first so the instance is not registered again
+		// once its key is deleted.
+		lease.cancel()
+		<-lease.done
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), etcdDialTimeout)
+	defer cancel()
+
+	if _, err := r.client.Delete(ctx, key); err != nil {
+		return fmt.Errorf("etcd delete %s: %w", key, err)
+	}
+	if ok {
+		if _, err := r.client.Revoke(ctx, clientv3.LeaseID(lease.id.Load())); err != nil {
+			logx.Warnw("Failed to revoke etcd lease", "key", key, "error", err)
+		}
+	}
+
+	logx.Infow("Service deregistered from etcd", "key", key)
+	return nil
+}
+
+// etcdPutWithLease grants a TTL lease, puts key under it and keeps the lease
+// alive until ctx is cancelled.
+func etcdPutWithLease(
+	ctx context.Context, client *clientv3.Client, key, value string,
+) (clientv3.LeaseID, <-chan *clientv3.LeaseKeepAliveResponse, error) {
+	opCtx, cancel := context.WithTimeout(ctx, etcdDialTimeout)
+	defer cancel()
+
+	lease, err := client.Grant(opCtx, etcdTTL)
+	if err != nil {
+		return 0, nil, fmt.Errorf("etcd grant lease: %w", err)
+	}
+	if _, err := client.Put(opCtx, key, value, clientv3.WithLease(lease.ID)); err != nil {
+		return 0, nil, fmt.Errorf("etcd put %s: %w", key, err)
+	}
+	ch, err := client.KeepAlive(ctx, lease.ID)
+	if err != nil {
+		return 0, nil, fmt.Errorf("etcd keepalive: %w", err)
+	}
+	return lease.ID, ch, nil
+}
+
+// keepEtcdLease drains the keepalive responses of a lease. The channel closes
+// when ctx is cancelled or the lease is lost; in the latter case the key is
+// put again under a new lease, retrying with exponential backoff.
+func keepEtcdLease(
+	ctx context.Context, client *clientv3.Client, key, value string,
+	lease *etcdLease, ch <-chan *clientv3.LeaseKeepAliveResponse,
+) {
+	defer close(lease.done)
+
+	delay := etcdRetryMin
+	for {
+		alive := false
+		for range ch {
+			alive = true
+		}
+		if ctx.Err() != nil {
+			return
+		}
+		// A lease lost without a single keepalive is likely to be lost
+		// again, so back off instead of spinning against etcd.
+		if alive {
+			delay = etcdRetryMin
+		} else if !sleepCtx(ctx, &delay) {
+			return
+		}
+		logx.Warnw("Lost etcd lease, registering the instance again", "key", key)
+
+		for {
+			id, next, err := etcdPutWithLease(ctx, client, key, value)
+			if err == nil {
+				lease.id.Store(int64(id))
+				ch = next
+				break
+			}
+			logx.Warnw("Failed to register the instance in etcd again", "key", key, "retry", delay, "error", err)
+			if !sleepCtx(ctx, &delay) {
+				return
+			}
+		}
+	}
+}
+
+// sleepCtx waits for *delay and doubles it up to etcdRetryMax. It returns
+// false if ctx is done first.
+func sleepCtx(ctx context.Context, delay *time.Duration) bool {
+	timer := time.NewTimer(*delay)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return false
+	case <-timer.C:
+		*delay = min(*delay*2, etcdRetryMax)
+		return true
+	}
+}
+
+// etcdResolver implements discovery.Resolver over the instances stored by
+// etcdRegistry or kitex-contrib/registry-etcd. Kitex caches the result and
+// resolves again periodically, so new and removed instances are picked up.
+type etcdResolver struct {
+	client *clientv3.Client
+}
+
+// newEtcdResolver creates an etcd-backed service resolver. Its client lives
+// as long as the Kitex client using it.
+func newEtcdResolver(cfg EtcdConfig) (*etcdResolver, error) {
+	client, err := newEtcdClient(cfg)
+	if err != nil {
+		return nil, err
+	}
+	return &etcdResolver{client: client}, nil
+}
+
+// Target returns the service name, which is the resolve key.
+func (r *etcdResolver) Target(_ context.Context, target rpcinfo.EndpointInfo) string {
+	return target.ServiceName()
+}
+
+// Resolve lists the registered instances of a service.
+func (r *etcdResolver) Resolve(ctx context.Context, desc string) (discovery.Result, error) {
+	prefix := fmt.Sprintf(etcdPrefixTpl, desc)
+	resp, err := r.client.Get(ctx, prefix, clientv3.WithPrefix())
+	if err != nil {
+		return discovery.Result{}, fmt.Errorf("etcd get %s: %w", prefix, err)
+	}
+
+	instances := make([]discovery.Instance, 0, len(resp.Kvs))
+	for _, kv := range resp.Kvs {
+		var ins etcdInstance
+		if err := json.Unmarshal(kv.Value, &ins); err != nil {
+			logx.Warnw("Skipping invalid etcd instance", "key", string(kv.Key), "error", err)
+			continue
+		}
+		instances = append(instances, discovery.NewInstance(ins.Network, ins.Address, ins.Weight, ins.Tags))
+	}
+	if len(instances) == 0 {
+		return discovery.Result{}, fmt.Errorf("etcd: no instance registered for service %q", desc)
+	}
+	return discovery.Result{
+		Cacheable: true,
+		CacheKey:  desc,
+		Instances: instances,
+	}, nil
+}
+
+// Diff computes the instance changes between two results.
+func (r *etcdResolver) Diff(cacheKey string, prev, next discovery.Result) (discovery.Change, bool) {
+	return discovery.DefaultDiff(cacheKey, prev, next)
+}
+
+// Name returns the resolver name.
+func (r *etcdResolver) Name() string {
+	return "etcd"
+}
+
+// etcdEntry returns the etcd key and JSON value for a registry entry.
+func etcdEntry(info *registry.Info) (string, string, error) {
+	if info == nil || info.ServiceName == "" {
+		return "", "", errors.New("etcd registry: missing service name")
+	}
+	if info.Addr == nil {
+		return "", "", errors.New("etcd registry: missing service address")
+	}
+
+	addr, err := registrationAddr(info.Addr)
+	if err != nil {
+		return "", "", err
+	}
+
+	value, err := json.Marshal(etcdInstance{
+		Network: info.Addr.Network(),
+		Address: addr,
+		Weight:  info.Weight,
+		Tags:    info.Tags,
+	})
+	if err != nil {
+		return "", "", err
+	}
+
+	return fmt.Sprintf(etcdPrefixTpl, info.ServiceName) + addr, string(value), nil
+}
+
+// registrationAddr replaces an unspecified listen host with the first
+// non-loopback IPv4 address so that peers can reach the instance.
+func registrationAddr(addr net.Addr) (string, error) {
+	host, port, err := net.SplitHostPort(addr.String())
+	if err != nil {
+		return "", err
+	}
+	if ip := net.ParseIP(host); host != "" && (ip == nil || !ip.IsUnspecified()) {
+		return addr.String(), nil
+	}
+
+	addrs, err := net.InterfaceAddrs()
+	if err != nil {
+		return "", err
+	}
+	for _, a := range addrs {
+		if ipNet, ok := a.(*net.IPNet); ok && !ipNet.IP.IsLoopback() {
+			if ipv4 := ipNet.IP.To4(); ipv4 != nil {
+				return net.JoinHostPort(ipv4.String(), port), nil
+			}
+		}
+	}
+	return "", errors.New("etcd registry: no non-loopback IPv4 address found")
+}
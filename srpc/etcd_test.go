@@ -0,0 +1,229 @@
+package srpc
+
+import (
+	"context"
+	"net"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/cloudwego/kitex/pkg/registry"
+	"go.etcd.io/etcd/api/v3/mvccpb"
+	clientv3 "go.etcd.io/etcd/client/v3"
+)
+
+// fakeEtcd is an in-memory stand-in for the etcd KV and Lease APIs used by
+// the registry and resolver.
+type fakeEtcd struct {
+	clientv3.KV
+	clientv3.Lease
+
+	mu      sync.Mutex
+	data    map[string]string
+	leases  map[clientv3.LeaseID]bool
+	expired map[clientv3.LeaseID]chan struct{}
+	nextID  clientv3.LeaseID
+	closed  bool
+}
+
+func newFakeEtcdClient() (*clientv3.Client, *fakeEtcd) {
+	fake := &fakeEtcd{
+		data:    map[string]string{},
+		leases:  map[clientv3.LeaseID]bool{},
+		expired: map[clientv3.LeaseID]chan struct{}{},
+	}
+	client := clientv3.NewCtxClient(context.Background())
+	client.KV = fake
+	client.Lease = fake
+	return client, fake
+}
+
+func (f *fakeEtcd) Put(_ context.Context, key, val string, _ ...clientv3.OpOption) (*clientv3.PutResponse, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.data[key] = val
+	return &clientv3.PutResponse{}, nil
+}
+
+func (f *fakeEtcd) Get(_ context.Context, key string, _ ...clientv3.OpOption) (*clientv3.GetResponse, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	resp := &clientv3.GetResponse{}
+	for k, v := range f.data {
+		if strings.HasPrefix(k, key) {
+			resp.Kvs = append(resp.Kvs, &mvccpb.KeyValue{Key: []byte(k), Value: []byte(v)})
+		}
+	}
+	return resp, nil
+}
+
+func (f *fakeEtcd) Delete(_ context.Context, key string, _ ...clientv3.OpOption) (*clientv3.DeleteResponse, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	delete(f.data, key)
+	return &clientv3.DeleteResponse{}, nil
+}
+
+func (f *fakeEtcd) Grant(_ context.Context, _ int64) (*clientv3.LeaseGrantResponse, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.nextID++
+	f.leases[f.nextID] = true
+	f.expired[f.nextID] = make(chan struct{})
+	return &clientv3.LeaseGrantResponse{ID: f.nextID}, nil
+}
+
+func (f *fakeEtcd) KeepAlive(
+	ctx context.Context, id clientv3.LeaseID,
+) (<-chan *clientv3.LeaseKeepAliveResponse, error) {
+	f.mu.Lock()
+	expired := f.expired[id]
+	f.mu.Unlock()
+
+	ch := make(chan *clientv3.LeaseKeepAliveResponse, 1)
+	ch <- &clientv3.LeaseKeepAliveResponse{ID: id, TTL: etcdTTL}
+	go func() {
+		select {
+		case <-ctx.Done():
+		case <-expired:
+		}
+		close(ch)
+	}()
+	return ch, nil
+}
+
+// expire simulates a lease that ran out, e.g. during a network partition:
+// the lease and key are dropped and the keepalive channel closes.
+func (f *fakeEtcd) expire(id clientv3.LeaseID, key string) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	delete(f.leases, id)
+	delete(f.data, key)
+	close(f.expired[id])
+}
+
+func (f *fakeEtcd) has(key string) bool {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	_, ok := f.data[key]
+	return ok
+}
+
+func (f *fakeEtcd) Revoke(_ context.Context, id clientv3.LeaseID) (*clientv3.LeaseRevokeResponse, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	delete(f.leases, id)
+	return &clientv3.LeaseRevokeResponse{}, nil
+}
+
+func (f *fakeEtcd) Close() error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.closed = true
+	return nil
+}
+
+func tcpAddr(t *testing.T, s string) net.Addr {
+	t.Helper()
+	addr, err := net.ResolveTCPAddr("tcp", s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return addr
+}
+
+func TestEtcdRegistryAndResolver(t *testing.T) {
+	client, fake := newFakeEtcdClient()
+	reg := &etcdRegistry{client: client, leases: map[string]*etcdLease{}}
+	res := &etcdResolver{client: client}
+	ctx := context.Background()
+
+	instances := []*registry.Info{
+		{ServiceName: "user", Addr: tcpAddr(t, "10.0.0.1:8888"), Weight: 10, Tags: map[string]string{"zone": "a"}},
+		{ServiceName: "user", Addr: tcpAddr(t, "10.0.0.2:8888"), Weight: 20},
+		{ServiceName: "order", Addr: tcpAddr(t, "10.0.0.3:8888"), Weight: 10},
+	}
+	for _, info := range instances {
+		if err := reg.Register(info); err != nil {
+			t.Fatalf("Register(%s): %v", info.Addr, err)
+		}
+	}
+	if _, ok := fake.data["kitex/registry-etcd/user/10.0.0.1:8888"]; !ok {
+		t.Fatalf("instance not stored under the registry-etcd key layout: %v", fake.data)
+	}
+
+	result, err := res.Resolve(ctx, "user")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var addrs []string
+	for _, ins := range result.Instances {
+		addrs = append(addrs, ins.Address().String())
+	}
+	sort.Strings(addrs)
+	if got := strings.Join(addrs, ","); got != "10.0.0.1:8888,10.0.0.2:8888" {
+		t.Fatalf("resolved %s, want both user instances", got)
+	}
+	for _, ins := range result.Instances {
+		if ins.Address().String() == "10.0.0.1:8888" {
+			if zone, _ := ins.Tag("zone"); zone != "a" || ins.Weight() != 10 {
+				t.Fatalf("instance tags/weight not kept: zone=%q weight=%d", zone, ins.Weight())
+			}
+		}
+	}
+
+	// Deregistering every instance revokes the leases and closes the client
+	for _, info := range instances {
+		if err := reg.Deregister(info); err != nil {
+			t.Fatalf("Deregister(%s): %v", info.Addr, err)
+		}
+	}
+	if len(fake.data) != 0 || len(fake.leases) != 0 {
+		t.Fatalf("left behind keys %v and leases %v", fake.data, fake.leases)
+	}
+	if !fake.closed || reg.client != nil {
+		t.Fatal("etcd client not closed after the last instance was deregistered")
+	}
+	if err := reg.Deregister(instances[0]); err != nil {
+		t.Fatalf("Deregister after close: %v", err)
+	}
+}
+
+func TestEtcdRegistryLostLease(t *testing.T) {
+	client, fake := newFakeEtcdClient()
+	reg := &etcdRegistry{client: client, leases: map[string]*etcdLease{}}
+	info := &registry.Info{ServiceName: "user", Addr: tcpAddr(t, "10.0.0.1:8888")}
+	const key = "kitex/registry-etcd/user/10.0.0.1:8888"
+
+	if err := reg.Register(info); err != nil {
+		t.Fatal(err)
+	}
+	lease := reg.leases[key]
+	first := clientv3.LeaseID(lease.id.Load())
+	fake.expire(first, key)
+
+	deadline := time.Now().Add(5 * time.Second)
+	for !fake.has(key) || clientv3.LeaseID(lease.id.Load()) == first {
+		if time.Now().After(deadline) {
+			t.Fatal("instance not registered again after its lease was lost")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	if err := reg.Deregister(info); err != nil {
+		t.Fatal(err)
+	}
+	if fake.has(key) || len(fake.leases) != 0 {
+		t.Fatalf("left behind keys %v and leases %v", fake.data, fake.leases)
+	}
+}
+
+func TestEtcdResolverNoInstances(t *testing.T) {
+	client, _ := newFakeEtcdClient()
+	res := &etcdResolver{client: client}
+	if _, err := res.Resolve(context.Background(), "missing"); err == nil {
+		t.Fatal("Resolve of an unregistered service succeeded")
+	}
+}
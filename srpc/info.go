@@ -0,0 +1,42 @@
+package srpc
+
+import (
+	"strings"
+	"sync"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/ssgohq/goten-core/metric"
+)
+
+var (
+	clientInfoOnce sync.Once
+	clientInfo     *metric.GaugeVec
+)
+
+func initClientInfo() {
+	clientInfoOnce.Do(func() {
+		clientInfo = metric.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: "goten",
+			Subsystem: "rpc_client",
+			Name:      "info",
+			Help:      "Routing configuration of each RPC client, always 1",
+		}, []string{"service", "discovery", "load_balancer", "target"})
+	})
+}
+
+// recordClientInfo publishes the discovery type, load balancer and target of
+// a created client, so routing misconfiguration shows up in metrics.
+func recordClientInfo(cfg *ClientConfig) {
+	initClientInfo()
+	clientInfo.Set(1, cfg.ServiceName, cfg.Discovery.Type, cfg.LoadBalancer, clientTarget(cfg))
+}
+
+// clientTarget describes where a client sends requests: the endpoint list
+// when endpoints are configured, otherwise the service name to resolve.
+func clientTarget(cfg *ClientConfig) string {
+	if len(cfg.Endpoints) > 0 {
+		return strings.Join(cfg.Endpoints, ",")
+	}
+	return cfg.ServiceName
+}
@@ -0,0 +1,90 @@
+package srpc
+
+import (
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// metricValue returns the value of the counter or gauge series of the named
+// metric in the default registry with exactly the given labels, and whether
+// the series exists.
+func metricValue(t *testing.T, name string, labels map[string]string) (float64, bool) {
+	t.Helper()
+	families, err := prometheus.DefaultGatherer.Gather()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, mf := range families {
+		if mf.GetName() != name {
+			continue
+		}
+	metrics:
+		for _, m := range mf.GetMetric() {
+			if len(m.GetLabel()) != len(labels) {
+				continue
+			}
+			for _, lp := range m.GetLabel() {
+				if want, ok := labels[lp.GetName()]; !ok || want != lp.GetValue() {
+					continue metrics
+				}
+			}
+			if m.GetCounter() != nil {
+				return m.GetCounter().GetValue(), true
+			}
+			return m.GetGauge().GetValue(), true
+		}
+	}
+	return 0, false
+}
+
+func TestRecordClientInfo(t *testing.T) {
+	tests := []struct {
+		name string
+		cfg  ClientConfig
+		want map[string]string
+	}{
+		{
+			name: "endpoints",
+			cfg: ClientConfig{
+				ServiceName:  "info-direct",
+				Endpoints:    []string{"10.0.0.1:8888", "10.0.0.2:8888"},
+				LoadBalancer: "roundrobin",
+			},
+			want: map[string]string{
+				"service":       "info-direct",
+				"discovery":     "",
+				"load_balancer": "roundrobin",
+				"target":        "10.0.0.1:8888,10.0.0.2:8888",
+			},
+		},
+		{
+			name: "discovery",
+			cfg: ClientConfig{
+				ServiceName:  "info-consul",
+				Discovery:    DiscoveryConfig{Type: "consul"},
+				LoadBalancer: "consistenthash",
+			},
+			want: map[string]string{
+				"service":       "info-consul",
+				"discovery":     "consul",
+				"load_balancer": "consistenthash",
+				"target":        "info-consul",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			recordClientInfo(&tt.cfg)
+
+			got, ok := metricValue(t, "goten_rpc_client_info", tt.want)
+			if !ok {
+				t.Fatalf("no goten_rpc_client_info series with labels %v", tt.want)
+			}
+			if got != 1 {
+				t.Errorf("goten_rpc_client_info = %v, want 1", got)
+			}
+		})
+	}
+}
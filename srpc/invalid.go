@@ -0,0 +1,31 @@
+package srpc
+
+import (
+	"context"
+	"net"
+
+	"github.com/cloudwego/kitex/pkg/proxy"
+)
+
+// invalidConfig makes Kitex refuse a client or server whose builder found an
+// invalid configuration. It is installed as the proxy so that the error is
+// returned by NewClient and Server.Run instead of the service running
+// without the setting that failed.
+type invalidConfig struct {
+	err error
+}
+
+// Configure implements proxy.ForwardProxy; Kitex calls it from NewClient.
+func (p invalidConfig) Configure(*proxy.Config) error {
+	return p.err
+}
+
+// ResolveProxyInstance implements proxy.ForwardProxy.
+func (p invalidConfig) ResolveProxyInstance(context.Context) error {
+	return p.err
+}
+
+// Replace implements proxy.ReverseProxy; Kitex calls it from Server.Run.
+func (p invalidConfig) Replace(net.Addr) (net.Addr, error) {
+	return nil, p.err
+}
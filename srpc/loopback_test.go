@@ -0,0 +1,115 @@
+package srpc
+
+import (
+	"context"
+	"net"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/cloudwego/kitex/client"
+	"github.com/cloudwego/kitex/client/genericclient"
+	"github.com/cloudwego/kitex/pkg/generic"
+	"github.com/cloudwego/kitex/server"
+	"github.com/cloudwego/kitex/server/genericserver"
+)
+
+// echoIDL describes the service served by startEchoServer.
+const echoIDL = `
+namespace go echo
+
+struct EchoRequest {
+	1: string msg
+}
+
+struct EchoResponse {
+	1: string msg
+}
+
+service Echo {
+	EchoResponse Echo(1: EchoRequest req)
+}
+`
+
+// echoHandler answers every call with the JSON response returned by reply.
+type echoHandler struct {
+	reply func(ctx context.Context, request string) string
+}
+
+// GenericCall implements the generic.Service interface.
+func (h echoHandler) GenericCall(ctx context.Context, method string, request interface{}) (interface{}, error) {
+	req, _ := request.(string)
+	return h.reply(ctx, req), nil
+}
+
+// echoGeneric returns the JSON generic codec for echoIDL.
+func echoGeneric(t *testing.T) generic.Generic {
+	t.Helper()
+	p, err := generic.NewThriftContentProvider(echoIDL, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	g, err := generic.JSONThriftGeneric(p)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return g
+}
+
+// freePort returns a TCP port on the loopback interface that was free.
+func freePort(t *testing.T) int {
+	t.Helper()
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ln.Close()
+	return ln.Addr().(*net.TCPAddr).Port
+}
+
+// startEchoServer runs a server built from cfg with handler until the test
+// ends and returns its address.
+func startEchoServer(t *testing.T, cfg *ServerConfig, handler echoHandler) string {
+	t.Helper()
+	cfg.Host = "127.0.0.1"
+	cfg.Port = freePort(t)
+	opts := append(NewServerBuilder(cfg).Build(), server.WithExitWaitTime(time.Millisecond))
+	svr := genericserver.NewServer(&handler, echoGeneric(t), opts...)
+
+	errCh := make(chan error, 1)
+	go func() { errCh <- svr.Run() }()
+	t.Cleanup(func() {
+		_ = svr.Stop()
+		<-errCh
+	})
+
+	addr := net.JoinHostPort(cfg.Host, strconv.Itoa(cfg.Port))
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		conn, err := net.DialTimeout("tcp", addr, 100*time.Millisecond)
+		if err == nil {
+			_ = conn.Close()
+			return addr
+		}
+		select {
+		case err := <-errCh:
+			t.Fatalf("server exited: %v", err)
+		default:
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("server not listening on %s: %v", addr, err)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+// newEchoClient returns a generic client built from cfg.
+func newEchoClient(t *testing.T, cfg *ClientConfig, opts ...client.Option) genericclient.Client {
+	t.Helper()
+	cli, err := genericclient.NewClient("echo", echoGeneric(t), append(NewClientBuilder(cfg).Build(), opts...)...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = cli.Close() })
+	return cli
+}
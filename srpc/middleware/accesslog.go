@@ -34,16 +34,9 @@ func AccessLog() endpoint.Middleware {
 
 			// Log the access
 			duration := time.Since(start)
-			fields := []interface{}{
-				"method", method,
-				"service", service,
-				"caller", caller,
-				"duration", duration.String(),
-				"duration_ms", duration.Milliseconds(),
-			}
+			fields := accessLogFields(ctx, method, service, caller, duration, err)
 
 			if err != nil {
-				fields = append(fields, "error", err.Error())
 				logx.Warnw("RPC access", fields...)
 			} else {
 				logx.Infow("RPC access", fields...)
@@ -54,7 +47,38 @@ func AccessLog() endpoint.Middleware {
 	}
 }
 
-// AccessLogWithConfig returns an access log middleware with custom configuration.
+// accessLogFieldsCap is the number of key-value entries an access log line
+// can hold, so that the fields slice is allocated once per request.
+const accessLogFieldsCap = 16
+
+// accessLogFields returns the fields of an RPC access log line.
+func accessLogFields(
+	ctx context.Context,
+	method, service, caller string,
+	duration time.Duration,
+	err error,
+) []interface{} {
+	fields := make([]interface{}, 0, accessLogFieldsCap)
+	fields = append(fields,
+		"method", method,
+		"service", service,
+		"caller", caller,
+		"duration", duration.String(),
+		"duration_ms", duration.Milliseconds(),
+	)
+	if requestID := RequestIDFromContext(ctx); requestID != "" {
+		fields = append(fields, "request_id", requestID)
+	}
+	if isStreaming(rpcinfo.GetRPCInfo(ctx)) {
+		fields = append(fields, "stream", true)
+	}
+	if err != nil {
+		fields = append(fields, "error", err.Error())
+	}
+	return fields
+}
+
+// AccessLogConfig configures the access log middleware.
 type AccessLogConfig struct {
 	// SkipMethods is a list of methods to skip logging.
 	SkipMethods []string
@@ -96,16 +120,9 @@ func AccessLogWithConfig(cfg AccessLogConfig) endpoint.Middleware {
 
 			// Log the access
 			duration := time.Since(start)
-			fields := []interface{}{
-				"method", method,
-				"service", service,
-				"caller", caller,
-				"duration", duration.String(),
-				"duration_ms", duration.Milliseconds(),
-			}
+			fields := accessLogFields(ctx, method, service, caller, duration, err)
 
 			if err != nil {
-				fields = append(fields, "error", err.Error())
 				logx.Warnw("RPC access", fields...)
 			} else if cfg.SlowThreshold > 0 && duration > cfg.SlowThreshold {
 				logx.Warnw("RPC slow access", fields...)
@@ -0,0 +1,65 @@
+package middleware
+
+import (
+	"context"
+
+	"github.com/cloudwego/kitex/pkg/endpoint"
+	"github.com/cloudwego/kitex/pkg/rpcinfo"
+	"github.com/cloudwego/kitex/pkg/utils"
+	"golang.org/x/sync/singleflight"
+)
+
+// CoalesceKeyFunc returns the coalescing key for a call.
+// Returning false sends the call through without coalescing.
+type CoalesceKeyFunc func(ctx context.Context, method string, req interface{}) (string, bool)
+
+// Coalesce returns a client middleware that deduplicates identical in-flight
+// calls: concurrent calls with the same target, method and key share the
+// response of a single call.
+//
+// Only use it for idempotent reads. Followers receive the same response
+// object as the first caller and must treat it as read-only, and they
+// inherit the first caller's deadline and cancellation.
+//
+// Example:
+//
+//	builder.WithMiddleware(middleware.Coalesce(func(ctx context.Context, method string, req interface{}) (string, bool) {
+//	    if r, ok := req.(interface{ GetFirstArgument() interface{} }); ok && method == "GetUser" {
+//	        return r.GetFirstArgument().(*user.GetUserRequest).GetId(), true
+//	    }
+//	    return "", false
+//	}))
+func Coalesce(key CoalesceKeyFunc) endpoint.Middleware {
+	var group singleflight.Group
+	return func(next endpoint.Endpoint) endpoint.Endpoint {
+		return func(ctx context.Context, req, resp interface{}) error {
+			result, ok := resp.(utils.KitexResult)
+			if !ok {
+				return next(ctx, req, resp)
+			}
+
+			var method, service string
+			if ri := rpcinfo.GetRPCInfo(ctx); ri != nil && ri.To() != nil {
+				method = ri.To().Method()
+				service = ri.To().ServiceName()
+			}
+
+			k, ok := key(ctx, method, req)
+			if !ok {
+				return next(ctx, req, resp)
+			}
+
+			v, err, shared := group.Do(service+"/"+method+"/"+k, func() (interface{}, error) {
+				err := next(ctx, req, resp)
+				return result.GetResult(), err
+			})
+			if shared && v != nil {
+				// The first caller already holds the response in resp.
+				if result.GetResult() == nil {
+					result.SetSuccess(v)
+				}
+			}
+			return err
+		}
+	}
+}
@@ -0,0 +1,88 @@
+package middleware
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// testResult is a minimal utils.KitexResult, like a generated XXXResult.
+type testResult struct{ success interface{} }
+
+func (r *testResult) GetResult() interface{}     { return r.success }
+func (r *testResult) SetSuccess(val interface{}) { r.success = val }
+
+func TestCoalesce(t *testing.T) {
+	const callers = 20
+
+	tests := []struct {
+		name      string
+		key       CoalesceKeyFunc
+		wantCalls int32
+	}{
+		{
+			name:      "identical calls share one",
+			key:       func(context.Context, string, interface{}) (string, bool) { return "user-1", true },
+			wantCalls: 1,
+		},
+		{
+			name:      "distinct keys",
+			key:       func(_ context.Context, _ string, req interface{}) (string, bool) { return req.(string), true },
+			wantCalls: callers,
+		},
+		{
+			name:      "not coalesced",
+			key:       func(context.Context, string, interface{}) (string, bool) { return "", false },
+			wantCalls: callers,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var calls atomic.Int32
+			release := make(chan struct{})
+			ep := Coalesce(tt.key)(func(_ context.Context, _, resp interface{}) error {
+				calls.Add(1)
+				<-release
+				resp.(*testResult).SetSuccess("pong")
+				return nil
+			})
+
+			var wg sync.WaitGroup
+			results := make([]*testResult, callers)
+			errs := make([]error, callers)
+			for i := range callers {
+				results[i] = &testResult{}
+				wg.Add(1)
+				go func() {
+					defer wg.Done()
+					errs[i] = ep(context.Background(), fmt.Sprintf("req-%d", i), results[i])
+				}()
+			}
+
+			// Let every caller reach the endpoint or join the in-flight call
+			deadline := time.Now().Add(time.Second)
+			for calls.Load() < tt.wantCalls && time.Now().Before(deadline) {
+				time.Sleep(time.Millisecond)
+			}
+			time.Sleep(50 * time.Millisecond)
+			close(release)
+			wg.Wait()
+
+			if got := calls.Load(); got != tt.wantCalls {
+				t.Errorf("endpoint called %d times, want %d", got, tt.wantCalls)
+			}
+			for i := range callers {
+				if errs[i] != nil {
+					t.Errorf("caller %d: error = %v", i, errs[i])
+				}
+				if got := results[i].GetResult(); got != "pong" {
+					t.Errorf("caller %d: result = %v, want pong", i, got)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,93 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/cloudwego/kitex/pkg/endpoint"
+	"github.com/cloudwego/kitex/pkg/kerrors"
+	"github.com/cloudwego/kitex/pkg/rpcinfo"
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/ssgohq/goten-core/metric"
+)
+
+var (
+	deadlineMetricsOnce    sync.Once
+	clientDeadlineUsage    *metric.HistogramVec
+	clientDeadlineExceeded *metric.CounterVec
+)
+
+func initDeadlineMetrics() {
+	deadlineMetricsOnce.Do(func() {
+		clientDeadlineUsage = metric.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: "goten",
+			Subsystem: "rpc_client",
+			Name:      "deadline_usage_ratio",
+			Help:      "Fraction of the call's deadline budget used by outbound RPCs",
+			Buckets:   []float64{0.1, 0.25, 0.5, 0.75, 0.9, 0.95, 1, 1.5},
+		}, []string{"service", "method"})
+		clientDeadlineExceeded = metric.NewCounterVec(prometheus.CounterOpts{
+			Namespace: "goten",
+			Subsystem: "rpc_client",
+			Name:      "deadline_exceeded_total",
+			Help:      "Total number of outbound RPCs that failed because their deadline was exceeded",
+		}, []string{"service", "method"})
+	})
+}
+
+// DeadlineMetrics returns a client middleware that records how much of its
+// deadline budget each call used, as elapsed time over the time remaining
+// when the call started, and counts calls failing with a timeout.
+// The budget is the context deadline, which includes the Kitex RPC timeout,
+// or the RPC timeout alone when the context has none; calls with neither are
+// not observed in the ratio histogram.
+func DeadlineMetrics() endpoint.Middleware {
+	initDeadlineMetrics()
+	return func(next endpoint.Endpoint) endpoint.Endpoint {
+		return func(ctx context.Context, req, resp interface{}) error {
+			start := time.Now()
+
+			// Extract the target and the budget
+			ri := rpcinfo.GetRPCInfo(ctx)
+			var method, service string
+			var budget time.Duration
+			if ri != nil {
+				if ri.To() != nil {
+					method = ri.To().Method()
+					service = ri.To().ServiceName()
+				}
+				if ri.Config() != nil {
+					budget = ri.Config().RPCTimeout()
+				}
+			}
+			if deadline, ok := ctx.Deadline(); ok {
+				budget = deadline.Sub(start)
+			}
+
+			// Execute the call
+			err := next(ctx, req, resp)
+
+			// Record the metrics
+			if budget > 0 {
+				clientDeadlineUsage.Observe(float64(time.Since(start))/float64(budget), service, method)
+			}
+			if isDeadlineExceeded(err) {
+				clientDeadlineExceeded.Inc(service, method)
+			}
+
+			return err
+		}
+	}
+}
+
+// isDeadlineExceeded reports whether err is a Kitex RPC timeout or a
+// context deadline, as opposed to any other failure.
+func isDeadlineExceeded(err error) bool {
+	if err == nil {
+		return false
+	}
+	return kerrors.IsTimeoutError(err) || errors.Is(err, context.DeadlineExceeded)
+}
@@ -0,0 +1,122 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/cloudwego/kitex/pkg/kerrors"
+	"github.com/cloudwego/kitex/pkg/rpcinfo"
+	"github.com/cloudwego/kitex/pkg/serviceinfo"
+)
+
+func TestDeadlineMetrics(t *testing.T) {
+	tests := []struct {
+		name         string
+		deadline     time.Duration // context deadline; 0 for none
+		rpcTimeout   time.Duration
+		next         func(ctx context.Context) error
+		wantObserved bool
+		minRatio     float64
+		maxRatio     float64
+		wantExceeded float64
+	}{
+		{
+			name:         "well within the deadline",
+			deadline:     time.Second,
+			next:         func(context.Context) error { return nil },
+			wantObserved: true,
+			maxRatio:     0.5,
+		},
+		{
+			name:     "near the deadline",
+			deadline: 200 * time.Millisecond,
+			next: func(context.Context) error {
+				time.Sleep(170 * time.Millisecond)
+				return nil
+			},
+			wantObserved: true,
+			minRatio:     0.8,
+			maxRatio:     1,
+		},
+		{
+			name:     "deadline exceeded",
+			deadline: 50 * time.Millisecond,
+			next: func(ctx context.Context) error {
+				<-ctx.Done()
+				return ctx.Err()
+			},
+			wantObserved: true,
+			minRatio:     1,
+			maxRatio:     10,
+			wantExceeded: 1,
+		},
+		{
+			name:       "rpc timeout without a context deadline",
+			rpcTimeout: 50 * time.Millisecond,
+			next: func(context.Context) error {
+				time.Sleep(60 * time.Millisecond)
+				return kerrors.ErrRPCTimeout.WithCause(errors.New("timeout"))
+			},
+			wantObserved: true,
+			minRatio:     1,
+			maxRatio:     10,
+			wantExceeded: 1,
+		},
+		{
+			name:     "other failures are not timeouts",
+			deadline: time.Second,
+			next: func(context.Context) error {
+				return kerrors.ErrRemoteOrNetwork.WithCause(errors.New("reset"))
+			},
+			wantObserved: true,
+			maxRatio:     0.5,
+		},
+		{
+			name: "no budget",
+			next: func(context.Context) error { return nil },
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			const (
+				usage    = "goten_rpc_client_deadline_usage_ratio"
+				exceeded = "goten_rpc_client_deadline_exceeded_total"
+			)
+			labels := map[string]string{"service": "deadline-test", "method": tt.name}
+			before := findMetric(t, usage, labels).GetHistogram()
+			beforeExceeded := metricCount(t, exceeded, labels)
+
+			ctx := callContext("deadline-test", tt.name, serviceinfo.StreamingNone)
+			if tt.rpcTimeout > 0 {
+				cfg := rpcinfo.AsMutableRPCConfig(rpcinfo.GetRPCInfo(ctx).Config())
+				if err := cfg.SetRPCTimeout(tt.rpcTimeout); err != nil {
+					t.Fatal(err)
+				}
+			}
+			if tt.deadline > 0 {
+				var cancel context.CancelFunc
+				ctx, cancel = context.WithTimeout(ctx, tt.deadline)
+				defer cancel()
+			}
+
+			ep := DeadlineMetrics()(func(ctx context.Context, _, _ interface{}) error { return tt.next(ctx) })
+			_ = ep(ctx, nil, nil)
+
+			after := findMetric(t, usage, labels).GetHistogram()
+			observed := after.GetSampleCount() - before.GetSampleCount()
+			if observed != 1 && tt.wantObserved || observed != 0 && !tt.wantObserved {
+				t.Fatalf("usage samples = %d, want observed %v", observed, tt.wantObserved)
+			}
+			if ratio := after.GetSampleSum() - before.GetSampleSum(); tt.wantObserved &&
+				(ratio < tt.minRatio || ratio > tt.maxRatio) {
+				t.Errorf("usage ratio = %.2f, want between %.2f and %.2f", ratio, tt.minRatio, tt.maxRatio)
+			}
+			if got := metricCount(t, exceeded, labels) - beforeExceeded; got != tt.wantExceeded {
+				t.Errorf("deadline exceeded = %v, want %v", got, tt.wantExceeded)
+			}
+		})
+	}
+}
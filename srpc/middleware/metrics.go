@@ -0,0 +1,207 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/cloudwego/kitex/pkg/endpoint"
+	"github.com/cloudwego/kitex/pkg/kerrors"
+	"github.com/cloudwego/kitex/pkg/rpcinfo"
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/ssgohq/goten-core/metric"
+	srpcerrors "github.com/ssgohq/goten-core/srpc/errors"
+)
+
+var (
+	serverMetricsOnce      sync.Once
+	serverRequestsTotal    *metric.CounterVec
+	serverErrorsTotal      *metric.CounterVec
+	serverRequestsDuration *metric.HistogramVec
+	serverStreamsDuration  *metric.HistogramVec
+	serverStreamMessages   *metric.CounterVec
+
+	clientMetricsOnce      sync.Once
+	clientRequestsTotal    *metric.CounterVec
+	clientErrorsTotal      *metric.CounterVec
+	clientRequestsDuration *metric.HistogramVec
+	clientStreamsDuration  *metric.HistogramVec
+)
+
+// streamBuckets covers stream lifetimes from 100ms to about half an hour.
+var streamBuckets = prometheus.ExponentialBuckets(0.1, 4, 8)
+
+func initServerMetrics() {
+	serverMetricsOnce.Do(func() {
+		serverRequestsTotal = metric.NewCounterVec(prometheus.CounterOpts{
+			Namespace: "goten",
+			Subsystem: "rpc_server",
+			Name:      "requests_total",
+			Help:      "Total number of RPC requests handled by the server",
+		}, []string{"service", "method", "code"})
+		serverErrorsTotal = metric.NewCounterVec(prometheus.CounterOpts{
+			Namespace: "goten",
+			Subsystem: "rpc_server",
+			Name:      "errors_total",
+			Help:      "Total number of RPC requests that returned an error",
+		}, []string{"service", "method", "code"})
+		serverRequestsDuration = metric.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: "goten",
+			Subsystem: "rpc_server",
+			Name:      "request_duration_seconds",
+			Help:      "RPC request latency in seconds",
+			Buckets:   prometheus.DefBuckets,
+		}, []string{"service", "method"})
+		serverStreamsDuration = metric.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: "goten",
+			Subsystem: "rpc_server",
+			Name:      "stream_duration_seconds",
+			Help:      "RPC stream lifetime in seconds",
+			Buckets:   streamBuckets,
+		}, []string{"service", "method"})
+		serverStreamMessages = metric.NewCounterVec(prometheus.CounterOpts{
+			Namespace: "goten",
+			Subsystem: "rpc_server",
+			Name:      "stream_messages_total",
+			Help:      "Total number of messages received or sent on RPC streams",
+		}, []string{"service", "method", "direction"})
+	})
+}
+
+func initClientMetrics() {
+	clientMetricsOnce.Do(func() {
+		clientRequestsTotal = metric.NewCounterVec(prometheus.CounterOpts{
+			Namespace: "goten",
+			Subsystem: "rpc_client",
+			Name:      "requests_total",
+			Help:      "Total number of outbound RPC attempts made by the client",
+		}, []string{"service", "method", "code", "attempt"})
+		clientErrorsTotal = metric.NewCounterVec(prometheus.CounterOpts{
+			Namespace: "goten",
+			Subsystem: "rpc_client",
+			Name:      "errors_total",
+			Help:      "Total number of outbound RPC attempts that returned an error",
+		}, []string{"service", "method", "code"})
+		clientRequestsDuration = metric.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: "goten",
+			Subsystem: "rpc_client",
+			Name:      "request_duration_seconds",
+			Help:      "Outbound RPC latency in seconds",
+			Buckets:   prometheus.DefBuckets,
+		}, []string{"service", "method"})
+		clientStreamsDuration = metric.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: "goten",
+			Subsystem: "rpc_client",
+			Name:      "stream_duration_seconds",
+			Help:      "Outbound RPC stream lifetime in seconds",
+			Buckets:   streamBuckets,
+		}, []string{"service", "method"})
+	})
+}
+
+// Metrics returns a server middleware that records request count, error
+// count and latency per method. The code label is the goten or Kitex
+// business status code of the response, or "0" on success.
+// Streams are timed separately, in stream_duration_seconds, since their
+// duration is the stream lifetime; see StreamRecvMetrics and
+// StreamSendMetrics for per-message counts.
+func Metrics() endpoint.Middleware {
+	initServerMetrics()
+	return func(next endpoint.Endpoint) endpoint.Endpoint {
+		return func(ctx context.Context, req, resp interface{}) error {
+			start := time.Now()
+
+			// Extract RPC info
+			ri := rpcinfo.GetRPCInfo(ctx)
+			var method, service string
+			if ri != nil && ri.Invocation() != nil {
+				method = ri.Invocation().MethodName()
+				service = ri.Invocation().ServiceName()
+			}
+
+			// Execute the request
+			err := next(ctx, req, resp)
+
+			// Record the metrics
+			code, failed := statusCode(ri, err)
+			serverRequestsTotal.Inc(service, method, code)
+			if failed {
+				serverErrorsTotal.Inc(service, method, code)
+			}
+			duration := time.Since(start)
+			if isStreaming(ri) {
+				serverStreamsDuration.Observe(duration.Seconds(), service, method)
+			} else {
+				serverRequestsDuration.Observe(duration.Seconds(), service, method)
+				metric.RecordLatency("rpc_server "+service+"/"+method, duration)
+			}
+
+			return err
+		}
+	}
+}
+
+// statusCode returns the status code label for a call and whether it failed.
+// Business status errors set by the handler are reported by Kitex through the
+// invocation rather than the returned error.
+func statusCode(ri rpcinfo.RPCInfo, err error) (string, bool) {
+	if err == nil && ri != nil && ri.Invocation() != nil {
+		if bizErr := ri.Invocation().BizStatusErr(); bizErr != nil {
+			err = bizErr
+		}
+	}
+	if err == nil {
+		return "0", false
+	}
+
+	var bizErr kerrors.BizStatusErrorIface
+	if errors.As(err, &bizErr) {
+		return strconv.Itoa(int(bizErr.BizStatusCode())), true
+	}
+	return strconv.Itoa(int(srpcerrors.Code(err))), true
+}
+
+// ClientMetrics returns a client middleware that records latency, attempts
+// and error codes of outbound calls per target service and method.
+// Kitex runs client middlewares once per attempt, so retried calls are
+// counted once per attempt with the attempt number as a label.
+func ClientMetrics() endpoint.Middleware {
+	initClientMetrics()
+	return func(next endpoint.Endpoint) endpoint.Endpoint {
+		return func(ctx context.Context, req, resp interface{}) error {
+			start := time.Now()
+
+			// Extract the target
+			ri := rpcinfo.GetRPCInfo(ctx)
+			var method, service string
+			attempt := "0"
+			if ri != nil && ri.To() != nil {
+				method = ri.To().Method()
+				service = ri.To().ServiceName()
+				attempt = ri.To().DefaultTag(rpcinfo.RetryTag, "0")
+			}
+
+			// Execute the call
+			err := next(ctx, req, resp)
+
+			// Record the metrics
+			code, failed := statusCode(ri, err)
+			clientRequestsTotal.Inc(service, method, code, attempt)
+			if failed {
+				clientErrorsTotal.Inc(service, method, code)
+			}
+			duration := time.Since(start)
+			if isStreaming(ri) {
+				clientStreamsDuration.Observe(duration.Seconds(), service, method)
+			} else {
+				clientRequestsDuration.Observe(duration.Seconds(), service, method)
+				metric.RecordLatency("rpc_client "+service+"/"+method, duration)
+			}
+
+			return err
+		}
+	}
+}
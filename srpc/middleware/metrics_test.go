@@ -0,0 +1,154 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/cloudwego/kitex/pkg/endpoint"
+	"github.com/cloudwego/kitex/pkg/rpcinfo"
+	"github.com/cloudwego/kitex/pkg/serviceinfo"
+	"github.com/cloudwego/kitex/pkg/streaming"
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+)
+
+// findMetric returns the series of the metric named name in the default
+// registry with the given labels, or nil. The middlewares register their
+// metrics globally, so tests compare readings taken before and after.
+func findMetric(t *testing.T, name string, labels map[string]string) *dto.Metric {
+	t.Helper()
+	families, err := prometheus.DefaultGatherer.Gather()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, mf := range families {
+		if mf.GetName() != name {
+			continue
+		}
+	metrics:
+		for _, m := range mf.GetMetric() {
+			for _, lp := range m.GetLabel() {
+				if want, ok := labels[lp.GetName()]; ok && want != lp.GetValue() {
+					continue metrics
+				}
+			}
+			return m
+		}
+	}
+	return nil
+}
+
+// metricCount returns the value of the counter, or the sample count of the
+// histogram, named name with the given labels.
+func metricCount(t *testing.T, name string, labels map[string]string) float64 {
+	t.Helper()
+	m := findMetric(t, name, labels)
+	if m.GetHistogram() != nil {
+		return float64(m.GetHistogram().GetSampleCount())
+	}
+	return m.GetCounter().GetValue()
+}
+
+// callContext returns a context carrying the RPC info of a call to
+// service/method in the given streaming mode.
+func callContext(service, method string, mode serviceinfo.StreamingMode) context.Context {
+	inv := rpcinfo.NewInvocation(service, method)
+	inv.SetStreamingMode(mode)
+	ri := rpcinfo.NewRPCInfo(nil, rpcinfo.NewEndpointInfo(service, method, nil, nil),
+		inv, rpcinfo.NewRPCConfig(), rpcinfo.NewRPCStats())
+	return rpcinfo.NewCtxWithRPCInfo(context.Background(), ri)
+}
+
+func TestMetricsStreaming(t *testing.T) {
+	middlewares := []struct {
+		name string
+		mw   endpoint.Middleware
+		side string
+	}{
+		{name: "server", mw: Metrics(), side: "rpc_server"},
+		{name: "client", mw: ClientMetrics(), side: "rpc_client"},
+	}
+	tests := []struct {
+		name       string
+		mode       serviceinfo.StreamingMode
+		wantStream bool
+	}{
+		{name: "unary", mode: serviceinfo.StreamingNone},
+		{name: "grpc unary", mode: serviceinfo.StreamingUnary},
+		{name: "client stream", mode: serviceinfo.StreamingClient, wantStream: true},
+		{name: "server stream", mode: serviceinfo.StreamingServer, wantStream: true},
+		{name: "bidirectional", mode: serviceinfo.StreamingBidirectional, wantStream: true},
+	}
+
+	for _, m := range middlewares {
+		for _, tt := range tests {
+			t.Run(m.name+"/"+tt.name, func(t *testing.T) {
+				labels := map[string]string{"service": "metrics-test", "method": tt.name}
+				requests := "goten_" + m.side + "_request_duration_seconds"
+				streams := "goten_" + m.side + "_stream_duration_seconds"
+				beforeRequests := metricCount(t, requests, labels)
+				beforeStreams := metricCount(t, streams, labels)
+
+				ep := m.mw(func(context.Context, interface{}, interface{}) error { return nil })
+				if err := ep(callContext("metrics-test", tt.name, tt.mode), nil, nil); err != nil {
+					t.Fatal(err)
+				}
+
+				wantRequests, wantStreams := 1.0, 0.0
+				if tt.wantStream {
+					wantRequests, wantStreams = 0, 1
+				}
+				if got := metricCount(t, requests, labels) - beforeRequests; got != wantRequests {
+					t.Errorf("%s samples = %v, want %v", requests, got, wantRequests)
+				}
+				if got := metricCount(t, streams, labels) - beforeStreams; got != wantStreams {
+					t.Errorf("%s samples = %v, want %v", streams, got, wantStreams)
+				}
+			})
+		}
+	}
+}
+
+func TestStreamMessageMetrics(t *testing.T) {
+	tests := []struct {
+		direction string
+		count     func(ctx context.Context, err error) error
+	}{
+		{
+			direction: "recv",
+			count: func(ctx context.Context, err error) error {
+				return StreamRecvMetrics()(func(context.Context, streaming.ServerStream, interface{}) error {
+					return err
+				})(ctx, nil, nil)
+			},
+		},
+		{
+			direction: "send",
+			count: func(ctx context.Context, err error) error {
+				return StreamSendMetrics()(func(context.Context, streaming.ServerStream, interface{}) error {
+					return err
+				})(ctx, nil, nil)
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.direction, func(t *testing.T) {
+			const name = "goten_rpc_server_stream_messages_total"
+			labels := map[string]string{"service": "metrics-test", "method": "Chat", "direction": tt.direction}
+			before := metricCount(t, name, labels)
+
+			ctx := callContext("metrics-test", "Chat", serviceinfo.StreamingBidirectional)
+			for i := 0; i < 3; i++ {
+				_ = tt.count(ctx, nil)
+			}
+			// Failed messages are not counted
+			_ = tt.count(ctx, errors.New("stream closed"))
+
+			if got := metricCount(t, name, labels) - before; got != 3 {
+				t.Errorf("%s messages = %v, want 3", tt.direction, got)
+			}
+		})
+	}
+}
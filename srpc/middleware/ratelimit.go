@@ -0,0 +1,118 @@
+package middleware
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/cloudwego/kitex/pkg/endpoint"
+	"github.com/cloudwego/kitex/pkg/rpcinfo"
+	"golang.org/x/time/rate"
+
+	srpcerrors "github.com/ssgohq/goten-core/srpc/errors"
+)
+
+const (
+	// RateLimitWildcard is the RateLimit key whose limit applies to every
+	// method that is not listed explicitly. Each method gets its own bucket.
+	RateLimitWildcard = "*"
+
+	// rateLimitIdleTTL is how long a method bucket may go unused before it
+	// is garbage-collected.
+	rateLimitIdleTTL = 10 * time.Minute
+)
+
+// RateLimit returns a server middleware that enforces a token-bucket QPS
+// limit per method name. The burst equals the limit. Methods that are not
+// listed are unlimited unless a RateLimitWildcard entry is present, and a
+// non-positive limit disables limiting for that method. Calls over the limit
+// fail with CodeResourceExhausted.
+//
+// Example:
+//
+//	builder.WithMiddleware(middleware.RateLimit(map[string]int{
+//	    "CreateUser":                 50,
+//	    middleware.RateLimitWildcard: 500,
+//	}))
+func RateLimit(limits map[string]int) endpoint.Middleware {
+	l := newMethodLimiter(limits)
+	return func(next endpoint.Endpoint) endpoint.Endpoint {
+		return func(ctx context.Context, req, resp interface{}) error {
+			var method string
+			if ri := rpcinfo.GetRPCInfo(ctx); ri != nil && ri.Invocation() != nil {
+				method = ri.Invocation().MethodName()
+			}
+
+			if !l.allow(method, time.Now()) {
+				return srpcerrors.ToKitexError(
+					srpcerrors.ResourceExhaustedf("rate limit exceeded for method %s", method),
+				)
+			}
+			return next(ctx, req, resp)
+		}
+	}
+}
+
+// methodLimiter holds one token bucket per method.
+type methodLimiter struct {
+	limits map[string]int
+
+	mu        sync.Mutex
+	buckets   map[string]*methodBucket
+	lastSweep time.Time
+}
+
+type methodBucket struct {
+	limiter  *rate.Limiter
+	lastUsed time.Time
+}
+
+func newMethodLimiter(limits map[string]int) *methodLimiter {
+	copied := make(map[string]int, len(limits))
+	for method, qps := range limits {
+		copied[method] = qps
+	}
+	return &methodLimiter{
+		limits:    copied,
+		buckets:   make(map[string]*methodBucket),
+		lastSweep: time.Now(),
+	}
+}
+
+// allow reports whether a call to method may proceed at now.
+func (l *methodLimiter) allow(method string, now time.Time) bool {
+	qps, ok := l.limits[method]
+	if !ok {
+		qps, ok = l.limits[RateLimitWildcard]
+	}
+	if !ok || qps <= 0 {
+		return true
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.sweep(now)
+
+	b, ok := l.buckets[method]
+	if !ok {
+		b = &methodBucket{limiter: rate.NewLimiter(rate.Limit(qps), qps)}
+		l.buckets[method] = b
+	}
+	b.lastUsed = now
+	return b.limiter.AllowN(now, 1)
+}
+
+// sweep drops buckets that have been idle for rateLimitIdleTTL. An idle
+// bucket is full, so recreating it later does not change behavior.
+func (l *methodLimiter) sweep(now time.Time) {
+	if now.Sub(l.lastSweep) < rateLimitIdleTTL {
+		return
+	}
+	for method, b := range l.buckets {
+		if now.Sub(b.lastUsed) >= rateLimitIdleTTL {
+			delete(l.buckets, method)
+		}
+	}
+	l.lastSweep = now
+}
@@ -0,0 +1,62 @@
+package middleware
+
+import (
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// TestMethodLimiterConcurrent calls allow from many goroutines at the same
+// instant, so no tokens refill and exactly the burst passes per bucket;
+// run it with -race.
+func TestMethodLimiterConcurrent(t *testing.T) {
+	const callers = 200
+
+	tests := []struct {
+		name        string
+		limits      map[string]int
+		methods     []string
+		wantAllowed int64
+	}{
+		{name: "limited method", limits: map[string]int{"Get": 10}, methods: []string{"Get"}, wantAllowed: 10},
+		{name: "unlisted method", limits: map[string]int{"Get": 10}, methods: []string{"List"}, wantAllowed: callers},
+		{name: "disabled limit", limits: map[string]int{"Get": 0}, methods: []string{"Get"}, wantAllowed: callers},
+		{
+			name:        "wildcard bucket per method",
+			limits:      map[string]int{RateLimitWildcard: 5},
+			methods:     []string{"Get", "List", "Delete"},
+			wantAllowed: 15,
+		},
+		{
+			name:        "explicit and wildcard",
+			limits:      map[string]int{"Get": 20, RateLimitWildcard: 5},
+			methods:     []string{"Get", "List"},
+			wantAllowed: 25,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			l := newMethodLimiter(tt.limits)
+			now := time.Now()
+
+			var allowed atomic.Int64
+			var wg sync.WaitGroup
+			for i := range callers {
+				wg.Add(1)
+				go func() {
+					defer wg.Done()
+					if l.allow(tt.methods[i%len(tt.methods)], now) {
+						allowed.Add(1)
+					}
+				}()
+			}
+			wg.Wait()
+
+			if got := allowed.Load(); got != tt.wantAllowed {
+				t.Errorf("allowed = %d, want %d", got, tt.wantAllowed)
+			}
+		})
+	}
+}
@@ -4,25 +4,29 @@ package middleware
 import (
 	"context"
 	"fmt"
+	"os"
 	"runtime/debug"
 
 	"github.com/cloudwego/kitex/pkg/endpoint"
+	"github.com/cloudwego/kitex/pkg/rpcinfo"
 
 	"github.com/ssgohq/goten-core/logx"
+	"github.com/ssgohq/goten-core/trace"
 )
 
 // Recovery returns a middleware that recovers from panics.
-// It logs the panic with stack trace and returns an internal error.
+// It logs the panic with stack trace, the method, service and caller, and
+// the request and trace IDs when present, then returns an internal error.
+// After logging the panic it dumps the recent log entries to stderr when
+// logx keeps a ring buffer (see logx.DumpRecentForPanic).
 func Recovery() endpoint.Middleware {
 	return func(next endpoint.Endpoint) endpoint.Endpoint {
 		return func(ctx context.Context, req, resp interface{}) (err error) {
 			defer func() {
 				if r := recover(); r != nil {
 					stack := debug.Stack()
-					logx.Errorw("Panic recovered in RPC handler",
-						"panic", fmt.Sprintf("%v", r),
-						"stack", string(stack),
-					)
+					logx.Errorw("Panic recovered in RPC handler", recoveryFields(ctx, r, stack)...)
+					_ = logx.DumpRecentForPanic(os.Stderr)
 					err = fmt.Errorf("internal server error")
 				}
 			}()
@@ -31,6 +35,35 @@ func Recovery() endpoint.Middleware {
 	}
 }
 
+// recoveryFields returns the fields of a panic log line.
+func recoveryFields(ctx context.Context, panicValue interface{}, stack []byte) []interface{} {
+	var method, service, caller string
+	if ri := rpcinfo.GetRPCInfo(ctx); ri != nil {
+		if ri.Invocation() != nil {
+			method = ri.Invocation().MethodName()
+			service = ri.Invocation().ServiceName()
+		}
+		if ri.From() != nil {
+			caller = ri.From().ServiceName()
+		}
+	}
+
+	fields := []interface{}{
+		"panic", fmt.Sprintf("%v", panicValue),
+		"stack", string(stack),
+		"method", method,
+		"service", service,
+		"caller", caller,
+	}
+	if requestID := RequestIDFromContext(ctx); requestID != "" {
+		fields = append(fields, "request_id", requestID)
+	}
+	if traceID := trace.TraceIDFromContext(ctx); traceID != "" {
+		fields = append(fields, "trace_id", traceID)
+	}
+	return fields
+}
+
 // RecoveryWithHandler returns a recovery middleware with custom panic handler.
 func RecoveryWithHandler(
 	handler func(ctx context.Context, panicValue interface{}, stack []byte) error,
@@ -0,0 +1,113 @@
+package middleware
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/cloudwego/kitex/pkg/rpcinfo"
+	oteltrace "go.opentelemetry.io/otel/trace"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+
+	"github.com/ssgohq/goten-core/internal/ctxkeys"
+	"github.com/ssgohq/goten-core/logx"
+)
+
+// panickingEndpoint panics with "boom".
+func panickingEndpoint(context.Context, interface{}, interface{}) error {
+	panic("boom")
+}
+
+func TestRecoveryLogFields(t *testing.T) {
+	traceID, err := oteltrace.TraceIDFromHex("0af7651916cd43dd8448eb211c80319c")
+	if err != nil {
+		t.Fatal(err)
+	}
+	withRPCInfo := func(ctx context.Context) context.Context {
+		ri := rpcinfo.NewRPCInfo(
+			rpcinfo.NewEndpointInfo("order-api", "", nil, nil),
+			rpcinfo.NewEndpointInfo("user-rpc", "GetUser", nil, nil),
+			rpcinfo.NewInvocation("user-rpc", "GetUser"),
+			nil, nil,
+		)
+		return rpcinfo.NewCtxWithRPCInfo(ctx, ri)
+	}
+
+	tests := []struct {
+		name       string
+		ctx        func() context.Context
+		wantFields map[string]string
+		wantAbsent []string
+	}{
+		{
+			name: "full context",
+			ctx: func() context.Context {
+				ctx := withRPCInfo(ctxkeys.WithRequestID(context.Background(), "req-1"))
+				return oteltrace.ContextWithSpanContext(ctx, oteltrace.NewSpanContext(oteltrace.SpanContextConfig{
+					TraceID: traceID,
+					SpanID:  oteltrace.SpanID{1},
+				}))
+			},
+			wantFields: map[string]string{
+				"panic":      "boom",
+				"method":     "GetUser",
+				"service":    "user-rpc",
+				"caller":     "order-api",
+				"request_id": "req-1",
+				"trace_id":   traceID.String(),
+			},
+		},
+		{
+			name: "no request or trace ID",
+			ctx:  func() context.Context { return withRPCInfo(context.Background()) },
+			wantFields: map[string]string{
+				"panic":   "boom",
+				"method":  "GetUser",
+				"service": "user-rpc",
+				"caller":  "order-api",
+			},
+			wantAbsent: []string{"request_id", "trace_id"},
+		},
+		{
+			name:       "no RPC info",
+			ctx:        context.Background,
+			wantFields: map[string]string{"panic": "boom", "method": "", "service": "", "caller": ""},
+			wantAbsent: []string{"request_id", "trace_id"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			core, logs := observer.New(zapcore.ErrorLevel)
+			prev := logx.L()
+			logx.SetLogger(zap.New(core).Sugar())
+			t.Cleanup(func() { logx.SetLogger(prev) })
+
+			err := Recovery()(panickingEndpoint)(tt.ctx(), nil, nil)
+			if err == nil {
+				t.Fatal("Recovery() returned no error for a panic")
+			}
+
+			entries := logs.FilterMessage("Panic recovered in RPC handler").All()
+			if len(entries) != 1 {
+				t.Fatalf("logged %d panics, want 1", len(entries))
+			}
+			fields := entries[0].ContextMap()
+			for key, want := range tt.wantFields {
+				if got, ok := fields[key]; !ok || got != want {
+					t.Errorf("%s = %v, want %q", key, got, want)
+				}
+			}
+			for _, key := range tt.wantAbsent {
+				if got, ok := fields[key]; ok {
+					t.Errorf("%s = %v, want absent", key, got)
+				}
+			}
+			if stack, _ := fields["stack"].(string); !strings.Contains(stack, "panickingEndpoint") {
+				t.Errorf("stack does not show the panicking function:\n%s", stack)
+			}
+		})
+	}
+}
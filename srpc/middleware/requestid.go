@@ -0,0 +1,55 @@
+package middleware
+
+import (
+	"context"
+
+	"github.com/bytedance/gopkg/cloud/metainfo"
+	"github.com/cloudwego/kitex/pkg/endpoint"
+	"github.com/google/uuid"
+
+	"github.com/ssgohq/goten-core/internal/ctxkeys"
+)
+
+// RequestIDKey is the transport metadata key carrying the request ID.
+// Metadata is transmitted with TTHeader and gRPC transports.
+const RequestIDKey = "request_id"
+
+// RequestID returns a server middleware that reads the request ID from the
+// incoming transport metadata, generating one if absent, and stores it in the
+// context for RequestIDFromContext and for downstream calls.
+func RequestID() endpoint.Middleware {
+	return func(next endpoint.Endpoint) endpoint.Endpoint {
+		return func(ctx context.Context, req, resp interface{}) error {
+			requestID, ok := metainfo.GetPersistentValue(ctx, RequestIDKey)
+			if !ok || requestID == "" {
+				requestID = uuid.New().String()
+				ctx = metainfo.WithPersistentValue(ctx, RequestIDKey, requestID)
+			}
+			return next(ctxkeys.WithRequestID(ctx, requestID), req, resp)
+		}
+	}
+}
+
+// ClientRequestID returns a client middleware that injects the request ID of
+// the context into the outgoing transport metadata.
+func ClientRequestID() endpoint.Middleware {
+	return func(next endpoint.Endpoint) endpoint.Endpoint {
+		return func(ctx context.Context, req, resp interface{}) error {
+			if requestID := ctxkeys.RequestID(ctx); requestID != "" {
+				ctx = metainfo.WithPersistentValue(ctx, RequestIDKey, requestID)
+			}
+			return next(ctx, req, resp)
+		}
+	}
+}
+
+// RequestIDFromContext returns the request ID of the current call, or "" if
+// none. It covers IDs set by this package, by the HTTP RequestID middleware,
+// and IDs received in transport metadata.
+func RequestIDFromContext(ctx context.Context) string {
+	if requestID := ctxkeys.RequestID(ctx); requestID != "" {
+		return requestID
+	}
+	requestID, _ := metainfo.GetPersistentValue(ctx, RequestIDKey)
+	return requestID
+}
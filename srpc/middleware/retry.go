@@ -0,0 +1,45 @@
+package middleware
+
+import (
+	"context"
+	"sync"
+
+	"github.com/cloudwego/kitex/pkg/endpoint"
+	"github.com/cloudwego/kitex/pkg/rpcinfo"
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/ssgohq/goten-core/metric"
+)
+
+var (
+	retryMetricsOnce sync.Once
+	retriesTotal     *metric.CounterVec
+)
+
+func initRetryMetrics() {
+	retryMetricsOnce.Do(func() {
+		retriesTotal = metric.NewCounterVec(prometheus.CounterOpts{
+			Namespace: "goten",
+			Subsystem: "rpc_client",
+			Name:      "retries_total",
+			Help:      "Total number of RPC retry attempts performed by the client",
+		}, []string{"service", "method", "attempt"})
+	})
+}
+
+// RetryMetrics returns a client middleware that counts retry attempts.
+// Kitex runs client middlewares once per attempt and tags retried calls,
+// so the counter is incremented for every attempt after the first.
+func RetryMetrics() endpoint.Middleware {
+	initRetryMetrics()
+	return func(next endpoint.Endpoint) endpoint.Endpoint {
+		return func(ctx context.Context, req, resp interface{}) error {
+			if ri := rpcinfo.GetRPCInfo(ctx); ri != nil && ri.To() != nil {
+				if attempt := ri.To().DefaultTag(rpcinfo.RetryTag, "0"); attempt != "0" {
+					retriesTotal.Inc(ri.To().ServiceName(), ri.To().Method(), attempt)
+				}
+			}
+			return next(ctx, req, resp)
+		}
+	}
+}
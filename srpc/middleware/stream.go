@@ -0,0 +1,72 @@
+package middleware
+
+import (
+	"context"
+
+	"github.com/cloudwego/kitex/pkg/endpoint/sep"
+	"github.com/cloudwego/kitex/pkg/rpcinfo"
+	"github.com/cloudwego/kitex/pkg/serviceinfo"
+	"github.com/cloudwego/kitex/pkg/streaming"
+)
+
+// isStreaming reports whether the call is a client, server or bidirectional
+// stream. For these a single endpoint invocation spans the whole stream, so
+// its duration is the stream lifetime rather than a request latency.
+// Unary calls over a streaming transport (gRPC unary) are not streams.
+func isStreaming(ri rpcinfo.RPCInfo) bool {
+	if ri == nil {
+		return false
+	}
+	if ri.Invocation() != nil {
+		switch ri.Invocation().StreamingMode() {
+		case serviceinfo.StreamingClient, serviceinfo.StreamingServer, serviceinfo.StreamingBidirectional:
+			return true
+		case serviceinfo.StreamingUnary:
+			return false
+		}
+	}
+	return ri.Config() != nil && ri.Config().InteractionMode() == rpcinfo.Streaming
+}
+
+// StreamRecvMetrics returns a server stream middleware counting the messages
+// received on each stream, per service and method.
+//
+// Example:
+//
+//	server.WithStreamOptions(server.WithStreamRecvMiddleware(middleware.StreamRecvMetrics()))
+func StreamRecvMetrics() sep.StreamRecvMiddleware {
+	initServerMetrics()
+	return func(next sep.StreamRecvEndpoint) sep.StreamRecvEndpoint {
+		return func(ctx context.Context, stream streaming.ServerStream, message interface{}) error {
+			err := next(ctx, stream, message)
+			if err == nil {
+				countStreamMessage(ctx, "recv")
+			}
+			return err
+		}
+	}
+}
+
+// StreamSendMetrics returns a server stream middleware counting the messages
+// sent on each stream, per service and method.
+func StreamSendMetrics() sep.StreamSendMiddleware {
+	initServerMetrics()
+	return func(next sep.StreamSendEndpoint) sep.StreamSendEndpoint {
+		return func(ctx context.Context, stream streaming.ServerStream, message interface{}) error {
+			err := next(ctx, stream, message)
+			if err == nil {
+				countStreamMessage(ctx, "send")
+			}
+			return err
+		}
+	}
+}
+
+func countStreamMessage(ctx context.Context, direction string) {
+	var method, service string
+	if ri := rpcinfo.GetRPCInfo(ctx); ri != nil && ri.Invocation() != nil {
+		method = ri.Invocation().MethodName()
+		service = ri.Invocation().ServiceName()
+	}
+	serverStreamMessages.Inc(service, method, direction)
+}
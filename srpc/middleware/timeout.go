@@ -0,0 +1,128 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/cloudwego/kitex/pkg/endpoint"
+	"github.com/cloudwego/kitex/pkg/kerrors"
+	"github.com/cloudwego/kitex/pkg/rpcinfo"
+
+	"github.com/ssgohq/goten-core/logx"
+)
+
+// ReadWriteTimeout returns a client middleware that fails an attempt with a
+// timeout error when no response arrives within d. Kitex runs client
+// middlewares once per attempt, so with retries each attempt gets its own
+// budget. The error wraps kerrors.ErrRPCTimeout, so retry policies treat it
+// as a timeout, and its cause names the read/write timeout to distinguish it
+// from the overall RPC timeout. A non-positive d disables the middleware.
+func ReadWriteTimeout(d time.Duration) endpoint.Middleware {
+	return func(next endpoint.Endpoint) endpoint.Endpoint {
+		if d <= 0 {
+			return next
+		}
+		return func(ctx context.Context, req, resp interface{}) error {
+			return callWithTimeout(ctx, next, req, resp, d, "read/write timeout")
+		}
+	}
+}
+
+// ContextDeadlineTimeout returns a client middleware that makes the context
+// deadline take precedence over the configured RPC timeout d. It is meant
+// for clients built without a Kitex RPC timeout: a call whose context has a
+// deadline runs until that deadline, even past d, and a call without one
+// fails with a timeout error wrapping kerrors.ErrRPCTimeout after d, per
+// attempt. A non-positive d leaves calls without a deadline unbounded.
+func ContextDeadlineTimeout(d time.Duration) endpoint.Middleware {
+	return func(next endpoint.Endpoint) endpoint.Endpoint {
+		return func(ctx context.Context, req, resp interface{}) error {
+			if deadline, ok := ctx.Deadline(); ok {
+				if remaining := time.Until(deadline); d > 0 && remaining > d {
+					logx.Debugw("RPC timeout overridden by context deadline",
+						"method", calleeMethod(ctx),
+						"rpcTimeout", d,
+						"deadline", remaining,
+					)
+				}
+				return next(ctx, req, resp)
+			}
+			if d <= 0 {
+				return next(ctx, req, resp)
+			}
+			return callWithTimeout(ctx, next, req, resp, d, "rpc timeout")
+		}
+	}
+}
+
+// RPCTimeoutOverrideLog returns a client middleware that logs, at debug
+// level, calls whose context deadline is tighter than the Kitex RPC timeout
+// and therefore ends them early. Kitex bounds each attempt by the earlier of
+// the two, so this only explains the shorter budget; it changes nothing.
+func RPCTimeoutOverrideLog() endpoint.Middleware {
+	return func(next endpoint.Endpoint) endpoint.Endpoint {
+		return func(ctx context.Context, req, resp interface{}) error {
+			ri := rpcinfo.GetRPCInfo(ctx)
+			deadline, ok := ctx.Deadline()
+			if ok && ri != nil && ri.Config() != nil {
+				// Kitex sets the deadline to the RPC timeout when it is the
+				// tighter one, so allow for the time spent reaching here.
+				timeout := ri.Config().RPCTimeout()
+				if remaining := time.Until(deadline); timeout > 0 && remaining < timeout-time.Millisecond {
+					logx.Debugw("RPC timeout overridden by context deadline",
+						"method", calleeMethod(ctx),
+						"rpcTimeout", timeout,
+						"deadline", remaining,
+					)
+				}
+			}
+			return next(ctx, req, resp)
+		}
+	}
+}
+
+// calleeMethod returns the method called by an outbound RPC, if known.
+func calleeMethod(ctx context.Context) string {
+	if ri := rpcinfo.GetRPCInfo(ctx); ri != nil && ri.To() != nil {
+		return ri.To().Method()
+	}
+	return ""
+}
+
+// callWithTimeout runs next, failing with a timeout error whose cause names
+// the timeout when it does not return within d. When the caller's context
+// ends first, its own error is returned unchanged.
+func callWithTimeout(
+	ctx context.Context,
+	next endpoint.Endpoint,
+	req, resp interface{},
+	d time.Duration,
+	name string,
+) error {
+	timeoutErr := fmt.Errorf("%s %s exceeded", name, d)
+	ctx, cancel := context.WithTimeoutCause(ctx, d, timeoutErr)
+	defer cancel()
+
+	done := make(chan error, 1)
+	go func() {
+		defer func() {
+			if r := recover(); r != nil {
+				done <- fmt.Errorf("panic in RPC call: %v", r)
+			}
+		}()
+		done <- next(ctx, req, resp)
+	}()
+
+	select {
+	case err := <-done:
+		return err
+	case <-ctx.Done():
+		// Only the deadline set here is reported as this timeout
+		if cause := context.Cause(ctx); errors.Is(cause, timeoutErr) {
+			return kerrors.ErrRPCTimeout.WithCause(cause)
+		}
+		return ctx.Err()
+	}
+}
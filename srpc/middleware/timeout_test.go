@@ -0,0 +1,101 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/cloudwego/kitex/pkg/kerrors"
+)
+
+// blockingEndpoint waits for ctx to end, like a call that gets no response.
+func blockingEndpoint(ctx context.Context, req, resp interface{}) error {
+	<-ctx.Done()
+	return ctx.Err()
+}
+
+func TestReadWriteTimeout(t *testing.T) {
+	tests := []struct {
+		name        string
+		timeout     time.Duration
+		parent      func() (context.Context, context.CancelFunc)
+		next        func(ctx context.Context, req, resp interface{}) error
+		wantTimeout bool
+		wantErr     error
+	}{
+		{
+			name:    "returns in time",
+			timeout: time.Second,
+			parent:  func() (context.Context, context.CancelFunc) { return context.WithCancel(context.Background()) },
+			next:    func(ctx context.Context, req, resp interface{}) error { return nil },
+		},
+		{
+			name:    "own deadline tighter",
+			timeout: 20 * time.Millisecond,
+			parent: func() (context.Context, context.CancelFunc) {
+				return context.WithTimeout(context.Background(), time.Second)
+			},
+			next:        blockingEndpoint,
+			wantTimeout: true,
+		},
+		{
+			name:    "caller deadline tighter",
+			timeout: time.Second,
+			parent: func() (context.Context, context.CancelFunc) {
+				return context.WithTimeout(context.Background(), 20*time.Millisecond)
+			},
+			next:    blockingEndpoint,
+			wantErr: context.DeadlineExceeded,
+		},
+		{
+			name:    "caller cancelled",
+			timeout: time.Second,
+			parent: func() (context.Context, context.CancelFunc) {
+				ctx, cancel := context.WithCancel(context.Background())
+				time.AfterFunc(20*time.Millisecond, cancel)
+				return ctx, cancel
+			},
+			next:    blockingEndpoint,
+			wantErr: context.Canceled,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx, cancel := tt.parent()
+			defer cancel()
+
+			err := ReadWriteTimeout(tt.timeout)(tt.next)(ctx, nil, nil)
+			if got := errors.Is(err, kerrors.ErrRPCTimeout); got != tt.wantTimeout {
+				t.Errorf("err = %v, want RPC timeout %v", err, tt.wantTimeout)
+			}
+			if tt.wantErr != nil && !errors.Is(err, tt.wantErr) {
+				t.Errorf("err = %v, want %v", err, tt.wantErr)
+			}
+			if !tt.wantTimeout && tt.wantErr == nil && err != nil {
+				t.Errorf("err = %v, want nil", err)
+			}
+		})
+	}
+}
+
+func TestContextDeadlineTimeout(t *testing.T) {
+	// A caller deadline longer than the RPC timeout wins
+	ctx, cancel := context.WithTimeout(context.Background(), 60*time.Millisecond)
+	defer cancel()
+	start := time.Now()
+	err := ContextDeadlineTimeout(10*time.Millisecond)(blockingEndpoint)(ctx, nil, nil)
+	if !errors.Is(err, context.DeadlineExceeded) || errors.Is(err, kerrors.ErrRPCTimeout) {
+		t.Errorf("with deadline: err = %v, want context.DeadlineExceeded", err)
+	}
+	if elapsed := time.Since(start); elapsed < 50*time.Millisecond {
+		t.Errorf("with deadline: returned after %v, before the caller deadline", elapsed)
+	}
+
+	// Without a deadline the RPC timeout applies
+	err = ContextDeadlineTimeout(10*time.Millisecond)(blockingEndpoint)(context.Background(), nil, nil)
+	if !errors.Is(err, kerrors.ErrRPCTimeout) {
+		t.Errorf("without deadline: err = %v, want RPC timeout", err)
+	}
+}
@@ -0,0 +1,89 @@
+package middleware
+
+import (
+	"context"
+
+	"github.com/cloudwego/kitex/pkg/endpoint"
+	"github.com/cloudwego/kitex/pkg/rpcinfo"
+	"github.com/cloudwego/kitex/pkg/stats"
+	"go.opentelemetry.io/otel/attribute"
+	oteltrace "go.opentelemetry.io/otel/trace"
+
+	"github.com/ssgohq/goten-core/trace"
+)
+
+// SpanCustomizer returns the span name and extra attributes for a call.
+// An empty name keeps the name chosen by the tracing suite.
+type SpanCustomizer func(ctx context.Context, method string, req interface{}) (string, []attribute.KeyValue)
+
+// CustomizeSpan returns a server middleware that renames the current RPC
+// span and adds attributes before the handler runs. It must run inside the
+// OpenTelemetry tracing suite, which starts the span; without an active
+// span it does nothing.
+//
+// Example:
+//
+//	adminSpans := func(_ context.Context, method string, _ interface{}) (string, []attribute.KeyValue) {
+//	    if strings.HasPrefix(method, "Admin") {
+//	        return "admin", []attribute.KeyValue{attribute.String("admin.method", method)}
+//	    }
+//	    return "", nil
+//	}
+//	builder.WithMiddleware(middleware.CustomizeSpan(adminSpans))
+func CustomizeSpan(fn SpanCustomizer) endpoint.Middleware {
+	return func(next endpoint.Endpoint) endpoint.Endpoint {
+		return func(ctx context.Context, req, resp interface{}) error {
+			if span := oteltrace.SpanFromContext(ctx); span.IsRecording() {
+				var method string
+				if ri := rpcinfo.GetRPCInfo(ctx); ri != nil && ri.Invocation() != nil {
+					method = ri.Invocation().MethodName()
+				}
+
+				name, attrs := fn(ctx, method, req)
+				if name != "" {
+					span.SetName(name)
+				}
+				if len(attrs) > 0 {
+					span.SetAttributes(attrs...)
+				}
+			}
+			return next(ctx, req, resp)
+		}
+	}
+}
+
+// untracedMethods is a Kitex tracer marking calls to listed methods with
+// trace.WithSuppressed.
+type untracedMethods map[string]bool
+
+// SkipTracing returns a Kitex client tracer that suppresses the spans of
+// calls to the given methods, for high-volume calls such as health pings.
+// It must be registered before the OpenTelemetry tracing suite, whose
+// tracer then starts a non-recording span from the marked context, so no
+// span is exported and no trace context is sent with the call. It relies on
+// the sampler installed by trace.StartAgent.
+//
+// Example:
+//
+//	cli, err := userservice.NewClient("user-rpc",
+//	    client.WithTracer(middleware.SkipTracing("Ping")),
+//	    client.WithSuite(kitextracing.NewClientSuite()),
+//	)
+func SkipTracing(methods ...string) stats.Tracer {
+	skip := make(untracedMethods, len(methods))
+	for _, method := range methods {
+		skip[method] = true
+	}
+	return skip
+}
+
+// Start implements stats.Tracer.
+func (m untracedMethods) Start(ctx context.Context) context.Context {
+	if ri := rpcinfo.GetRPCInfo(ctx); ri != nil && ri.Invocation() != nil && m[ri.Invocation().MethodName()] {
+		return trace.WithSuppressed(ctx)
+	}
+	return ctx
+}
+
+// Finish implements stats.Tracer.
+func (m untracedMethods) Finish(context.Context) {}
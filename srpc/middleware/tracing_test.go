@@ -0,0 +1,90 @@
+package middleware
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/cloudwego/kitex/pkg/rpcinfo"
+	"go.opentelemetry.io/otel/attribute"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func TestCustomizeSpan(t *testing.T) {
+	adminSpans := func(_ context.Context, method string, _ interface{}) (string, []attribute.KeyValue) {
+		if strings.HasPrefix(method, "Admin") {
+			return "admin", []attribute.KeyValue{attribute.String("admin.method", method)}
+		}
+		return "", nil
+	}
+
+	tests := []struct {
+		name      string
+		method    string
+		noSpan    bool
+		wantName  string
+		wantAttrs []attribute.KeyValue
+	}{
+		{
+			name:      "renamed with attributes",
+			method:    "AdminDelete",
+			wantName:  "admin",
+			wantAttrs: []attribute.KeyValue{attribute.String("admin.method", "AdminDelete")},
+		},
+		{name: "empty name keeps the suite name", method: "GetUser", wantName: "user/GetUser"},
+		{name: "no active span", method: "AdminDelete", noSpan: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			exporter := tracetest.NewInMemoryExporter()
+			tp := sdktrace.NewTracerProvider(sdktrace.WithSyncer(exporter))
+			t.Cleanup(func() { _ = tp.Shutdown(context.Background()) })
+
+			ri := rpcinfo.NewRPCInfo(nil, rpcinfo.NewEndpointInfo("user", tt.method, nil, nil),
+				rpcinfo.NewInvocation("user", tt.method), rpcinfo.NewRPCConfig(), rpcinfo.NewRPCStats())
+			ctx := rpcinfo.NewCtxWithRPCInfo(context.Background(), ri)
+
+			called := false
+			ep := CustomizeSpan(func(ctx context.Context, method string, req interface{}) (string, []attribute.KeyValue) {
+				called = true
+				return adminSpans(ctx, method, req)
+			})(func(context.Context, interface{}, interface{}) error { return nil })
+
+			if tt.noSpan {
+				if err := ep(ctx, nil, nil); err != nil {
+					t.Fatal(err)
+				}
+				if called {
+					t.Error("customizer called without an active span")
+				}
+				return
+			}
+
+			// The tracing suite starts the span before the middlewares run
+			ctx, span := tp.Tracer("kitex").Start(ctx, "user/"+tt.method)
+			if err := ep(ctx, nil, nil); err != nil {
+				t.Fatal(err)
+			}
+			span.End()
+
+			spans := exporter.GetSpans()
+			if len(spans) != 1 {
+				t.Fatalf("exported spans = %d, want 1", len(spans))
+			}
+			if got := spans[0].Name; got != tt.wantName {
+				t.Errorf("span name = %q, want %q", got, tt.wantName)
+			}
+			for _, want := range tt.wantAttrs {
+				found := false
+				for _, got := range spans[0].Attributes {
+					found = found || got == want
+				}
+				if !found {
+					t.Errorf("span attributes = %v, want %v", spans[0].Attributes, want)
+				}
+			}
+		})
+	}
+}
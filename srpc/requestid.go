@@ -0,0 +1,14 @@
+package srpc
+
+import (
+	"context"
+
+	"github.com/ssgohq/goten-core/srpc/middleware"
+)
+
+// RequestIDFromContext returns the request ID propagated to the current RPC,
+// or "" if none. ServerSuite and ClientSuite install the middlewares that
+// carry the ID across calls in transport metadata.
+func RequestIDFromContext(ctx context.Context) string {
+	return middleware.RequestIDFromContext(ctx)
+}
@@ -2,49 +2,75 @@ package srpc
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"net"
 	"os"
 	"os/signal"
+	"sync"
 	"syscall"
 	"time"
 
 	"github.com/cloudwego/kitex/pkg/endpoint"
 	"github.com/cloudwego/kitex/pkg/limit"
 	"github.com/cloudwego/kitex/pkg/registry"
+	"github.com/cloudwego/kitex/pkg/remote/trans/gonet"
 	"github.com/cloudwego/kitex/pkg/rpcinfo"
 	"github.com/cloudwego/kitex/server"
 	kitextracing "github.com/kitex-contrib/obs-opentelemetry/tracing"
 	consul "github.com/kitex-contrib/registry-consul"
 
+	"github.com/ssgohq/goten-core/internal/appname"
 	"github.com/ssgohq/goten-core/logx"
-	"github.com/ssgohq/goten-core/srpc/middleware"
 )
 
+// ErrServerExited is returned when a server's Run returns without an error
+// before shutdown was requested, which usually indicates a misconfiguration.
+var ErrServerExited = errors.New("srpc: server exited before shutdown was requested")
+
 // ServerBuilder helps construct Kitex server with common options.
 type ServerBuilder struct {
 	config   *ServerConfig
 	options  []server.Option
-	registry registry.Registry
+	registry *onceRegistry
+	info     *registry.Info
+	err      error
 }
 
 // NewServerBuilder creates a new server builder with the given configuration.
+// An invalid configuration is reported by Err and makes the server built
+// from the builder's options fail to start.
 func NewServerBuilder(config *ServerConfig) *ServerBuilder {
 	config.SetDefaults()
+	err := config.Validate()
+	if err != nil {
+		logx.Errorw("Invalid server config", "name", config.Name, "error", err)
+	}
+	appname.Set(config.Name)
 	return &ServerBuilder{
 		config:  config,
 		options: make([]server.Option, 0),
+		err:     err,
 	}
 }
 
+// Err returns the configuration error found by the builder, if any.
+func (b *ServerBuilder) Err() error {
+	return b.err
+}
+
 // Build returns all configured server options ready to pass to the generated
-// Kitex service NewServer function.
+// Kitex service NewServer function. If the configuration is invalid, the
+// options make the server's Run return the error from Err without listening.
 //
 // Example:
 //
 //	builder := srpc.NewServerBuilder(&config)
 //	svr := userservice.NewServer(&impl, builder.Build()...)
 func (b *ServerBuilder) Build() []server.Option {
+	if b.err != nil {
+		return b.invalid(b.err)
+	}
 	opts := make([]server.Option, 0, 10)
 
 	// 1. Basic service info
@@ -68,33 +94,59 @@ func (b *ServerBuilder) Build() []server.Option {
 		}))
 	}
 
-	// 4. Service registry
-	if reg := b.buildRegistry(); reg != nil {
-		opts = append(opts, server.WithRegistry(reg))
-		b.registry = reg
+	// 4. TLS (Go net transport with a TLS listener). The server must not
+	// fall back to plaintext, so a TLS error fails the server.
+	if b.config.TLS.Enabled {
+		tlsCfg, err := b.config.TLS.ServerTLSConfig()
+		if err != nil {
+			logx.Errorw("Failed to load server TLS config", "name", b.config.Name, "error", err)
+			return b.invalid(fmt.Errorf("srpc: %w", err))
+		}
+		opts = append(opts,
+			server.WithTransServerFactory(&tlsTransServerFactory{
+				factory: gonet.NewTransServerFactory(),
+				config:  tlsCfg,
+			}),
+			server.WithTransHandlerFactory(gonet.NewSvrTransHandlerFactory()),
+		)
 	}
 
-	// 5. OpenTelemetry tracing suite
-	if b.config.Trace.IsEnabled() {
-		opts = append(opts, server.WithSuite(kitextracing.NewServerSuite()))
+	// 5. Service registry
+	// The registry info is shared with Kitex, which fills in the listen
+	// address at startup, so that Server.Stop can deregister the instance.
+	reg, err := b.buildRegistry()
+	if err != nil {
+		logx.Errorw("Failed to create service registry", "name", b.config.Name, "error", err)
+		return b.invalid(err)
 	}
-
-	// 6. Recovery middleware
-	if b.config.EnableRecovery {
-		opts = append(opts, server.WithMiddleware(middleware.Recovery()))
+	if reg != nil {
+		b.registry = &onceRegistry{Registry: reg}
+		b.info = &registry.Info{}
+		if b.config.Discovery.Type == "consul" {
+			b.info.Tags = consulTags(b.config.Discovery.Consul.Tags)
+		}
+		opts = append(opts,
+			server.WithRegistry(b.registry),
+			server.WithRegistryInfo(b.info),
+		)
 	}
 
-	// 7. Access logging middleware
-	if b.config.EnableAccessLog {
-		opts = append(opts, server.WithMiddleware(middleware.AccessLog()))
-	}
+	// 6. Goten defaults: tracing, recovery, access logging and metrics
+	opts = append(opts, server.WithSuite(ServerSuite(b.config)))
 
-	// 8. User-provided options
+	// 7. User-provided options
 	opts = append(opts, b.options...)
 
 	return opts
 }
 
+// invalid records err and returns options that make the server fail to
+// start with it.
+func (b *ServerBuilder) invalid(err error) []server.Option {
+	b.err = err
+	return []server.Option{server.WithProxy(invalidConfig{err: err})}
+}
+
 // WithOption adds a custom server option.
 func (b *ServerBuilder) WithOption(opt server.Option) *ServerBuilder {
 	b.options = append(b.options, opt)
@@ -107,15 +159,37 @@ func (b *ServerBuilder) WithMiddleware(mw endpoint.Middleware) *ServerBuilder {
 	return b
 }
 
-// buildRegistry creates a service registry based on configuration.
-func (b *ServerBuilder) buildRegistry() registry.Registry {
+// NewServer wraps a Kitex server built from this builder's options.
+// Unlike the package-level NewServer, the returned Server deregisters the
+// instance from the service registry before it stops.
+//
+// Example:
+//
+//	builder := srpc.NewServerBuilder(&config)
+//	kitexSvr := userservice.NewServer(&impl, builder.Build()...)
+//	svr := builder.NewServer(kitexSvr)
+func (b *ServerBuilder) NewServer(kitexServer server.Server) *Server {
+	svr := NewServer(kitexServer, b.config)
+	if b.registry != nil {
+		svr.registry = b.registry
+		svr.info = b.info
+	}
+	return svr
+}
+
+// buildRegistry creates a service registry based on configuration. It fails on an
+// unknown discovery type rather than silently running without discovery.
+func (b *ServerBuilder) buildRegistry() (registry.Registry, error) {
 	switch b.config.Discovery.Type {
 	case "consul":
-		return b.buildConsulRegistry()
+		return b.buildConsulRegistry(), nil
 	case "etcd":
-		return b.buildEtcdRegistry()
+		return b.buildEtcdRegistry(), nil
+	case "", "none", "direct":
+		return nil, nil
 	default:
-		return nil
+		return nil, fmt.Errorf("srpc: discovery: %w %q (expected consul, etcd, direct or none)",
+			ErrUnknownDiscoveryType, b.config.Discovery.Type)
 	}
 }
 
@@ -123,31 +197,41 @@ func (b *ServerBuilder) buildRegistry() registry.Registry {
 func (b *ServerBuilder) buildConsulRegistry() registry.Registry {
 	cfg := b.config.Discovery.Consul
 
-	r, err := consul.NewConsulRegister(cfg.Address)
+	r, err := consul.NewConsulRegisterWithConfig(consulAPIConfig(cfg), consulRegistryOptions(cfg)...)
 	if err != nil {
 		logx.Errorw("Failed to create Consul registry", "address", cfg.Address, "error", err)
 		return nil
 	}
 
-	logx.Infow("Consul registry created", "address", cfg.Address)
+	logx.Infow("Consul registry created",
+		"address", cfg.Address,
+		"datacenter", cfg.Datacenter,
+		"healthCheck", cfg.IsHealthCheckEnabled(),
+	)
 	return r
 }
 
 // buildEtcdRegistry creates an etcd registry.
-// Note: Requires github.com/kitex-contrib/registry-etcd
+// Instances are stored using the kitex-contrib/registry-etcd key layout.
 func (b *ServerBuilder) buildEtcdRegistry() registry.Registry {
-	// TODO: Implement etcd registry when needed
-	// cfg := b.config.Discovery.Etcd
-	// r, err := etcd.NewEtcdRegistry(cfg.Hosts)
-	logx.Warnw("Etcd registry is not yet implemented, falling back to no registry")
-	return nil
+	cfg := b.config.Discovery.Etcd
+
+	r, err := newEtcdRegistry(cfg)
+	if err != nil {
+		logx.Errorw("Failed to create etcd registry", "hosts", cfg.Hosts, "error", err)
+		return nil
+	}
+
+	logx.Infow("Etcd registry created", "hosts", cfg.Hosts)
+	return r
 }
 
 // Server wraps a Kitex server with additional lifecycle management.
 type Server struct {
 	kitexServer server.Server
 	config      *ServerConfig
-	registry    registry.Registry //nolint:unused // reserved for future service deregistration
+	registry    registry.Registry
+	info        *registry.Info
 }
 
 // NewServer creates a Server wrapper around a Kitex server.
@@ -171,6 +255,11 @@ func NewServer(kitexServer server.Server, config *ServerConfig) *Server {
 // Run starts the server and blocks until shutdown signal is received.
 // It handles graceful shutdown automatically.
 func (s *Server) Run() error {
+	return s.RunContext(context.Background())
+}
+
+// RunContext is like Run but also shuts the server down when ctx is done.
+func (s *Server) RunContext(ctx context.Context) error {
 	logx.Infow("Starting RPC server",
 		"name", s.config.Name,
 		"host", s.config.Host,
@@ -178,36 +267,70 @@ func (s *Server) Run() error {
 		"discovery", s.config.Discovery.Type,
 	)
 
-	return RunWithGracefulShutdown(s.kitexServer)
+	return runUntilShutdown(ctx, s.kitexServer, s.Stop)
 }
 
-// Stop stops the server gracefully.
+// Stop deregisters the server from the service registry and then stops it
+// gracefully. Deregistering first shortens the window in which clients
+// route requests to an instance that is shutting down.
 func (s *Server) Stop() error {
+	s.deregister()
 	return s.kitexServer.Stop()
 }
 
+// deregister removes the instance from the service registry, if any.
+func (s *Server) deregister() {
+	if s.registry == nil || s.info == nil || s.info.Addr == nil {
+		return
+	}
+	if err := s.registry.Deregister(s.info); err != nil {
+		logx.Warnw("Failed to deregister service", "name", s.config.Name, "error", err)
+		return
+	}
+	logx.Infow("Service deregistered", "name", s.config.Name)
+}
+
 // RunWithGracefulShutdown starts a Kitex server and handles graceful shutdown
 // on SIGINT and SIGTERM signals.
+// Kitex deregisters the instance from its registry as part of Stop.
+// If the server exits on its own, its error is returned, or ErrServerExited
+// if it exited without one.
 func RunWithGracefulShutdown(svr server.Server) error {
+	return RunWithGracefulShutdownContext(context.Background(), svr)
+}
+
+// RunWithGracefulShutdownContext is like RunWithGracefulShutdown but also
+// shuts the server down when ctx is done.
+func RunWithGracefulShutdownContext(ctx context.Context, svr server.Server) error {
+	return runUntilShutdown(ctx, svr, svr.Stop)
+}
+
+// runUntilShutdown runs svr and calls stop on SIGINT, SIGTERM or when ctx is
+// done. It returns early if the server exits on its own.
+func runUntilShutdown(ctx context.Context, svr server.Server, stop func() error) error {
 	// Start server in goroutine
 	errCh := make(chan error, 1)
 	go func() {
-		if err := svr.Run(); err != nil {
-			errCh <- err
-		}
+		errCh <- svr.Run()
 	}()
 
 	// Wait for shutdown signal
 	sigCh := make(chan os.Signal, 1)
 	signal.Notify(sigCh, syscall.SIGINT, syscall.SIGTERM)
+	defer signal.Stop(sigCh)
 
 	select {
 	case err := <-errCh:
+		if err == nil {
+			return ErrServerExited
+		}
 		return err
 	case sig := <-sigCh:
 		logx.Infow("Received shutdown signal", "signal", sig)
-		return svr.Stop()
+	case <-ctx.Done():
+		logx.Infow("Context done, shutting down", "error", ctx.Err())
 	}
+	return stop()
 }
 
 // MustRun starts the server and panics if it fails.
@@ -240,33 +363,59 @@ func WithTracing() server.Option {
 type ShutdownHook func(ctx context.Context) error
 
 // RunWithHooks starts a server with custom shutdown hooks.
+// Hooks run before the server stops; like RunWithGracefulShutdown, it
+// returns early if the server exits on its own.
 func RunWithHooks(svr server.Server, hooks ...ShutdownHook) error {
-	errCh := make(chan error, 1)
-	go func() {
-		if err := svr.Run(); err != nil {
-			errCh <- err
-		}
-	}()
-
-	sigCh := make(chan os.Signal, 1)
-	signal.Notify(sigCh, syscall.SIGINT, syscall.SIGTERM)
+	return RunWithHooksContext(context.Background(), svr, hooks...)
+}
 
-	select {
-	case err := <-errCh:
-		return err
-	case sig := <-sigCh:
-		logx.Infow("Received shutdown signal, running hooks", "signal", sig)
+// RunWithHooksContext is like RunWithHooks but also shuts the server down
+// when ctx is done.
+func RunWithHooksContext(ctx context.Context, svr server.Server, hooks ...ShutdownHook) error {
+	return runUntilShutdown(ctx, svr, func() error {
+		logx.Infow("Running shutdown hooks", "count", len(hooks))
 
-		ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+		hookCtx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
 		defer cancel()
 
 		// Run shutdown hooks
 		for _, hook := range hooks {
-			if err := hook(ctx); err != nil {
+			if err := hook(hookCtx); err != nil {
 				logx.Warnw("Shutdown hook failed", "error", err)
 			}
 		}
 
 		return svr.Stop()
+	})
+}
+
+// onceRegistry wraps a registry so that an instance is deregistered at most
+// once per registration. Server.Stop deregisters before Kitex does, and the
+// second call must not fail the shutdown.
+type onceRegistry struct {
+	registry.Registry
+	mu           sync.Mutex
+	deregistered bool
+}
+
+// Register registers the instance and re-arms deregistration.
+func (r *onceRegistry) Register(info *registry.Info) error {
+	r.mu.Lock()
+	r.deregistered = false
+	r.mu.Unlock()
+	return r.Registry.Register(info)
+}
+
+// Deregister deregisters the instance unless it was already deregistered.
+func (r *onceRegistry) Deregister(info *registry.Info) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.deregistered {
+		return nil
+	}
+	if err := r.Registry.Deregister(info); err != nil {
+		return err
 	}
+	r.deregistered = true
+	return nil
 }
@@ -0,0 +1,178 @@
+package srpc
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+
+	"github.com/cloudwego/kitex/client/genericclient"
+	"github.com/cloudwego/kitex/pkg/generic"
+	"github.com/cloudwego/kitex/server"
+	"github.com/cloudwego/kitex/server/genericserver"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+
+	"github.com/ssgohq/goten-core/logx"
+)
+
+// nopService is a generic service that is never called.
+type nopService struct{}
+
+// GenericCall implements the generic.Service interface.
+func (*nopService) GenericCall(context.Context, string, interface{}) (interface{}, error) {
+	return nil, nil
+}
+
+func TestServerBuilderInvalidConfig(t *testing.T) {
+	builder := NewServerBuilder(&ServerConfig{Name: "user", Host: "127.0.0.1", Port: -1})
+	if builder.Err() == nil {
+		t.Fatal("Err() = nil for an out-of-range port")
+	}
+
+	svr := genericserver.NewServer(&nopService{}, generic.BinaryThriftGeneric(), builder.Build()...)
+	if err := svr.Run(); err == nil {
+		t.Fatal("server built from an invalid config started")
+	}
+}
+
+func TestBuildDiscoveryType(t *testing.T) {
+	tests := []struct {
+		name    string
+		typ     string
+		wantErr bool
+	}{
+		{name: "none", typ: "none"},
+		{name: "direct", typ: "direct"},
+		{name: "typo", typ: "consol", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			core, logs := observer.New(zapcore.ErrorLevel)
+			prev := logx.L()
+			logx.SetLogger(zap.New(core).Sugar())
+			t.Cleanup(func() { logx.SetLogger(prev) })
+
+			// The type is changed after the builders validated the config,
+			// so that only Build can catch it
+			srvCfg := &ServerConfig{Name: "user", Host: "127.0.0.1", Port: 0}
+			srvBuilder := NewServerBuilder(srvCfg)
+			srvCfg.Discovery.Type = tt.typ
+			srvOpts := srvBuilder.Build()
+			if err := srvBuilder.Err(); errors.Is(err, ErrUnknownDiscoveryType) != tt.wantErr {
+				t.Errorf("server builder Err() = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			cliCfg := &ClientConfig{ServiceName: "user", Endpoints: []string{"127.0.0.1:8888"}}
+			cliBuilder := NewClientBuilder(cliCfg)
+			cliCfg.Discovery.Type = tt.typ
+			cli, err := genericclient.NewClient("user", generic.BinaryThriftGeneric(), cliBuilder.Build()...)
+			if errors.Is(err, ErrUnknownDiscoveryType) != tt.wantErr {
+				t.Errorf("NewClient() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if cli != nil {
+				_ = cli.Close()
+			}
+			if err := cliBuilder.Err(); errors.Is(err, ErrUnknownDiscoveryType) != tt.wantErr {
+				t.Errorf("client builder Err() = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			if tt.wantErr {
+				svr := genericserver.NewServer(&nopService{}, generic.BinaryThriftGeneric(), srvOpts...)
+				if err := svr.Run(); !errors.Is(err, ErrUnknownDiscoveryType) {
+					t.Errorf("Run() = %v, want ErrUnknownDiscoveryType", err)
+				}
+			}
+			// One error from each builder, and none for a known type
+			wantLogs := 0
+			if tt.wantErr {
+				wantLogs = 2
+			}
+			if logs.Len() != wantLogs {
+				t.Errorf("error logs = %v, want %d", logs.All(), wantLogs)
+			}
+		})
+	}
+}
+
+// fakeServer is a server.Server whose Run returns err, or blocks until Stop
+// if block is set.
+type fakeServer struct {
+	server.Server
+	err     error
+	block   bool
+	stopped chan struct{}
+}
+
+func (s *fakeServer) Run() error {
+	if s.block {
+		<-s.stopped
+	}
+	return s.err
+}
+
+func (s *fakeServer) Stop() error {
+	close(s.stopped)
+	return nil
+}
+
+func TestRunWithHooksContext(t *testing.T) {
+	errListen := errors.New("address in use")
+	tests := []struct {
+		name      string
+		runErr    error
+		block     bool
+		hookErr   error
+		wantErr   error
+		wantHooks []string
+	}{
+		{name: "context cancelled", block: true, wantHooks: []string{"flush", "close"}},
+		{
+			name:      "failing hook does not stop the others",
+			block:     true,
+			hookErr:   errors.New("flush failed"),
+			wantHooks: []string{"flush", "close"},
+		},
+		{name: "server exits with an error", runErr: errListen, wantErr: errListen},
+		{name: "server exits without an error", wantErr: ErrServerExited},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svr := &fakeServer{err: tt.runErr, block: tt.block, stopped: make(chan struct{})}
+			var ran []string
+			hook := func(name string, err error) ShutdownHook {
+				return func(context.Context) error {
+					ran = append(ran, name)
+					return err
+				}
+			}
+
+			ctx, cancel := context.WithCancel(context.Background())
+			if tt.block {
+				cancel()
+			}
+			defer cancel()
+
+			err := RunWithHooksContext(ctx, svr, hook("flush", tt.hookErr), hook("close", nil))
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("RunWithHooksContext() = %v, want %v", err, tt.wantErr)
+			}
+			if !slices.Equal(ran, tt.wantHooks) {
+				t.Errorf("hooks run = %q, want %q", ran, tt.wantHooks)
+			}
+			select {
+			case <-svr.stopped:
+				if !tt.block {
+					t.Error("server stopped after it exited on its own")
+				}
+			default:
+				if tt.block {
+					t.Error("server not stopped")
+				}
+			}
+		})
+	}
+}
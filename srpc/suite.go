@@ -0,0 +1,122 @@
+package srpc
+
+import (
+	"github.com/cloudwego/kitex/client"
+	"github.com/cloudwego/kitex/server"
+	"github.com/cloudwego/kitex/transport"
+	kitextracing "github.com/kitex-contrib/obs-opentelemetry/tracing"
+
+	"github.com/ssgohq/goten-core/srpc/middleware"
+)
+
+// serverSuite bundles the goten server defaults.
+type serverSuite struct {
+	config *ServerConfig
+}
+
+// ServerSuite returns a Kitex suite with the goten server defaults:
+// tracing when enabled in the trace config, request ID propagation, and the
+// recovery, access-log and metrics middlewares when enabled in the server
+// config.
+//
+// Example:
+//
+//	svr := userservice.NewServer(&impl, server.WithSuite(srpc.ServerSuite(&config)))
+func ServerSuite(config *ServerConfig) server.Suite {
+	config.SetDefaults()
+	return &serverSuite{config: config}
+}
+
+// Options implements the server.Suite interface.
+func (s *serverSuite) Options() []server.Option {
+	var opts []server.Option
+
+	// 1. OpenTelemetry tracing suite
+	if s.config.Trace.IsEnabled() {
+		opts = append(opts, server.WithSuite(kitextracing.NewServerSuite()))
+	}
+
+	// 2. Request ID propagation, ahead of the middlewares that log it
+	opts = append(opts, server.WithMiddleware(middleware.RequestID()))
+
+	// 3. Recovery middleware
+	if s.config.EnableRecovery {
+		opts = append(opts, server.WithMiddleware(middleware.Recovery()))
+	}
+
+	// 4. Access logging middleware
+	if s.config.EnableAccessLog {
+		opts = append(opts, server.WithMiddleware(middleware.AccessLog()))
+	}
+
+	// 5. Metrics middleware, plus per-message counts for streams
+	if s.config.EnableMetrics {
+		opts = append(opts,
+			server.WithMiddleware(middleware.Metrics()),
+			server.WithStreamOptions(
+				server.WithStreamRecvMiddleware(middleware.StreamRecvMetrics()),
+				server.WithStreamSendMiddleware(middleware.StreamSendMetrics()),
+			),
+		)
+	}
+
+	return opts
+}
+
+// clientSuite bundles the goten client defaults.
+type clientSuite struct {
+	config *ClientConfig
+}
+
+// ClientSuite returns a Kitex suite with the goten client defaults: the
+// TTHeader transport, client spans, except for calls to UntracedMethods,
+// trace context and request ID propagation, retry metrics when retries are
+// enabled, and outbound and deadline metrics when enabled in the client
+// config.
+//
+// The request ID and trace context travel in TTHeader metadata, so the
+// suite enables TTHeader on top of the default transport. Clients that
+// select gRPC with client.WithTransportProtocol keep propagating them in
+// HTTP/2 headers.
+//
+// Example:
+//
+//	cli, err := userservice.NewClient("user-rpc", client.WithSuite(srpc.ClientSuite(&config)))
+func ClientSuite(config *ClientConfig) client.Suite {
+	config.SetDefaults()
+	return &clientSuite{config: config}
+}
+
+// Options implements the client.Suite interface.
+func (s *clientSuite) Options() []client.Option {
+	var opts []client.Option
+
+	// 1. TTHeader transport, which carries the propagated metadata
+	opts = append(opts, client.WithTransportProtocol(transport.TTHeader))
+
+	// 2. OpenTelemetry tracing suite
+	// This propagates trace context from incoming requests to outgoing RPC calls.
+	// Opted-out methods are marked first, so that their spans are dropped.
+	if len(s.config.UntracedMethods) > 0 {
+		opts = append(opts, client.WithTracer(middleware.SkipTracing(s.config.UntracedMethods...)))
+	}
+	opts = append(opts, client.WithSuite(kitextracing.NewClientSuite()))
+
+	// 3. Request ID propagation
+	opts = append(opts, client.WithMiddleware(middleware.ClientRequestID()))
+
+	// 4. Retry metrics middleware
+	if s.config.Retry.Enabled {
+		opts = append(opts, client.WithMiddleware(middleware.RetryMetrics()))
+	}
+
+	// 5. Outbound and deadline budget metrics middlewares
+	if s.config.EnableMetrics {
+		opts = append(opts,
+			client.WithMiddleware(middleware.ClientMetrics()),
+			client.WithMiddleware(middleware.DeadlineMetrics()),
+		)
+	}
+
+	return opts
+}
@@ -0,0 +1,206 @@
+package srpc
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/cloudwego/kitex/client"
+	"github.com/cloudwego/kitex/client/genericclient"
+	"github.com/cloudwego/kitex/pkg/diagnosis"
+	"github.com/cloudwego/kitex/pkg/generic"
+	"github.com/cloudwego/kitex/server"
+	"github.com/cloudwego/kitex/server/genericserver"
+
+	"github.com/ssgohq/goten-core/internal/ctxkeys"
+	"github.com/ssgohq/goten-core/srpc/middleware"
+	"github.com/ssgohq/goten-core/trace"
+)
+
+func TestClientSuiteRequestID(t *testing.T) {
+	tests := []struct {
+		name      string
+		requestID string
+	}{
+		{name: "propagated", requestID: "req-123"},
+		{name: "generated by the server", requestID: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			addr := startEchoServer(t, &ServerConfig{Name: "echo"}, echoHandler{
+				reply: func(ctx context.Context, _ string) string {
+					resp, _ := json.Marshal(map[string]string{"msg": middleware.RequestIDFromContext(ctx)})
+					return string(resp)
+				},
+			})
+			cli := newEchoClient(t, &ClientConfig{ServiceName: "echo", Endpoints: []string{addr}})
+
+			ctx := context.Background()
+			if tt.requestID != "" {
+				ctx = ctxkeys.WithRequestID(ctx, tt.requestID)
+			}
+			resp, err := cli.GenericCall(ctx, "Echo", `{"msg":"hi"}`)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var got struct{ Msg string }
+			if err := json.Unmarshal([]byte(resp.(string)), &got); err != nil {
+				t.Fatal(err)
+			}
+			switch {
+			case tt.requestID != "" && got.Msg != tt.requestID:
+				t.Errorf("server request ID = %q, want %q", got.Msg, tt.requestID)
+			case got.Msg == "":
+				t.Error("server request ID is empty")
+			}
+		})
+	}
+}
+
+// probes is a diagnosis.Service that keeps the registered probes. Kitex
+// registers the debug info of the applied options, which names every
+// middleware, tracer and nested suite.
+type probes map[diagnosis.ProbeName]diagnosis.ProbeFunc
+
+// RegisterProbeFunc implements the diagnosis.Service interface.
+func (p probes) RegisterProbeFunc(name diagnosis.ProbeName, fn diagnosis.ProbeFunc) {
+	p[name] = fn
+}
+
+// serverSuiteOptions returns the debug info of a server using ServerSuite.
+func serverSuiteOptions(t *testing.T, cfg *ServerConfig) string {
+	t.Helper()
+	p := probes{}
+	// Kitex applies the options and registers the probes before it asks the
+	// proxy for the listen address, so the server never listens.
+	svr := genericserver.NewServer(&nopService{}, generic.BinaryThriftGeneric(),
+		server.WithSuite(ServerSuite(cfg)),
+		server.WithDiagnosisService(p),
+		server.WithProxy(invalidConfig{err: errors.New("not listening")}),
+	)
+	if err := svr.Run(); err == nil {
+		t.Fatal("server started")
+	}
+	return fmt.Sprint(p[diagnosis.OptionsKey]())
+}
+
+// clientSuiteOptions returns the debug info of a client using ClientSuite.
+func clientSuiteOptions(t *testing.T, cfg *ClientConfig) string {
+	t.Helper()
+	p := probes{}
+	cli, err := genericclient.NewClient("echo", generic.BinaryThriftGeneric(),
+		client.WithSuite(ClientSuite(cfg)),
+		client.WithDiagnosisService(p),
+		client.WithHostPorts("127.0.0.1:1"),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = cli.Close() })
+	return fmt.Sprint(p[diagnosis.OptionsKey]())
+}
+
+func TestServerSuiteOptions(t *testing.T) {
+	const (
+		tracing    = "tracing.ServerMiddleware"
+		requestID  = "middleware.RequestID."
+		recovery   = "middleware.Recovery."
+		accessLog  = "middleware.AccessLog."
+		metrics    = "middleware.Metrics."
+		streamRecv = "middleware.StreamRecvMetrics."
+		streamSend = "middleware.StreamSendMetrics."
+	)
+	all := []string{tracing, requestID, recovery, accessLog, metrics, streamRecv, streamSend}
+
+	tests := []struct {
+		name string
+		cfg  ServerConfig
+		want []string
+	}{
+		{name: "defaults", cfg: ServerConfig{Name: "echo"}, want: []string{requestID}},
+		{
+			name: "tracing",
+			cfg:  ServerConfig{Name: "echo", Trace: trace.Config{Name: "echo", Endpoint: "localhost:4317"}},
+			want: []string{tracing, requestID},
+		},
+		{
+			name: "recovery and access log",
+			cfg:  ServerConfig{Name: "echo", EnableRecovery: true, EnableAccessLog: true},
+			want: []string{requestID, recovery, accessLog},
+		},
+		{
+			name: "metrics",
+			cfg:  ServerConfig{Name: "echo", EnableMetrics: true},
+			want: []string{requestID, metrics, streamRecv, streamSend},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := serverSuiteOptions(t, &tt.cfg)
+			assertContributes(t, got, all, tt.want)
+		})
+	}
+}
+
+func TestClientSuiteOptions(t *testing.T) {
+	const (
+		ttheader  = "WithTransportProtocol(TTHeader)"
+		untraced  = "middleware.untracedMethods"
+		tracing   = "tracing.ClientMiddleware"
+		requestID = "middleware.ClientRequestID."
+		retries   = "middleware.RetryMetrics."
+		metrics   = "middleware.ClientMetrics."
+		deadline  = "middleware.DeadlineMetrics."
+	)
+	all := []string{ttheader, untraced, tracing, requestID, retries, metrics, deadline}
+
+	tests := []struct {
+		name string
+		cfg  ClientConfig
+		want []string
+	}{
+		{name: "defaults", cfg: ClientConfig{}, want: []string{ttheader, tracing, requestID}},
+		{
+			name: "untraced methods",
+			cfg:  ClientConfig{UntracedMethods: []string{"Ping"}},
+			want: []string{ttheader, untraced, tracing, requestID},
+		},
+		{
+			name: "retry",
+			cfg:  ClientConfig{Retry: RetryConfig{Enabled: true}},
+			want: []string{ttheader, tracing, requestID, retries},
+		},
+		{
+			name: "metrics",
+			cfg:  ClientConfig{EnableMetrics: true},
+			want: []string{ttheader, tracing, requestID, metrics, deadline},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := clientSuiteOptions(t, &tt.cfg)
+			assertContributes(t, got, all, tt.want)
+		})
+	}
+}
+
+// assertContributes checks that the debug info names exactly the wanted
+// entries among all.
+func assertContributes(t *testing.T, info string, all, want []string) {
+	t.Helper()
+	for _, name := range all {
+		wanted := false
+		for _, w := range want {
+			wanted = wanted || w == name
+		}
+		if got := strings.Contains(info, name); got != wanted {
+			t.Errorf("suite contributes %s = %v, want %v", name, got, wanted)
+		}
+	}
+}
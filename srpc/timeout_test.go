@@ -0,0 +1,78 @@
+package srpc
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestClientTimeoutAndContextDeadline(t *testing.T) {
+	const (
+		short   = 50 * time.Millisecond
+		handler = 300 * time.Millisecond
+		long    = 2 * time.Second
+	)
+	tests := []struct {
+		name     string
+		timeout  ClientTimeoutConfig
+		deadline time.Duration
+		wantErr  bool
+	}{
+		{name: "rpc timeout without deadline", timeout: ClientTimeoutConfig{RPC: short}, wantErr: true},
+		{
+			name:     "looser deadline capped by rpc timeout",
+			timeout:  ClientTimeoutConfig{RPC: short},
+			deadline: long,
+			wantErr:  true,
+		},
+		{name: "tighter deadline wins", timeout: ClientTimeoutConfig{RPC: long}, deadline: short, wantErr: true},
+		{name: "within both", timeout: ClientTimeoutConfig{RPC: long}, deadline: long},
+		{
+			name:     "prefer context: looser deadline wins",
+			timeout:  ClientTimeoutConfig{RPC: short, PreferContextDeadline: true},
+			deadline: long,
+		},
+		{
+			name:    "prefer context: rpc timeout without deadline",
+			timeout: ClientTimeoutConfig{RPC: short, PreferContextDeadline: true},
+			wantErr: true,
+		},
+		{
+			name:     "prefer context: tighter deadline wins",
+			timeout:  ClientTimeoutConfig{RPC: long, PreferContextDeadline: true},
+			deadline: short,
+			wantErr:  true,
+		},
+	}
+
+	addr := startEchoServer(t, &ServerConfig{Name: "echo"}, echoHandler{
+		reply: func(context.Context, string) string {
+			time.Sleep(handler)
+			return `{"msg":"done"}`
+		},
+	})
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cli := newEchoClient(t, &ClientConfig{ServiceName: "echo", Endpoints: []string{addr}, Timeout: tt.timeout})
+
+			ctx := context.Background()
+			if tt.deadline > 0 {
+				var cancel context.CancelFunc
+				ctx, cancel = context.WithTimeout(ctx, tt.deadline)
+				defer cancel()
+			}
+			start := time.Now()
+			_, err := cli.GenericCall(ctx, "Echo", `{"msg":"hi"}`)
+			elapsed := time.Since(start)
+
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("GenericCall() error = %v, want error %v", err, tt.wantErr)
+			}
+			// A failed call ends at the tighter bound, well before the handler returns
+			if tt.wantErr && elapsed >= handler {
+				t.Errorf("GenericCall() failed after %v, want before %v", elapsed, handler)
+			}
+		})
+	}
+}
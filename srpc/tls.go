@@ -0,0 +1,211 @@
+package srpc
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"sync/atomic"
+	"time"
+
+	"github.com/cloudwego/gopkg/bufiox"
+	"github.com/cloudwego/kitex/pkg/remote"
+)
+
+// TLSConfig represents TLS settings for RPC servers and clients.
+//
+// On a server, CertFile and KeyFile are required; setting CAFile enables
+// mutual TLS by requiring and verifying client certificates.
+// On a client, CAFile verifies the server certificate; setting CertFile and
+// KeyFile presents a client certificate for mutual TLS.
+type TLSConfig struct {
+	// Enabled enables TLS. Default: false
+	Enabled bool `yaml:"enabled,omitempty" json:"enabled,omitempty"`
+	// CertFile is the PEM-encoded certificate file.
+	CertFile string `yaml:"certFile,omitempty" json:"certFile,omitempty"`
+	// KeyFile is the PEM-encoded private key file.
+	KeyFile string `yaml:"keyFile,omitempty" json:"keyFile,omitempty"`
+	// CAFile is the PEM-encoded CA bundle used to verify the peer.
+	CAFile string `yaml:"caFile,omitempty" json:"caFile,omitempty"`
+	// ServerName overrides the server name used for verification (client only).
+	ServerName string `yaml:"serverName,omitempty" json:"serverName,omitempty"`
+	// InsecureSkipVerify disables server certificate verification (client only).
+	InsecureSkipVerify bool `yaml:"insecureSkipVerify,omitempty" json:"insecureSkipVerify,omitempty"`
+}
+
+// Validate checks that the files required for the enabled mode are set and
+// that the certificate, key and CA bundle can be loaded.
+func (c *TLSConfig) Validate() error {
+	if !c.Enabled {
+		return nil
+	}
+	if (c.CertFile == "") != (c.KeyFile == "") {
+		return errors.New("tls: certFile and keyFile must be set together")
+	}
+	if c.CertFile != "" {
+		if _, err := tls.LoadX509KeyPair(c.CertFile, c.KeyFile); err != nil {
+			return fmt.Errorf("tls: load key pair: %w", err)
+		}
+	}
+	if c.CAFile != "" {
+		if _, err := loadCertPool(c.CAFile); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ServerTLSConfig builds a *tls.Config for a server.
+func (c *TLSConfig) ServerTLSConfig() (*tls.Config, error) {
+	if c.CertFile == "" || c.KeyFile == "" {
+		return nil, errors.New("tls: server requires certFile and keyFile")
+	}
+	cert, err := tls.LoadX509KeyPair(c.CertFile, c.KeyFile)
+	if err != nil {
+		return nil, fmt.Errorf("tls: load key pair: %w", err)
+	}
+
+	cfg := &tls.Config{
+		Certificates: []tls.Certificate{cert},
+		MinVersion:   tls.VersionTLS12,
+	}
+	if c.CAFile != "" {
+		pool, err := loadCertPool(c.CAFile)
+		if err != nil {
+			return nil, err
+		}
+		cfg.ClientCAs = pool
+		cfg.ClientAuth = tls.RequireAndVerifyClientCert
+	}
+	return cfg, nil
+}
+
+// ClientTLSConfig builds a *tls.Config for a client.
+func (c *TLSConfig) ClientTLSConfig() (*tls.Config, error) {
+	cfg := &tls.Config{
+		ServerName:         c.ServerName,
+		InsecureSkipVerify: c.InsecureSkipVerify, //nolint:gosec // explicitly configured
+		MinVersion:         tls.VersionTLS12,
+	}
+	if c.CAFile != "" {
+		pool, err := loadCertPool(c.CAFile)
+		if err != nil {
+			return nil, err
+		}
+		cfg.RootCAs = pool
+	}
+	if c.CertFile != "" && c.KeyFile != "" {
+		cert, err := tls.LoadX509KeyPair(c.CertFile, c.KeyFile)
+		if err != nil {
+			return nil, fmt.Errorf("tls: load key pair: %w", err)
+		}
+		cfg.Certificates = []tls.Certificate{cert}
+	}
+	return cfg, nil
+}
+
+// loadCertPool reads a PEM-encoded CA bundle.
+func loadCertPool(file string) (*x509.CertPool, error) {
+	pem, err := os.ReadFile(file)
+	if err != nil {
+		return nil, fmt.Errorf("tls: read CA file: %w", err)
+	}
+	pool := x509.NewCertPool()
+	if !pool.AppendCertsFromPEM(pem) {
+		return nil, fmt.Errorf("tls: no certificates found in %s", file)
+	}
+	return pool, nil
+}
+
+// tlsTransServerFactory wraps a TransServerFactory so that its listener
+// terminates TLS. It must be paired with the Go net transport, since netpoll
+// reads from the raw socket.
+type tlsTransServerFactory struct {
+	factory remote.TransServerFactory
+	config  *tls.Config
+}
+
+// NewTransServer implements the remote.TransServerFactory interface.
+func (f *tlsTransServerFactory) NewTransServer(
+	opt *remote.ServerOption,
+	h remote.ServerTransHandler,
+) remote.TransServer {
+	return &tlsTransServer{
+		TransServer: f.factory.NewTransServer(opt, h),
+		config:      f.config,
+	}
+}
+
+type tlsTransServer struct {
+	remote.TransServer
+	config *tls.Config
+}
+
+// CreateListener wraps the underlying listener with TLS.
+func (s *tlsTransServer) CreateListener(addr net.Addr) (net.Listener, error) {
+	ln, err := s.TransServer.CreateListener(addr)
+	if err != nil {
+		return nil, err
+	}
+	return tls.NewListener(ln, s.config), nil
+}
+
+// tlsDialer dials TLS connections usable by the Go net client transport.
+type tlsDialer struct {
+	config *tls.Config
+}
+
+// DialTimeout implements the remote.Dialer interface.
+func (d *tlsDialer) DialTimeout(network, address string, timeout time.Duration) (net.Conn, error) {
+	dialer := &tls.Dialer{
+		NetDialer: &net.Dialer{Timeout: timeout},
+		Config:    d.config,
+	}
+	conn, err := dialer.Dial(network, address)
+	if err != nil {
+		return nil, err
+	}
+	return &tlsConn{
+		Conn: conn,
+		r:    bufiox.NewDefaultReader(conn),
+		w:    bufiox.NewDefaultWriter(conn),
+	}, nil
+}
+
+// tlsConn exposes buffered reader/writer accessors expected by the Go net
+// transport on top of a TLS connection. Kitex has no client TLS option for
+// TCP transports, and gonet reads through these accessors on the
+// connections its dialer returns; the loopback test in tls_test.go guards
+// that contract.
+type tlsConn struct {
+	net.Conn
+	r      *bufiox.DefaultReader
+	w      *bufiox.DefaultWriter
+	closed uint32
+}
+
+// Reader returns the buffered reader.
+func (c *tlsConn) Reader() *bufiox.DefaultReader {
+	return c.r
+}
+
+// Writer returns the buffered writer.
+func (c *tlsConn) Writer() *bufiox.DefaultWriter {
+	return c.w
+}
+
+// Read reads through the buffered reader.
+func (c *tlsConn) Read(b []byte) (int, error) {
+	return c.r.Read(b)
+}
+
+// Close releases the reader and closes the connection once.
+func (c *tlsConn) Close() error {
+	if atomic.CompareAndSwapUint32(&c.closed, 0, 1) {
+		_ = c.r.Release(nil)
+		return c.Conn.Close()
+	}
+	return nil
+}
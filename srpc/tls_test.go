@@ -0,0 +1,272 @@
+package srpc
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/cloudwego/kitex/client/genericclient"
+	"github.com/cloudwego/kitex/pkg/generic"
+	"github.com/cloudwego/kitex/server/genericserver"
+)
+
+// testPKI holds the PEM files of a CA and the certificates it signed.
+type testPKI struct {
+	caFile, serverCert, serverKey, clientCert, clientKey string
+}
+
+// newTestPKI writes a CA, a server certificate for 127.0.0.1 and a client
+// certificate to dir.
+func newTestPKI(t *testing.T, dir string) testPKI {
+	t.Helper()
+	caKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	caTmpl := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "test ca"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  true,
+		KeyUsage:              x509.KeyUsageCertSign,
+		BasicConstraintsValid: true,
+	}
+	caDER, err := x509.CreateCertificate(rand.Reader, caTmpl, caTmpl, &caKey.PublicKey, caKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	caCert, err := x509.ParseCertificate(caDER)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	issue := func(serial int64, name string, usage x509.ExtKeyUsage) (string, string) {
+		key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+		if err != nil {
+			t.Fatal(err)
+		}
+		tmpl := &x509.Certificate{
+			SerialNumber: big.NewInt(serial),
+			Subject:      pkix.Name{CommonName: name},
+			NotBefore:    time.Now().Add(-time.Hour),
+			NotAfter:     time.Now().Add(time.Hour),
+			KeyUsage:     x509.KeyUsageDigitalSignature,
+			ExtKeyUsage:  []x509.ExtKeyUsage{usage},
+			IPAddresses:  []net.IP{net.ParseIP("127.0.0.1")},
+		}
+		der, err := x509.CreateCertificate(rand.Reader, tmpl, caCert, &key.PublicKey, caKey)
+		if err != nil {
+			t.Fatal(err)
+		}
+		keyDER, err := x509.MarshalECPrivateKey(key)
+		if err != nil {
+			t.Fatal(err)
+		}
+		certFile := writePEM(t, dir, name+".crt", "CERTIFICATE", der)
+		keyFile := writePEM(t, dir, name+".key", "EC PRIVATE KEY", keyDER)
+		return certFile, keyFile
+	}
+
+	pki := testPKI{caFile: writePEM(t, dir, "ca.crt", "CERTIFICATE", caDER)}
+	pki.serverCert, pki.serverKey = issue(2, "server", x509.ExtKeyUsageServerAuth)
+	pki.clientCert, pki.clientKey = issue(3, "client", x509.ExtKeyUsageClientAuth)
+	return pki
+}
+
+// writePEM writes a PEM block to dir/name and returns its path.
+func writePEM(t *testing.T, dir, name, blockType string, der []byte) string {
+	t.Helper()
+	path := filepath.Join(dir, name)
+	if err := os.WriteFile(path, pem.EncodeToMemory(&pem.Block{Type: blockType, Bytes: der}), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestTLSLoopback(t *testing.T) {
+	pki := newTestPKI(t, t.TempDir())
+	other := newTestPKI(t, t.TempDir())
+
+	tests := []struct {
+		name    string
+		client  TLSConfig
+		wantErr bool
+	}{
+		{
+			name: "mutual TLS",
+			client: TLSConfig{
+				Enabled: true, CAFile: pki.caFile, CertFile: pki.clientCert, KeyFile: pki.clientKey,
+			},
+		},
+		{
+			name:    "no client certificate",
+			client:  TLSConfig{Enabled: true, CAFile: pki.caFile},
+			wantErr: true,
+		},
+		{
+			name: "client certificate from another CA",
+			client: TLSConfig{
+				Enabled: true, CAFile: pki.caFile, CertFile: other.clientCert, KeyFile: other.clientKey,
+			},
+			wantErr: true,
+		},
+		{
+			name: "server certificate from an untrusted CA",
+			client: TLSConfig{
+				Enabled: true, CAFile: other.caFile, CertFile: pki.clientCert, KeyFile: pki.clientKey,
+			},
+			wantErr: true,
+		},
+		{
+			name:    "plaintext client",
+			client:  TLSConfig{},
+			wantErr: true,
+		},
+	}
+
+	addr := startEchoServer(t, &ServerConfig{
+		Name: "echo",
+		TLS:  TLSConfig{Enabled: true, CertFile: pki.serverCert, KeyFile: pki.serverKey, CAFile: pki.caFile},
+	}, echoHandler{
+		reply: func(_ context.Context, request string) string { return request },
+	})
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cli := newEchoClient(t, &ClientConfig{
+				ServiceName: "echo",
+				Endpoints:   []string{addr},
+				TLS:         tt.client,
+				Timeout:     ClientTimeoutConfig{RPC: 2 * time.Second},
+			})
+
+			resp, err := cli.GenericCall(context.Background(), "Echo", `{"msg":"hi"}`)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("call succeeded with response %v, want an error", resp)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if resp != `{"msg":"hi"}` {
+				t.Errorf("response = %v, want the echoed request", resp)
+			}
+		})
+	}
+}
+
+func TestTLSConfigValidate(t *testing.T) {
+	dir := t.TempDir()
+	pki := newTestPKI(t, dir)
+	notPEM := filepath.Join(dir, "not.pem")
+	if err := os.WriteFile(notPEM, []byte("not a certificate"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	missing := filepath.Join(dir, "missing.pem")
+
+	tests := []struct {
+		name    string
+		cfg     TLSConfig
+		wantErr bool
+	}{
+		{name: "disabled", cfg: TLSConfig{CertFile: missing}},
+		{
+			name: "key pair and CA",
+			cfg:  TLSConfig{Enabled: true, CertFile: pki.serverCert, KeyFile: pki.serverKey, CAFile: pki.caFile},
+		},
+		{name: "CA only", cfg: TLSConfig{Enabled: true, CAFile: pki.caFile}},
+		{name: "cert without key", cfg: TLSConfig{Enabled: true, CertFile: pki.serverCert}, wantErr: true},
+		{name: "missing cert file", cfg: TLSConfig{Enabled: true, CertFile: missing, KeyFile: pki.serverKey}, wantErr: true},
+		{
+			name:    "mismatched key",
+			cfg:     TLSConfig{Enabled: true, CertFile: pki.serverCert, KeyFile: pki.clientKey},
+			wantErr: true,
+		},
+		{name: "missing CA file", cfg: TLSConfig{Enabled: true, CAFile: missing}, wantErr: true},
+		{name: "CA file without certificates", cfg: TLSConfig{Enabled: true, CAFile: notPEM}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.cfg.Validate(); (err != nil) != tt.wantErr {
+				t.Errorf("Validate() = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestTLSFailClosed(t *testing.T) {
+	tests := []struct {
+		name string
+		// setup returns the TLS config and is given the PKI directory
+		setup func(t *testing.T, dir string) TLSConfig
+		// removeAfterValidate deletes the PKI between Validate and Build
+		removeAfterValidate bool
+	}{
+		{
+			name: "missing files",
+			setup: func(t *testing.T, dir string) TLSConfig {
+				return TLSConfig{
+					Enabled: true, CAFile: filepath.Join(dir, "ca.crt"),
+					CertFile: filepath.Join(dir, "server.crt"), KeyFile: filepath.Join(dir, "server.key"),
+				}
+			},
+		},
+		{
+			name: "files removed before build",
+			setup: func(t *testing.T, dir string) TLSConfig {
+				pki := newTestPKI(t, dir)
+				return TLSConfig{Enabled: true, CAFile: pki.caFile, CertFile: pki.serverCert, KeyFile: pki.serverKey}
+			},
+			removeAfterValidate: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			tlsCfg := tt.setup(t, dir)
+
+			srvBuilder := NewServerBuilder(&ServerConfig{Name: "echo", Host: "127.0.0.1", Port: freePort(t), TLS: tlsCfg})
+			cliBuilder := NewClientBuilder(&ClientConfig{ServiceName: "echo", Endpoints: []string{"127.0.0.1:1"}, TLS: tlsCfg})
+			if tt.removeAfterValidate {
+				if srvBuilder.Err() != nil || cliBuilder.Err() != nil {
+					t.Fatalf("valid TLS config rejected: %v, %v", srvBuilder.Err(), cliBuilder.Err())
+				}
+				if err := os.RemoveAll(dir); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			svr := genericserver.NewServer(&nopService{}, generic.BinaryThriftGeneric(), srvBuilder.Build()...)
+			if err := svr.Run(); err == nil {
+				t.Error("server started without its TLS config")
+			}
+			if srvBuilder.Err() == nil {
+				t.Error("server builder Err() = nil")
+			}
+
+			cli, err := genericclient.NewClient("echo", generic.BinaryThriftGeneric(), cliBuilder.Build()...)
+			if err == nil {
+				_ = cli.Close()
+				t.Error("client created without its TLS config")
+			}
+			if cliBuilder.Err() == nil {
+				t.Error("client builder Err() = nil")
+			}
+		})
+	}
+}
@@ -0,0 +1,142 @@
+package srpc
+
+import (
+	"context"
+	stderrors "errors"
+	"strconv"
+	"sync"
+
+	"github.com/cloudwego/kitex/pkg/kerrors"
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/ssgohq/goten-core/logx"
+	"github.com/ssgohq/goten-core/metric"
+	"github.com/ssgohq/goten-core/srpc/errors"
+)
+
+var (
+	typedClientErrorsOnce sync.Once
+	typedClientErrors     *metric.CounterVec
+)
+
+func initTypedClientErrors() {
+	typedClientErrorsOnce.Do(func() {
+		typedClientErrors = metric.NewCounterVec(prometheus.CounterOpts{
+			Namespace: "goten",
+			Subsystem: "rpc_client",
+			Name:      "call_errors_total",
+			Help:      "Total number of failed calls made through typed clients, by error code",
+		}, []string{"service", "method", "code"})
+	})
+}
+
+// TypedClient wraps a generated Kitex client so that calls made through Call
+// return *errors.Error values, with biz status errors decoded and transport
+// failures mapped to codes.
+type TypedClient[T any] struct {
+	client  T
+	service string
+	opts    typedClientOptions
+}
+
+type typedClientOptions struct {
+	logErrors   bool
+	countErrors bool
+}
+
+// TypedClientOption configures a TypedClient.
+type TypedClientOption func(*typedClientOptions)
+
+// WithErrorLogging logs failed calls at warn level, with the service,
+// method and error code.
+func WithErrorLogging() TypedClientOption {
+	return func(o *typedClientOptions) {
+		o.logErrors = true
+	}
+}
+
+// WithErrorMetrics counts failed calls in goten_rpc_client_call_errors_total
+// by service, method and error code.
+func WithErrorMetrics() TypedClientOption {
+	return func(o *typedClientOptions) {
+		o.countErrors = true
+	}
+}
+
+// NewTypedClient wraps cli, a client of the named service.
+//
+// Example:
+//
+//	cli, err := userservice.NewClient("user-rpc", srpc.NewClientBuilder(&c.UserRpc).Build()...)
+//	if err != nil {
+//	    return err
+//	}
+//	users := srpc.NewTypedClient("user-rpc", cli, srpc.WithErrorLogging())
+//
+//	user, err := srpc.Call(ctx, users, "GetUser", func(ctx context.Context, cli userservice.Client) (*user.User, error) {
+//	    return cli.GetUser(ctx, &user.GetUserReq{Id: id})
+//	})
+//	if errors.IsNotFound(err) {
+//	    // ...
+//	}
+func NewTypedClient[T any](service string, cli T, opts ...TypedClientOption) *TypedClient[T] {
+	c := &TypedClient[T]{client: cli, service: service}
+	for _, opt := range opts {
+		opt(&c.opts)
+	}
+	if c.opts.countErrors {
+		initTypedClientErrors()
+	}
+	return c
+}
+
+// Client returns the wrapped Kitex client.
+func (c *TypedClient[T]) Client() T {
+	return c.client
+}
+
+// Call invokes fn with the wrapped client and returns its result. A non-nil
+// error is always an *errors.Error: errors returned by the server keep their
+// code, message and details, timeouts become CodeDeadlineExceeded,
+// cancellations CodeCancelled and other failures CodeUnavailable, wrapping
+// the original error. The method name is used in logs and metrics only.
+func Call[T, R any](
+	ctx context.Context,
+	c *TypedClient[T],
+	method string,
+	fn func(context.Context, T) (R, error),
+) (R, error) {
+	resp, err := fn(ctx, c.client)
+	if err == nil {
+		return resp, nil
+	}
+
+	rpcErr := toRPCError(err)
+	if c.opts.logErrors {
+		logx.Ctx(ctx).Warnw("RPC call failed",
+			"service", c.service,
+			"method", method,
+			"code", rpcErr.Code,
+			"error", rpcErr.Error(),
+		)
+	}
+	if c.opts.countErrors {
+		typedClientErrors.Inc(c.service, method, strconv.Itoa(int(rpcErr.Code)))
+	}
+	return resp, rpcErr
+}
+
+// toRPCError converts a client call error to an *errors.Error.
+func toRPCError(err error) *errors.Error {
+	if e := errors.FromError(err); e != nil {
+		return e
+	}
+	switch {
+	case stderrors.Is(err, context.Canceled):
+		return errors.Wrap(err, errors.CodeCancelled, "call cancelled")
+	case kerrors.IsTimeoutError(err), stderrors.Is(err, context.DeadlineExceeded):
+		return errors.Wrap(err, errors.CodeDeadlineExceeded, "call timed out")
+	default:
+		return errors.Wrap(err, errors.CodeUnavailable, "call failed")
+	}
+}
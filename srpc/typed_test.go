@@ -0,0 +1,135 @@
+package srpc
+
+import (
+	"context"
+	stderrors "errors"
+	"fmt"
+	"testing"
+
+	"github.com/cloudwego/kitex/pkg/kerrors"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+
+	"github.com/ssgohq/goten-core/logx"
+	"github.com/ssgohq/goten-core/srpc/errors"
+)
+
+// fakeUserClient stands in for a generated Kitex client.
+type fakeUserClient struct {
+	err error
+}
+
+func (c fakeUserClient) GetUser(context.Context, string) (string, error) {
+	if c.err != nil {
+		return "", c.err
+	}
+	return "alice", nil
+}
+
+func TestCallErrors(t *testing.T) {
+	tests := []struct {
+		name        string
+		err         error
+		wantCode    int32
+		wantMessage string
+		wantDetail  any
+		wantWrapped error
+	}{
+		{name: "success"},
+		{
+			name:        "biz status error",
+			err:         errors.ToKitexError(errors.NotFound("user not found").WithDetail("id", "42")),
+			wantCode:    errors.CodeNotFound,
+			wantMessage: "user not found",
+			wantDetail:  "42",
+		},
+		{
+			name:        "biz status error without details",
+			err:         kerrors.NewBizStatusError(errors.CodeInvalidArgument, "bad id"),
+			wantCode:    errors.CodeInvalidArgument,
+			wantMessage: "bad id",
+		},
+		{
+			name:        "rpc timeout",
+			err:         kerrors.ErrRPCTimeout.WithCause(stderrors.New("timer fired")),
+			wantCode:    errors.CodeDeadlineExceeded,
+			wantMessage: "call timed out",
+		},
+		{
+			name:        "context deadline",
+			err:         fmt.Errorf("send: %w", context.DeadlineExceeded),
+			wantCode:    errors.CodeDeadlineExceeded,
+			wantMessage: "call timed out",
+			wantWrapped: context.DeadlineExceeded,
+		},
+		{
+			name:        "context cancelled",
+			err:         context.Canceled,
+			wantCode:    errors.CodeCancelled,
+			wantMessage: "call cancelled",
+			wantWrapped: context.Canceled,
+		},
+		{
+			name:        "transport failure",
+			err:         kerrors.ErrNoDestAddress,
+			wantCode:    errors.CodeUnavailable,
+			wantMessage: "call failed",
+			wantWrapped: kerrors.ErrNoDestAddress,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			core, logs := observer.New(zapcore.WarnLevel)
+			ctx := logx.WithContext(context.Background(), zap.New(core).Sugar())
+
+			const service = "user-rpc"
+			users := NewTypedClient(service, fakeUserClient{err: tt.err}, WithErrorLogging(), WithErrorMetrics())
+			method := "GetUser/" + tt.name
+			code := fmt.Sprint(tt.wantCode)
+			// Metrics are global, so compare with a reading taken before the call
+			labels := map[string]string{"service": service, "method": method, "code": code}
+			before, _ := metricValue(t, "goten_rpc_client_call_errors_total", labels)
+
+			resp, err := Call(ctx, users, method, func(ctx context.Context, cli fakeUserClient) (string, error) {
+				return cli.GetUser(ctx, "42")
+			})
+			if tt.err == nil {
+				if err != nil || resp != "alice" {
+					t.Fatalf("Call() = %q, %v, want alice, nil", resp, err)
+				}
+				if logs.Len() != 0 {
+					t.Errorf("logged %d entries for a successful call", logs.Len())
+				}
+				return
+			}
+
+			var rpcErr *errors.Error
+			if !stderrors.As(err, &rpcErr) {
+				t.Fatalf("Call() error = %T %v, want *errors.Error", err, err)
+			}
+			if rpcErr.Code != tt.wantCode || rpcErr.Message != tt.wantMessage {
+				t.Errorf("Call() error = %d %q, want %d %q", rpcErr.Code, rpcErr.Message, tt.wantCode, tt.wantMessage)
+			}
+			if tt.wantDetail != nil && rpcErr.Details["id"] != tt.wantDetail {
+				t.Errorf("detail id = %v, want %v", rpcErr.Details["id"], tt.wantDetail)
+			}
+			if tt.wantWrapped != nil && !stderrors.Is(err, tt.wantWrapped) {
+				t.Errorf("Call() error %v does not wrap %v", err, tt.wantWrapped)
+			}
+
+			after, _ := metricValue(t, "goten_rpc_client_call_errors_total", labels)
+			if got := after - before; got != 1 {
+				t.Errorf("call errors = %v, want 1", got)
+			}
+			entries := logs.FilterMessage("RPC call failed").All()
+			if len(entries) != 1 {
+				t.Fatalf("logged %d failures, want 1", len(entries))
+			}
+			if got := entries[0].ContextMap()["code"]; fmt.Sprint(got) != code {
+				t.Errorf("logged code = %v, want %s", got, code)
+			}
+		})
+	}
+}
@@ -0,0 +1,76 @@
+package srpc
+
+import (
+	"context"
+	"testing"
+
+	"go.opentelemetry.io/otel"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	oteltrace "go.opentelemetry.io/otel/trace"
+
+	"github.com/ssgohq/goten-core/trace"
+)
+
+// suppressingSampler samples every span except those started from a context
+// marked with trace.WithSuppressed, like the sampler of trace.StartAgent.
+type suppressingSampler struct{}
+
+func (suppressingSampler) ShouldSample(p sdktrace.SamplingParameters) sdktrace.SamplingResult {
+	if p.ParentContext != nil && trace.IsSuppressed(p.ParentContext) {
+		return sdktrace.SamplingResult{Decision: sdktrace.Drop}
+	}
+	return sdktrace.AlwaysSample().ShouldSample(p)
+}
+
+func (suppressingSampler) Description() string { return "suppressingSampler" }
+
+func TestClientUntracedMethods(t *testing.T) {
+	tests := []struct {
+		name            string
+		untraced        []string
+		wantClientSpans int
+	}{
+		{name: "traced", wantClientSpans: 1},
+		{name: "other method opted out", untraced: []string{"Ping"}, wantClientSpans: 1},
+		{name: "opted out", untraced: []string{"Echo"}, wantClientSpans: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			recorder := tracetest.NewSpanRecorder()
+			tp := sdktrace.NewTracerProvider(
+				sdktrace.WithSampler(suppressingSampler{}),
+				sdktrace.WithSpanProcessor(recorder),
+			)
+			prev := otel.GetTracerProvider()
+			otel.SetTracerProvider(tp)
+			t.Cleanup(func() {
+				otel.SetTracerProvider(prev)
+				_ = tp.Shutdown(context.Background())
+			})
+
+			addr := startEchoServer(t, &ServerConfig{Name: "echo"}, echoHandler{
+				reply: func(context.Context, string) string { return `{"msg":"ok"}` },
+			})
+			cli := newEchoClient(t, &ClientConfig{
+				ServiceName:     "echo",
+				Endpoints:       []string{addr},
+				UntracedMethods: tt.untraced,
+			})
+			if _, err := cli.GenericCall(context.Background(), "Echo", `{"msg":"hi"}`); err != nil {
+				t.Fatal(err)
+			}
+
+			var clientSpans int
+			for _, span := range recorder.Ended() {
+				if span.SpanKind() == oteltrace.SpanKindClient {
+					clientSpans++
+				}
+			}
+			if clientSpans != tt.wantClientSpans {
+				t.Errorf("client spans = %d, want %d", clientSpans, tt.wantClientSpans)
+			}
+		})
+	}
+}
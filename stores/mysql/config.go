@@ -3,9 +3,11 @@ package mysql
 
 import (
 	"database/sql"
+	"fmt"
+	"regexp"
 	"time"
 
-	_ "github.com/go-sql-driver/mysql"
+	"github.com/go-sql-driver/mysql"
 )
 
 // Config represents MySQL connection configuration.
@@ -25,8 +27,22 @@ type Config struct {
 
 	// ConnMaxIdleTime is the maximum idle connection lifetime, default 30 minutes.
 	ConnMaxIdleTime time.Duration `yaml:"connMaxIdleTime,omitempty" json:"connMaxIdleTime,omitempty"`
+
+	// Params are session variables set on every new connection with
+	// "SET name = value", e.g. sql_mode, time_zone or collation_connection.
+	// Values are sent verbatim, so string values must be quoted:
+	//
+	//	params:
+	//	  time_zone: "'+00:00'"
+	//	  sql_mode: "'STRICT_ALL_TABLES'"
+	//
+	// They override session variables given in the DSN.
+	Params map[string]string `yaml:"params,omitempty" json:"params,omitempty"`
 }
 
+// paramName matches valid session variable names.
+var paramName = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
 // IsEnabled returns true if MySQL is configured.
 func (c Config) IsEnabled() bool {
 	return c.DSN != ""
@@ -48,6 +64,25 @@ func (c *Config) SetDefaults() {
 	}
 }
 
+// Validate checks the pool settings for invalid values.
+func (c Config) Validate() error {
+	if c.MaxOpenConns < 0 || c.MaxIdleConns < 0 {
+		return fmt.Errorf("mysql: maxOpenConns and maxIdleConns must be >= 0")
+	}
+	if c.MaxOpenConns > 0 && c.MaxIdleConns > c.MaxOpenConns {
+		return fmt.Errorf("mysql: maxIdleConns (%d) must not exceed maxOpenConns (%d)", c.MaxIdleConns, c.MaxOpenConns)
+	}
+	if c.ConnMaxLifetime < 0 || c.ConnMaxIdleTime < 0 {
+		return fmt.Errorf("mysql: connMaxLifetime and connMaxIdleTime must be >= 0")
+	}
+	for name := range c.Params {
+		if !paramName.MatchString(name) {
+			return fmt.Errorf("mysql: invalid session variable name %q", name)
+		}
+	}
+	return nil
+}
+
 // New creates a new MySQL connection pool.
 func New(c Config) (*sql.DB, error) {
 	if !c.IsEnabled() {
@@ -55,8 +90,11 @@ func New(c Config) (*sql.DB, error) {
 	}
 
 	c.SetDefaults()
+	if err := c.Validate(); err != nil {
+		return nil, err
+	}
 
-	db, err := sql.Open("mysql", c.DSN)
+	db, err := c.open()
 	if err != nil {
 		return nil, err
 	}
@@ -75,6 +113,38 @@ func New(c Config) (*sql.DB, error) {
 	return db, nil
 }
 
+// open opens the pool, applying Params through the driver, which sets them
+// as session variables whenever it establishes a connection.
+func (c Config) open() (*sql.DB, error) {
+	if len(c.Params) == 0 {
+		return sql.Open("mysql", c.DSN)
+	}
+	cfg, err := c.driverConfig()
+	if err != nil {
+		return nil, err
+	}
+	connector, err := mysql.NewConnector(cfg)
+	if err != nil {
+		return nil, err
+	}
+	return sql.OpenDB(connector), nil
+}
+
+// driverConfig parses the DSN and merges Params into its session variables.
+func (c Config) driverConfig() (*mysql.Config, error) {
+	cfg, err := mysql.ParseDSN(c.DSN)
+	if err != nil {
+		return nil, fmt.Errorf("mysql: parse dsn: %w", err)
+	}
+	if cfg.Params == nil {
+		cfg.Params = make(map[string]string, len(c.Params))
+	}
+	for name, value := range c.Params {
+		cfg.Params[name] = value
+	}
+	return cfg, nil
+}
+
 // MustNew creates a new MySQL connection pool or panics.
 func MustNew(c Config) *sql.DB {
 	db, err := New(c)
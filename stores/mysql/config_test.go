@@ -0,0 +1,125 @@
+package mysql
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestConfigValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		cfg     Config
+		wantErr bool
+	}{
+		{name: "valid", cfg: Config{MaxOpenConns: 10, MaxIdleConns: 5}},
+		{name: "negative max open conns", cfg: Config{MaxOpenConns: -1}, wantErr: true},
+		{name: "idle above open conns", cfg: Config{MaxOpenConns: 5, MaxIdleConns: 10}, wantErr: true},
+		{name: "negative conn max lifetime", cfg: Config{ConnMaxLifetime: -time.Second}, wantErr: true},
+		{name: "valid params", cfg: Config{Params: map[string]string{"time_zone": "'+00:00'", "_x1": "1"}}},
+		{name: "param with spaces", cfg: Config{Params: map[string]string{"sql_mode = ''; DROP": "1"}}, wantErr: true},
+		{name: "param with leading digit", cfg: Config{Params: map[string]string{"1x": "1"}}, wantErr: true},
+		{name: "empty param name", cfg: Config{Params: map[string]string{"": "1"}}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.cfg.Validate(); (err != nil) != tt.wantErr {
+				t.Errorf("Validate() = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestConfigSetDefaults(t *testing.T) {
+	defaults := Config{MaxOpenConns: 10, MaxIdleConns: 5, ConnMaxLifetime: time.Hour, ConnMaxIdleTime: 30 * time.Minute}
+	explicit := Config{MaxOpenConns: 20, MaxIdleConns: 2, ConnMaxLifetime: time.Minute, ConnMaxIdleTime: time.Second}
+
+	tests := []struct {
+		name string
+		cfg  Config
+		want Config
+	}{
+		{name: "empty", cfg: Config{}, want: defaults},
+		{name: "explicit", cfg: explicit, want: explicit},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := tt.cfg
+			c.SetDefaults()
+			if got, want := fmt.Sprintf("%+v", c), fmt.Sprintf("%+v", tt.want); got != want {
+				t.Fatalf("SetDefaults() = %s, want %s", got, want)
+			}
+			c.SetDefaults()
+			if got, want := fmt.Sprintf("%+v", c), fmt.Sprintf("%+v", tt.want); got != want {
+				t.Errorf("second SetDefaults() = %s, want %s", got, want)
+			}
+		})
+	}
+}
+
+func TestOpenParams(t *testing.T) {
+	tests := []struct {
+		name       string
+		dsn        string
+		params     map[string]string
+		wantParams map[string]string
+		wantErr    bool
+	}{
+		{name: "no params", dsn: "app:secret@tcp(127.0.0.1:3306)/orders"},
+		{
+			name:       "params",
+			dsn:        "app:secret@tcp(127.0.0.1:3306)/orders",
+			params:     map[string]string{"time_zone": "'+00:00'"},
+			wantParams: map[string]string{"time_zone": "'+00:00'"},
+		},
+		{
+			name:   "params override the dsn",
+			dsn:    "app:secret@tcp(127.0.0.1:3306)/orders?time_zone=%27Europe%2FParis%27&autocommit=1",
+			params: map[string]string{"time_zone": "'+00:00'", "sql_mode": "'STRICT_ALL_TABLES'"},
+			wantParams: map[string]string{
+				"time_zone":  "'+00:00'",
+				"sql_mode":   "'STRICT_ALL_TABLES'",
+				"autocommit": "1",
+			},
+		},
+		{name: "invalid dsn", dsn: "app@orders", wantErr: true},
+		{
+			name:    "invalid dsn with params",
+			dsn:     "app@orders",
+			params:  map[string]string{"time_zone": "'+00:00'"},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := Config{DSN: tt.dsn, Params: tt.params}
+
+			// open does not connect, so no server is needed
+			db, err := c.open()
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("open() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if db != nil {
+				_ = db.Close()
+			}
+			if tt.wantErr || len(tt.params) == 0 {
+				return
+			}
+
+			cfg, err := c.driverConfig()
+			if err != nil {
+				t.Fatalf("driverConfig() error = %v", err)
+			}
+			if !reflect.DeepEqual(cfg.Params, tt.wantParams) {
+				t.Errorf("connector params = %v, want %v", cfg.Params, tt.wantParams)
+			}
+			if cfg.User != "app" || cfg.Passwd != "secret" || cfg.Addr != "127.0.0.1:3306" || cfg.DBName != "orders" {
+				t.Errorf("connector config = %s, want the DSN's user, address and database", cfg.FormatDSN())
+			}
+		})
+	}
+}
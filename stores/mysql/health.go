@@ -0,0 +1,28 @@
+package mysql
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/ssgohq/goten-core/lifecycle"
+)
+
+// HealthCheck returns a health check that pings the MySQL database and
+// reports it up or down, with the ping latency and any error as details.
+// The ping is bounded by the context deadline, and by 5s when the
+// context has none.
+// It also fits connections created with the sqlc package.
+//
+// Example:
+//
+//	healthMgr.RegisterDetailed("mysql", mysql.HealthCheck(db))
+func HealthCheck(db *sql.DB) lifecycle.DetailedHealthCheck {
+	return lifecycle.PingHealthCheck(func(ctx context.Context) error {
+		if _, ok := ctx.Deadline(); !ok {
+			var cancel context.CancelFunc
+			ctx, cancel = context.WithTimeout(ctx, pingTimeout)
+			defer cancel()
+		}
+		return db.PingContext(ctx)
+	}, 0)
+}
@@ -0,0 +1,26 @@
+package mysql
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+
+	"github.com/ssgohq/goten-core/lifecycle"
+)
+
+func TestHealthCheckDown(t *testing.T) {
+	// Nothing listens on port 1, so the ping fails with a refused connection
+	db, err := sql.Open("mysql", "goten:goten@tcp(127.0.0.1:1)/goten?timeout=1s")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	component := HealthCheck(db)(context.Background())
+	if component.Status != lifecycle.HealthStatusDown {
+		t.Fatalf("status = %s, want down", component.Status)
+	}
+	if msg, _ := component.Details["error"].(string); msg == "" {
+		t.Fatalf("no error detail: %v", component.Details)
+	}
+}
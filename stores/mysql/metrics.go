@@ -11,9 +11,21 @@ import (
 const (
 	namespace = "goten"
 	subsystem = "mysql"
+
+	// pingTimeout bounds the reachability check made on each collection.
+	pingTimeout = 5 * time.Second
 )
 
 var (
+	// up reports whether the database answered the last ping. Pool stats are
+	// still exported when it is down, but may look like a drained pool.
+	up = prom.NewGaugeVec(prom.GaugeOpts{
+		Namespace: namespace,
+		Subsystem: subsystem,
+		Name:      "up",
+		Help:      "Whether the last MySQL ping succeeded (1) or failed (0)",
+	}, []string{"database"})
+
 	// Connection pool metrics
 	openConnections = prom.NewGaugeVec(prom.GaugeOpts{
 		Namespace: namespace,
@@ -74,6 +86,7 @@ var (
 
 func init() {
 	prom.MustRegister(
+		up,
 		openConnections,
 		inUseConnections,
 		idleConnections,
@@ -143,7 +156,7 @@ func (c *MetricsCollector) Start() {
 	c.cancel = cancel
 
 	// Collect initial stats
-	c.collect()
+	c.collect(ctx)
 
 	// Start background collection
 	go func() {
@@ -155,7 +168,7 @@ func (c *MetricsCollector) Start() {
 			case <-ctx.Done():
 				return
 			case <-ticker.C:
-				c.collect()
+				c.collect(ctx)
 			}
 		}
 	}()
@@ -168,7 +181,16 @@ func (c *MetricsCollector) Stop() {
 	}
 }
 
-func (c *MetricsCollector) collect() {
+func (c *MetricsCollector) collect(ctx context.Context) {
+	pingCtx, cancel := context.WithTimeout(ctx, pingTimeout)
+	err := c.db.PingContext(pingCtx)
+	cancel()
+	if err != nil {
+		up.WithLabelValues(c.dbName).Set(0)
+	} else {
+		up.WithLabelValues(c.dbName).Set(1)
+	}
+
 	stats := c.db.Stats()
 
 	openConnections.WithLabelValues(c.dbName).Set(float64(stats.OpenConnections))
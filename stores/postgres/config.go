@@ -3,8 +3,11 @@ package postgres
 
 import (
 	"context"
+	"fmt"
 
 	"github.com/jackc/pgx/v5/pgxpool"
+
+	"github.com/ssgohq/goten-core/internal/appname"
 )
 
 // Config represents PostgreSQL connection configuration
@@ -17,7 +20,14 @@ type Config struct {
 	MaxConns int32 `yaml:"maxConns,omitempty" json:"maxConns,omitempty"`
 
 	// MinConns is the minimum number of connections in the pool, default 2
+	// (or MaxConns if lower)
 	MinConns int32 `yaml:"minConns,omitempty" json:"minConns,omitempty"`
+
+	// AppName is set as application_name on every connection, so queries
+	// can be attributed to the service in pg_stat_activity.
+	// Default: the application_name in the DSN, else the service name set
+	// by app.New or srpc.NewServerBuilder
+	AppName string `yaml:"appName,omitempty" json:"appName,omitempty"`
 }
 
 // IsEnabled returns true if PostgreSQL is configured
@@ -25,28 +35,47 @@ func (c Config) IsEnabled() bool {
 	return c.DSN != ""
 }
 
+// SetDefaults applies default values.
+func (c *Config) SetDefaults() {
+	if c.MaxConns == 0 {
+		c.MaxConns = 10
+	}
+	if c.MinConns == 0 {
+		c.MinConns = min(2, c.MaxConns)
+	}
+}
+
+// Validate checks the pool settings for invalid values
+func (c Config) Validate() error {
+	if c.MaxConns < 0 || c.MinConns < 0 {
+		return fmt.Errorf("postgres: maxConns and minConns must be >= 0")
+	}
+	if c.MaxConns > 0 && c.MinConns > c.MaxConns {
+		return fmt.Errorf("postgres: minConns (%d) must not exceed maxConns (%d)", c.MinConns, c.MaxConns)
+	}
+	return nil
+}
+
 // New creates a new PostgreSQL connection pool
 func New(ctx context.Context, c Config) (*pgxpool.Pool, error) {
 	if !c.IsEnabled() {
 		return nil, nil
 	}
 
+	c.SetDefaults()
+	if err := c.Validate(); err != nil {
+		return nil, err
+	}
+
 	config, err := pgxpool.ParseConfig(c.DSN)
 	if err != nil {
 		return nil, err
 	}
 
-	// Apply defaults
-	if c.MaxConns > 0 {
-		config.MaxConns = c.MaxConns
-	} else {
-		config.MaxConns = 10
-	}
-	if c.MinConns > 0 {
-		config.MinConns = c.MinConns
-	} else {
-		config.MinConns = 2
-	}
+	config.ConnConfig.RuntimeParams["application_name"] =
+		appname.Postgres(c.AppName, config.ConnConfig.RuntimeParams)
+	config.MaxConns = c.MaxConns
+	config.MinConns = c.MinConns
 
 	return pgxpool.NewWithConfig(ctx, config)
 }
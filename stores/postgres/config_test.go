@@ -0,0 +1,87 @@
+package postgres
+
+import (
+	"context"
+	"testing"
+)
+
+func TestConfigValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		cfg     Config
+		wantErr bool
+	}{
+		{name: "valid", cfg: Config{MaxConns: 10, MinConns: 2}},
+		{name: "negative min conns", cfg: Config{MinConns: -1}, wantErr: true},
+		{name: "min above max conns", cfg: Config{MaxConns: 2, MinConns: 5}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.cfg.Validate(); (err != nil) != tt.wantErr {
+				t.Errorf("Validate() = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestConfigSetDefaults(t *testing.T) {
+	tests := []struct {
+		name string
+		cfg  Config
+		want Config
+	}{
+		{name: "empty", cfg: Config{}, want: Config{MaxConns: 10, MinConns: 2}},
+		{name: "small pool", cfg: Config{MaxConns: 1}, want: Config{MaxConns: 1, MinConns: 1}},
+		{name: "explicit", cfg: Config{MaxConns: 20, MinConns: 5}, want: Config{MaxConns: 20, MinConns: 5}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := tt.cfg
+			c.SetDefaults()
+			if c != tt.want {
+				t.Fatalf("SetDefaults() = %+v, want %+v", c, tt.want)
+			}
+			c.SetDefaults()
+			if c != tt.want {
+				t.Errorf("second SetDefaults() = %+v, want %+v", c, tt.want)
+			}
+		})
+	}
+}
+
+func TestNewApplicationName(t *testing.T) {
+	tests := []struct {
+		name    string
+		dsn     string
+		appName string
+		want    string
+	}{
+		{
+			name:    "configured",
+			dsn:     "postgres://u:p@127.0.0.1:1/db?application_name=from-dsn",
+			appName: "orders",
+			want:    "orders",
+		},
+		{
+			name: "dsn",
+			dsn:  "postgres://u:p@127.0.0.1:1/db?application_name=from-dsn",
+			want: "from-dsn",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pool, err := New(context.Background(), Config{DSN: tt.dsn, AppName: tt.appName, MinConns: 1, MaxConns: 1})
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer pool.Close()
+
+			if got := pool.Config().ConnConfig.RuntimeParams["application_name"]; got != tt.want {
+				t.Errorf("application_name = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
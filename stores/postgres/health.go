@@ -0,0 +1,29 @@
+package postgres
+
+import (
+	"context"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"github.com/ssgohq/goten-core/lifecycle"
+)
+
+// HealthCheck returns a health check that pings the PostgreSQL pool and
+// reports it up or down, with the ping latency and any error as details.
+// The ping is bounded by the context deadline, and by 5s when the
+// context has none.
+// It also fits connections created with the sqlc package.
+//
+// Example:
+//
+//	healthMgr.RegisterDetailed("postgres", postgres.HealthCheck(pool))
+func HealthCheck(pool *pgxpool.Pool) lifecycle.DetailedHealthCheck {
+	return lifecycle.PingHealthCheck(func(ctx context.Context) error {
+		if _, ok := ctx.Deadline(); !ok {
+			var cancel context.CancelFunc
+			ctx, cancel = context.WithTimeout(ctx, pingTimeout)
+			defer cancel()
+		}
+		return pool.Ping(ctx)
+	}, 0)
+}
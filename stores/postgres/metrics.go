@@ -11,9 +11,21 @@ import (
 const (
 	namespace = "goten"
 	subsystem = "postgres"
+
+	// pingTimeout bounds the reachability check made on each collection.
+	pingTimeout = 5 * time.Second
 )
 
 var (
+	// up reports whether the database answered the last ping. Pool stats are
+	// still exported when it is down, but may look like a drained pool.
+	up = prom.NewGaugeVec(prom.GaugeOpts{
+		Namespace: namespace,
+		Subsystem: subsystem,
+		Name:      "up",
+		Help:      "Whether the last PostgreSQL ping succeeded (1) or failed (0)",
+	}, []string{"database"})
+
 	// Connection pool metrics
 	acquiredConns = prom.NewGaugeVec(prom.GaugeOpts{
 		Namespace: namespace,
@@ -102,6 +114,7 @@ var (
 
 func init() {
 	prom.MustRegister(
+		up,
 		acquiredConns,
 		idleConns,
 		totalConns,
@@ -175,7 +188,7 @@ func (c *MetricsCollector) Start() {
 	c.cancel = cancel
 
 	// Collect initial stats
-	c.collect()
+	c.collect(ctx)
 
 	// Start background collection
 	go func() {
@@ -187,7 +200,7 @@ func (c *MetricsCollector) Start() {
 			case <-ctx.Done():
 				return
 			case <-ticker.C:
-				c.collect()
+				c.collect(ctx)
 			}
 		}
 	}()
@@ -200,7 +213,16 @@ func (c *MetricsCollector) Stop() {
 	}
 }
 
-func (c *MetricsCollector) collect() {
+func (c *MetricsCollector) collect(ctx context.Context) {
+	pingCtx, cancel := context.WithTimeout(ctx, pingTimeout)
+	err := c.pool.Ping(pingCtx)
+	cancel()
+	if err != nil {
+		up.WithLabelValues(c.dbName).Set(0)
+	} else {
+		up.WithLabelValues(c.dbName).Set(1)
+	}
+
 	stat := c.pool.Stat()
 
 	acquiredConns.WithLabelValues(c.dbName).Set(float64(stat.AcquiredConns()))
@@ -2,7 +2,9 @@
 package redis
 
 import (
+	"context"
 	"fmt"
+	"time"
 
 	"github.com/redis/go-redis/v9"
 )
@@ -20,6 +22,10 @@ type Config struct {
 
 	// DB is the database number, default 0
 	DB int `yaml:"db,omitempty" json:"db,omitempty"`
+
+	// ConnectRetries is how many times NewContext retries a failed initial
+	// ping before giving up, default 0 (a single attempt).
+	ConnectRetries int `yaml:"connectRetries,omitempty" json:"connectRetries,omitempty"`
 }
 
 // IsEnabled returns true if Redis is configured
@@ -27,6 +33,13 @@ func (c Config) IsEnabled() bool {
 	return c.Host != ""
 }
 
+// SetDefaults applies default values.
+func (c *Config) SetDefaults() {
+	if c.Port == 0 {
+		c.Port = 6379
+	}
+}
+
 // Addr returns the Redis address in host:port format
 func (c Config) Addr() string {
 	port := c.Port
@@ -36,16 +49,35 @@ func (c Config) Addr() string {
 	return fmt.Sprintf("%s:%d", c.Host, port)
 }
 
-// Options returns go-redis Options
+// Validate checks the configuration for invalid values
+func (c Config) Validate() error {
+	if c.Port < 0 || c.Port > 65535 {
+		return fmt.Errorf("redis: port %d out of range [0, 65535]", c.Port)
+	}
+	if c.DB < 0 {
+		return fmt.Errorf("redis: db must be >= 0, got %d", c.DB)
+	}
+	if c.ConnectRetries < 0 {
+		return fmt.Errorf("redis: connectRetries must be >= 0, got %d", c.ConnectRetries)
+	}
+	return nil
+}
+
+// Options returns go-redis Options.
+// Commands honor the deadline of their context, not only the 3s read and
+// write timeouts, so a stalled server cannot outlast the caller's deadline.
 func (c Config) Options() *redis.Options {
 	return &redis.Options{
-		Addr:     c.Addr(),
-		Password: c.Password,
-		DB:       c.DB,
+		Addr:                  c.Addr(),
+		Password:              c.Password,
+		DB:                    c.DB,
+		ContextTimeoutEnabled: true,
 	}
 }
 
-// New creates a new Redis client
+// New creates a new Redis client.
+// The client connects lazily, so connection errors surface on first use;
+// use NewContext to verify the connection at startup.
 func New(c Config) *redis.Client {
 	if !c.IsEnabled() {
 		return nil
@@ -55,9 +87,61 @@ func New(c Config) *redis.Client {
 
 // MustNew creates a new Redis client or panics
 func MustNew(c Config) *redis.Client {
+	if err := c.Validate(); err != nil {
+		panic(err)
+	}
 	client := New(c)
 	if client == nil {
 		panic("redis: config not enabled")
 	}
 	return client
 }
+
+// NewContext creates a new Redis client and verifies the connection with a
+// ping bounded by ctx and by a 5s per-attempt timeout. Failed pings are
+// retried ConnectRetries times with a growing backoff. On failure the client
+// is closed and the last error is returned. It returns nil, nil when Redis
+// is not enabled.
+//
+// Example:
+//
+//	rdb, err := redis.NewContext(ctx, c.Redis)
+//	if err != nil {
+//	    return fmt.Errorf("connect redis: %w", err)
+//	}
+func NewContext(ctx context.Context, c Config) (*redis.Client, error) {
+	if !c.IsEnabled() {
+		return nil, nil
+	}
+	if err := c.Validate(); err != nil {
+		return nil, err
+	}
+	client := redis.NewClient(c.Options())
+	if err := ping(ctx, client, c.ConnectRetries); err != nil {
+		_ = client.Close()
+		return nil, fmt.Errorf("redis: connect to %s: %w", c.Addr(), err)
+	}
+	return client, nil
+}
+
+// ping pings the client until it succeeds, retries are exhausted or ctx is done.
+func ping(ctx context.Context, client *redis.Client, retries int) error {
+	backoff := 200 * time.Millisecond
+	for attempt := 0; ; attempt++ {
+		pingCtx, cancel := context.WithTimeout(ctx, pingTimeout)
+		err := client.Ping(pingCtx).Err()
+		cancel()
+		if err == nil || attempt >= retries {
+			return err
+		}
+
+		timer := time.NewTimer(backoff)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return fmt.Errorf("%w (last error: %v)", ctx.Err(), err)
+		case <-timer.C:
+		}
+		backoff = min(backoff*2, 2*time.Second)
+	}
+}
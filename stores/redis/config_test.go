@@ -0,0 +1,132 @@
+package redis
+
+import (
+	"context"
+	"errors"
+	"net"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestConfigValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		cfg     Config
+		wantErr bool
+	}{
+		{name: "valid", cfg: Config{Host: "cache", Port: 6379, DB: 2}},
+		{name: "port out of range", cfg: Config{Port: 70000}, wantErr: true},
+		{name: "negative db", cfg: Config{DB: -1}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.cfg.Validate(); (err != nil) != tt.wantErr {
+				t.Errorf("Validate() = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestConfigSetDefaults(t *testing.T) {
+	tests := []struct {
+		name string
+		cfg  Config
+		want Config
+	}{
+		{name: "empty", cfg: Config{}, want: Config{Port: 6379}},
+		{name: "explicit", cfg: Config{Host: "cache", Port: 6380, DB: 2}, want: Config{Host: "cache", Port: 6380, DB: 2}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := tt.cfg
+			c.SetDefaults()
+			if c != tt.want {
+				t.Fatalf("SetDefaults() = %+v, want %+v", c, tt.want)
+			}
+			c.SetDefaults()
+			if c != tt.want {
+				t.Errorf("second SetDefaults() = %+v, want %+v", c, tt.want)
+			}
+		})
+	}
+}
+
+// stalledServer accepts connections and never replies.
+func stalledServer(t *testing.T) int {
+	t.Helper()
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = ln.Close() })
+	go func() {
+		var conns []net.Conn
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				for _, conn := range conns {
+					_ = conn.Close()
+				}
+				return
+			}
+			conns = append(conns, conn)
+		}
+	}()
+	return ln.Addr().(*net.TCPAddr).Port
+}
+
+func TestNewContextPingFailure(t *testing.T) {
+	const deadline = 300 * time.Millisecond
+
+	tests := []struct {
+		name    string
+		cfg     func(t *testing.T) Config
+		wantErr error
+	}{
+		{
+			// Nothing listens on port 1, so every attempt is refused
+			name: "refused with retries",
+			cfg: func(*testing.T) Config {
+				return Config{Host: "127.0.0.1", Port: 1, ConnectRetries: 100}
+			},
+			wantErr: context.DeadlineExceeded,
+		},
+		{
+			name: "stalled server",
+			cfg: func(t *testing.T) Config {
+				return Config{Host: "127.0.0.1", Port: stalledServer(t)}
+			},
+			wantErr: context.DeadlineExceeded,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := tt.cfg(t)
+			ctx, cancel := context.WithTimeout(context.Background(), deadline)
+			defer cancel()
+
+			start := time.Now()
+			client, err := NewContext(ctx, cfg)
+			elapsed := time.Since(start)
+
+			if client != nil {
+				t.Error("NewContext() returned a client for a failed ping")
+			}
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("NewContext() error = %v, want %v", err, tt.wantErr)
+			}
+			if want := "redis: connect to 127.0.0.1:" + strconv.Itoa(cfg.Port); !strings.HasPrefix(err.Error(), want) {
+				t.Errorf("NewContext() error = %q, want it to start with %q", err, want)
+			}
+			// Well within the 5s per-attempt timeout and the 3s read timeout
+			if elapsed > deadline+time.Second {
+				t.Errorf("NewContext() returned after %v, want about %v", elapsed, deadline)
+			}
+		})
+	}
+}
@@ -0,0 +1,28 @@
+package redis
+
+import (
+	"context"
+
+	"github.com/redis/go-redis/v9"
+
+	"github.com/ssgohq/goten-core/lifecycle"
+)
+
+// HealthCheck returns a health check that pings the Redis server and
+// reports it up or down, with the ping latency and any error as details.
+// The ping is bounded by the context deadline, and by 5s when the
+// context has none.
+//
+// Example:
+//
+//	healthMgr.RegisterDetailed("redis", redis.HealthCheck(client))
+func HealthCheck(client *redis.Client) lifecycle.DetailedHealthCheck {
+	return lifecycle.PingHealthCheck(func(ctx context.Context) error {
+		if _, ok := ctx.Deadline(); !ok {
+			var cancel context.CancelFunc
+			ctx, cancel = context.WithTimeout(ctx, pingTimeout)
+			defer cancel()
+		}
+		return client.Ping(ctx).Err()
+	}, 0)
+}
@@ -0,0 +1,24 @@
+package redis
+
+import (
+	"context"
+	"testing"
+
+	"github.com/redis/go-redis/v9"
+
+	"github.com/ssgohq/goten-core/lifecycle"
+)
+
+func TestHealthCheckDown(t *testing.T) {
+	// Nothing listens on port 1, so the ping fails with a refused connection
+	client := redis.NewClient(&redis.Options{Addr: "127.0.0.1:1", MaxRetries: -1})
+	defer client.Close()
+
+	component := HealthCheck(client)(context.Background())
+	if component.Status != lifecycle.HealthStatusDown {
+		t.Fatalf("status = %s, want down", component.Status)
+	}
+	if msg, _ := component.Details["error"].(string); msg == "" {
+		t.Fatalf("no error detail: %v", component.Details)
+	}
+}
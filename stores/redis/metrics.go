@@ -11,9 +11,21 @@ import (
 const (
 	namespace = "goten"
 	subsystem = "redis"
+
+	// pingTimeout bounds the reachability check made on each collection.
+	pingTimeout = 5 * time.Second
 )
 
 var (
+	// up reports whether the instance answered the last ping. Pool stats are
+	// still exported when it is down, but may look like a drained pool.
+	up = prom.NewGaugeVec(prom.GaugeOpts{
+		Namespace: namespace,
+		Subsystem: subsystem,
+		Name:      "up",
+		Help:      "Whether the last Redis ping succeeded (1) or failed (0)",
+	}, []string{"instance"})
+
 	// Connection pool metrics
 	hits = prom.NewGaugeVec(prom.GaugeOpts{
 		Namespace: namespace,
@@ -60,6 +72,7 @@ var (
 
 func init() {
 	prom.MustRegister(
+		up,
 		hits,
 		misses,
 		timeouts,
@@ -127,7 +140,7 @@ func (c *MetricsCollector) Start() {
 	c.cancel = cancel
 
 	// Collect initial stats
-	c.collect()
+	c.collect(ctx)
 
 	// Start background collection
 	go func() {
@@ -139,7 +152,7 @@ func (c *MetricsCollector) Start() {
 			case <-ctx.Done():
 				return
 			case <-ticker.C:
-				c.collect()
+				c.collect(ctx)
 			}
 		}
 	}()
@@ -152,7 +165,16 @@ func (c *MetricsCollector) Stop() {
 	}
 }
 
-func (c *MetricsCollector) collect() {
+func (c *MetricsCollector) collect(ctx context.Context) {
+	pingCtx, cancel := context.WithTimeout(ctx, pingTimeout)
+	err := c.client.Ping(pingCtx).Err()
+	cancel()
+	if err != nil {
+		up.WithLabelValues(c.instanceName).Set(0)
+	} else {
+		up.WithLabelValues(c.instanceName).Set(1)
+	}
+
 	stats := c.client.PoolStats()
 
 	hits.WithLabelValues(c.instanceName).Set(float64(stats.Hits))
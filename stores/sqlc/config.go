@@ -4,9 +4,13 @@ package sqlc
 import (
 	"context"
 	"database/sql"
+	"errors"
+	"fmt"
 
 	_ "github.com/go-sql-driver/mysql"
 	"github.com/jackc/pgx/v5/pgxpool"
+
+	"github.com/ssgohq/goten-core/internal/appname"
 )
 
 // DBType represents the database type
@@ -31,8 +35,15 @@ type Config struct {
 	// MaxConns is the maximum number of connections (pool size), default 10
 	MaxConns int32 `yaml:"maxConns,omitempty" json:"maxConns,omitempty"`
 
-	// MinConns is the minimum number of connections (only for PostgreSQL), default 2
+	// MinConns is the minimum number of connections (only for PostgreSQL),
+	// default 2 (or MaxConns if lower)
 	MinConns int32 `yaml:"minConns,omitempty" json:"minConns,omitempty"`
+
+	// AppName is set as application_name on PostgreSQL connections, so
+	// queries can be attributed to the service in pg_stat_activity.
+	// Default: the application_name in the DSN, else the service name set
+	// by app.New or srpc.NewServerBuilder
+	AppName string `yaml:"appName,omitempty" json:"appName,omitempty"`
 }
 
 // IsEnabled returns true if database is configured
@@ -40,6 +51,50 @@ func (c Config) IsEnabled() bool {
 	return c.DSN != ""
 }
 
+// SetDefaults applies default values.
+func (c *Config) SetDefaults() {
+	if c.MaxConns == 0 {
+		c.MaxConns = 10
+	}
+	if c.MinConns == 0 {
+		c.MinConns = min(2, c.MaxConns)
+	}
+}
+
+// Validate checks the configuration for invalid values
+func (c Config) Validate() error {
+	switch c.Type {
+	case "", DBTypePostgres, DBTypeMySQL:
+	default:
+		return fmt.Errorf("sqlc: unknown type %q (expected postgres or mysql)", c.Type)
+	}
+	if c.MaxConns < 0 || c.MinConns < 0 {
+		return fmt.Errorf("sqlc: maxConns and minConns must be >= 0")
+	}
+	if c.MaxConns > 0 && c.MinConns > c.MaxConns {
+		return fmt.Errorf("sqlc: minConns (%d) must not exceed maxConns (%d)", c.MinConns, c.MaxConns)
+	}
+	return nil
+}
+
+// ErrDSNRequired is returned (wrapped) by the MustNew functions when the
+// configuration has no DSN.
+var ErrDSNRequired = errors.New("sqlc: dsn is required")
+
+// checkType reports an error when the configured type contradicts the
+// constructor being called. An empty type is accepted by both.
+func (c Config) checkType(want DBType) error {
+	if c.Type != "" && c.Type != want {
+		return fmt.Errorf("sqlc: config type is %q but a %s connection was requested", c.Type, want)
+	}
+	return nil
+}
+
+// dsnRequired describes a missing DSN for the given database type.
+func dsnRequired(dbType DBType) error {
+	return fmt.Errorf("%w: %s connection needs Config.DSN (yaml/json key \"dsn\") to be set", ErrDSNRequired, dbType)
+}
+
 // DBTX is the interface for database/sql operations (used by sqlc)
 type DBTX interface {
 	ExecContext(context.Context, string, ...interface{}) (sql.Result, error)
@@ -48,74 +103,88 @@ type DBTX interface {
 	QueryRowContext(context.Context, string, ...interface{}) *sql.Row
 }
 
-// NewPostgres creates a PostgreSQL connection pool for sqlc
+// NewPostgres creates a PostgreSQL connection pool for sqlc.
+// It returns nil, nil when the DSN is empty so the database stays optional,
+// and an error when Type is set to another database.
 func NewPostgres(ctx context.Context, c Config) (*pgxpool.Pool, error) {
+	if err := c.checkType(DBTypePostgres); err != nil {
+		return nil, err
+	}
 	if !c.IsEnabled() {
 		return nil, nil
 	}
 
+	c.SetDefaults()
+	if err := c.Validate(); err != nil {
+		return nil, err
+	}
+
 	config, err := pgxpool.ParseConfig(c.DSN)
 	if err != nil {
 		return nil, err
 	}
 
-	// Apply defaults
-	if c.MaxConns > 0 {
-		config.MaxConns = c.MaxConns
-	} else {
-		config.MaxConns = 10
-	}
-	if c.MinConns > 0 {
-		config.MinConns = c.MinConns
-	} else {
-		config.MinConns = 2
-	}
+	config.ConnConfig.RuntimeParams["application_name"] =
+		appname.Postgres(c.AppName, config.ConnConfig.RuntimeParams)
+	config.MaxConns = c.MaxConns
+	config.MinConns = c.MinConns
 
 	return pgxpool.NewWithConfig(ctx, config)
 }
 
-// MustNewPostgres creates a PostgreSQL connection pool for sqlc or panics
+// MustNewPostgres creates a PostgreSQL connection pool for sqlc or panics.
+// Unlike NewPostgres, an empty DSN is an error wrapping ErrDSNRequired.
 func MustNewPostgres(ctx context.Context, c Config) *pgxpool.Pool {
+	if err := c.checkType(DBTypePostgres); err != nil {
+		panic(err)
+	}
+	if !c.IsEnabled() {
+		panic(dsnRequired(DBTypePostgres))
+	}
 	pool, err := NewPostgres(ctx, c)
 	if err != nil {
 		panic(err)
 	}
-	if pool == nil {
-		panic("sqlc: postgres config not enabled")
-	}
 	return pool
 }
 
-// NewMySQL creates a MySQL connection for sqlc
+// NewMySQL creates a MySQL connection for sqlc.
+// It returns nil, nil when the DSN is empty so the database stays optional,
+// and an error when Type is set to another database.
 func NewMySQL(c Config) (*sql.DB, error) {
+	if err := c.checkType(DBTypeMySQL); err != nil {
+		return nil, err
+	}
 	if !c.IsEnabled() {
 		return nil, nil
 	}
 
-	db, err := sql.Open("mysql", c.DSN)
-	if err != nil {
+	c.SetDefaults()
+	if err := c.Validate(); err != nil {
 		return nil, err
 	}
 
-	// Apply defaults
-	maxConns := c.MaxConns
-	if maxConns == 0 {
-		maxConns = 10
+	db, err := sql.Open("mysql", c.DSN)
+	if err != nil {
+		return nil, err
 	}
-
-	db.SetMaxOpenConns(int(maxConns))
+	db.SetMaxOpenConns(int(c.MaxConns))
 
 	return db, nil
 }
 
-// MustNewMySQL creates a MySQL connection for sqlc or panics
+// MustNewMySQL creates a MySQL connection for sqlc or panics.
+// Unlike NewMySQL, an empty DSN is an error wrapping ErrDSNRequired.
 func MustNewMySQL(c Config) *sql.DB {
+	if err := c.checkType(DBTypeMySQL); err != nil {
+		panic(err)
+	}
+	if !c.IsEnabled() {
+		panic(dsnRequired(DBTypeMySQL))
+	}
 	db, err := NewMySQL(c)
 	if err != nil {
 		panic(err)
 	}
-	if db == nil {
-		panic("sqlc: mysql config not enabled")
-	}
 	return db
 }
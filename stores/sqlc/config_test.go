@@ -0,0 +1,124 @@
+package sqlc
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestConfigValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		cfg     Config
+		wantErr bool
+	}{
+		{name: "valid", cfg: Config{Type: DBTypeMySQL, MaxConns: 10, MinConns: 2}},
+		{name: "unknown type", cfg: Config{Type: "sqlite"}, wantErr: true},
+		{name: "negative max conns", cfg: Config{MaxConns: -1}, wantErr: true},
+		{name: "min above max conns", cfg: Config{MaxConns: 2, MinConns: 5}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.cfg.Validate(); (err != nil) != tt.wantErr {
+				t.Errorf("Validate() = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestConfigSetDefaults(t *testing.T) {
+	tests := []struct {
+		name string
+		cfg  Config
+		want Config
+	}{
+		{name: "empty", cfg: Config{}, want: Config{MaxConns: 10, MinConns: 2}},
+		{name: "small pool", cfg: Config{MaxConns: 1}, want: Config{MaxConns: 1, MinConns: 1}},
+		{
+			name: "explicit",
+			cfg:  Config{Type: DBTypeMySQL, MaxConns: 20, MinConns: 5},
+			want: Config{Type: DBTypeMySQL, MaxConns: 20, MinConns: 5},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := tt.cfg
+			c.SetDefaults()
+			if c != tt.want {
+				t.Fatalf("SetDefaults() = %+v, want %+v", c, tt.want)
+			}
+			c.SetDefaults()
+			if c != tt.want {
+				t.Errorf("second SetDefaults() = %+v, want %+v", c, tt.want)
+			}
+		})
+	}
+}
+
+func TestMustNewErrors(t *testing.T) {
+	mustNew := map[DBType]func(Config){
+		DBTypePostgres: func(c Config) { MustNewPostgres(context.Background(), c) },
+		DBTypeMySQL:    func(c Config) { MustNewMySQL(c) },
+	}
+
+	tests := []struct {
+		name       string
+		dbType     DBType
+		cfg        Config
+		wantDSN    bool
+		wantInText []string
+	}{
+		{
+			name:       "postgres without dsn",
+			dbType:     DBTypePostgres,
+			wantDSN:    true,
+			wantInText: []string{"postgres", "Config.DSN", `"dsn"`},
+		},
+		{
+			name:       "mysql without dsn",
+			dbType:     DBTypeMySQL,
+			wantDSN:    true,
+			wantInText: []string{"mysql", "Config.DSN", `"dsn"`},
+		},
+		{
+			name:       "type mismatch",
+			dbType:     DBTypeMySQL,
+			cfg:        Config{Type: DBTypePostgres, DSN: "postgres://localhost/app"},
+			wantInText: []string{`"postgres"`, "mysql connection"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			defer func() {
+				err, ok := recover().(error)
+				if !ok {
+					t.Fatal("MustNew did not panic with an error")
+				}
+				if errors.Is(err, ErrDSNRequired) != tt.wantDSN {
+					t.Errorf("panic %v: wraps ErrDSNRequired = %v, want %v", err, !tt.wantDSN, tt.wantDSN)
+				}
+				for _, want := range tt.wantInText {
+					if !strings.Contains(err.Error(), want) {
+						t.Errorf("panic %q does not mention %s", err, want)
+					}
+				}
+			}()
+			mustNew[tt.dbType](tt.cfg)
+		})
+	}
+}
+
+func TestNewWithoutDSN(t *testing.T) {
+	pool, err := NewPostgres(context.Background(), Config{})
+	if pool != nil || err != nil {
+		t.Errorf("NewPostgres() = %v, %v, want nil, nil", pool, err)
+	}
+	db, err := NewMySQL(Config{})
+	if db != nil || err != nil {
+		t.Errorf("NewMySQL() = %v, %v, want nil, nil", db, err)
+	}
+}
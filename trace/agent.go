@@ -6,6 +6,8 @@ import (
 	"strings"
 
 	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracegrpc"
 	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
 	"go.opentelemetry.io/otel/exporters/stdout/stdouttrace"
 	"go.opentelemetry.io/otel/propagation"
@@ -36,12 +38,14 @@ func StartAgent(cfg Config) (func(context.Context) error, error) {
 	}
 
 	cfg.SetDefaults()
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
 
 	// Create resource
 	res, err := resource.New(context.Background(),
-		resource.WithAttributes(
-			semconv.ServiceNameKey.String(cfg.Name),
-		),
+		resource.WithAttributes(resourceAttributes(cfg)...),
+		resource.WithHost(),
 	)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create resource: %w", err)
@@ -50,18 +54,19 @@ func StartAgent(cfg Config) (func(context.Context) error, error) {
 	// Create exporter
 	exporter, err := createExporter(cfg)
 	if err != nil {
+		if cfg.FailOpen {
+			logx.Warnw("Failed to create trace exporter, continuing without tracing",
+				"name", cfg.Name,
+				"exporter", cfg.Exporter,
+				"error", err,
+			)
+			return func(_ context.Context) error { return nil }, nil
+		}
 		return nil, fmt.Errorf("failed to create exporter: %w", err)
 	}
 
 	// Create sampler
-	var sampler sdktrace.Sampler
-	if cfg.SampleRate >= 1.0 {
-		sampler = sdktrace.AlwaysSample()
-	} else if cfg.SampleRate <= 0 {
-		sampler = sdktrace.NeverSample()
-	} else {
-		sampler = sdktrace.TraceIDRatioBased(cfg.SampleRate)
-	}
+	sampler := newSampler(cfg)
 
 	// Create TracerProvider
 	tp := sdktrace.NewTracerProvider(
@@ -69,13 +74,14 @@ func StartAgent(cfg Config) (func(context.Context) error, error) {
 			sdktrace.WithBatchTimeout(cfg.BatchTimeout),
 			sdktrace.WithExportTimeout(cfg.ExportTimeout),
 			sdktrace.WithMaxExportBatchSize(cfg.MaxExportBatchSize),
+			sdktrace.WithMaxQueueSize(cfg.MaxQueueSize),
 		),
 		sdktrace.WithResource(res),
 		sdktrace.WithSampler(sampler),
 	)
 
-	// Set global TracerProvider
-	otel.SetTracerProvider(tp)
+	// Set global TracerProvider, honoring per-component overrides
+	otel.SetTracerProvider(newComponentProvider(tp, cfg))
 
 	// Set global propagator
 	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(
@@ -85,14 +91,62 @@ func StartAgent(cfg Config) (func(context.Context) error, error) {
 
 	logx.Infow("Tracing initialized",
 		"name", cfg.Name,
+		"version", cfg.Version,
+		"environment", cfg.Environment,
 		"endpoint", cfg.Endpoint,
 		"exporter", cfg.Exporter,
+		"sampler", cfg.Sampler,
 		"sampleRate", cfg.SampleRate,
 	)
 
 	return tp.Shutdown, nil
 }
 
+// newSampler creates the sampler selected by cfg.Sampler. Whatever the
+// selection, spans started from a context marked with WithForceSample are
+// sampled and those started from a context marked with WithSuppressed are
+// dropped.
+func newSampler(cfg Config) sdktrace.Sampler {
+	switch strings.ToLower(cfg.Sampler) {
+	case "always":
+		return forceSampler{base: sdktrace.AlwaysSample()}
+	case "never":
+		return forceSampler{base: sdktrace.NeverSample()}
+	case "ratio":
+		return forceSampler{base: ratioSampler(cfg.SampleRate)}
+	default:
+		return forceSampler{base: sdktrace.ParentBased(ratioSampler(cfg.SampleRate))}
+	}
+}
+
+// ratioSampler samples the given fraction of traces.
+func ratioSampler(rate float64) sdktrace.Sampler {
+	if rate >= 1.0 {
+		return sdktrace.AlwaysSample()
+	}
+	if rate <= 0 {
+		return sdktrace.NeverSample()
+	}
+	return sdktrace.TraceIDRatioBased(rate)
+}
+
+// resourceAttributes returns the resource attributes describing the service.
+// Custom attributes cannot override the service name, version or environment.
+func resourceAttributes(cfg Config) []attribute.KeyValue {
+	attrs := make([]attribute.KeyValue, 0, len(cfg.Attributes)+3)
+	for key, value := range cfg.Attributes {
+		attrs = append(attrs, attribute.String(key, value))
+	}
+	attrs = append(attrs, semconv.ServiceNameKey.String(cfg.Name))
+	if cfg.Version != "" {
+		attrs = append(attrs, semconv.ServiceVersionKey.String(cfg.Version))
+	}
+	if cfg.Environment != "" {
+		attrs = append(attrs, semconv.DeploymentEnvironmentKey.String(cfg.Environment))
+	}
+	return attrs
+}
+
 // createExporter creates the appropriate exporter based on configuration.
 func createExporter(cfg Config) (sdktrace.SpanExporter, error) {
 	switch strings.ToLower(cfg.Exporter) {
@@ -111,8 +165,16 @@ func createExporter(cfg Config) (sdktrace.SpanExporter, error) {
 	}
 }
 
-// createOTLPExporter creates an OTLP HTTP exporter.
+// createOTLPExporter creates an OTLP exporter for the configured protocol.
 func createOTLPExporter(cfg Config) (sdktrace.SpanExporter, error) {
+	if strings.ToLower(cfg.Protocol) == "grpc" {
+		return createOTLPGRPCExporter(cfg)
+	}
+	return createOTLPHTTPExporter(cfg)
+}
+
+// createOTLPHTTPExporter creates an OTLP HTTP exporter.
+func createOTLPHTTPExporter(cfg Config) (sdktrace.SpanExporter, error) {
 	opts := []otlptracehttp.Option{
 		otlptracehttp.WithEndpoint(normalizeEndpoint(cfg.Endpoint)),
 	}
@@ -128,6 +190,25 @@ func createOTLPExporter(cfg Config) (sdktrace.SpanExporter, error) {
 	return otlptracehttp.New(context.Background(), opts...)
 }
 
+// createOTLPGRPCExporter creates an OTLP gRPC exporter.
+// The connection is established lazily, so an unreachable collector does not
+// fail startup.
+func createOTLPGRPCExporter(cfg Config) (sdktrace.SpanExporter, error) {
+	opts := []otlptracegrpc.Option{
+		otlptracegrpc.WithEndpoint(normalizeEndpoint(cfg.Endpoint)),
+	}
+
+	if cfg.Insecure {
+		opts = append(opts, otlptracegrpc.WithInsecure())
+	}
+
+	if len(cfg.Headers) > 0 {
+		opts = append(opts, otlptracegrpc.WithHeaders(cfg.Headers))
+	}
+
+	return otlptracegrpc.New(context.Background(), opts...)
+}
+
 // normalizeEndpoint removes protocol prefix from endpoint.
 func normalizeEndpoint(endpoint string) string {
 	endpoint = strings.TrimPrefix(endpoint, "http://")
@@ -0,0 +1,98 @@
+package trace
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"testing"
+
+	"go.opentelemetry.io/otel"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+)
+
+func TestStartAgentFailOpen(t *testing.T) {
+	tests := []struct {
+		name     string
+		failOpen bool
+		wantErr  bool
+	}{
+		{name: "fail closed", wantErr: true},
+		{name: "fail open", failOpen: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			before := otel.GetTracerProvider()
+			// The gRPC exporter rejects an endpoint that is not a valid URL
+			shutdown, err := StartAgent(Config{
+				Name:     "orders",
+				Endpoint: "%zz",
+				Protocol: "grpc",
+				FailOpen: tt.failOpen,
+			})
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("StartAgent() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if shutdown == nil {
+				t.Fatal("StartAgent() returned a nil shutdown function")
+			}
+			if err := shutdown(context.Background()); err != nil {
+				t.Errorf("shutdown() = %v", err)
+			}
+			if otel.GetTracerProvider() != before {
+				t.Error("StartAgent replaced the global tracer provider without an exporter")
+			}
+		})
+	}
+}
+
+// exporterKind describes exp by its type and, for OTLP exporters, the type
+// of the client carrying the spans.
+func exporterKind(exp sdktrace.SpanExporter) string {
+	kind := fmt.Sprintf("%T", exp)
+	if m, ok := exp.(interface{ MarshalLog() interface{} }); ok {
+		if client := reflect.ValueOf(m.MarshalLog()).FieldByName("Client"); client.IsValid() && !client.IsNil() {
+			kind += " " + client.Elem().Type().String()
+		}
+	}
+	return kind
+}
+
+func TestCreateExporter(t *testing.T) {
+	const (
+		otlpHTTP = "*otlptrace.Exporter *otlptracehttp.client"
+		otlpGRPC = "*otlptrace.Exporter *otlptracegrpc.client"
+	)
+	tests := []struct {
+		name string
+		cfg  Config
+		want string
+	}{
+		{name: "otlp default protocol", cfg: Config{}, want: otlpHTTP},
+		{name: "otlp http", cfg: Config{Exporter: "otlp", Protocol: "http"}, want: otlpHTTP},
+		{name: "otlp grpc", cfg: Config{Exporter: "otlp", Protocol: "GRPC"}, want: otlpGRPC},
+		{name: "jaeger grpc", cfg: Config{Exporter: "jaeger", Protocol: "grpc"}, want: otlpGRPC},
+		{name: "stdout", cfg: Config{Exporter: "stdout"}, want: "*stdouttrace.Exporter"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := tt.cfg
+			cfg.Name = "orders"
+			cfg.Endpoint = "localhost:4317"
+			cfg.SetDefaults()
+
+			exp, err := createExporter(cfg)
+			if err != nil {
+				t.Fatalf("createExporter() error = %v", err)
+			}
+			t.Cleanup(func() { _ = exp.Shutdown(context.Background()) })
+			if got := exporterKind(exp); got != tt.want {
+				t.Errorf("exporter = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
@@ -3,13 +3,27 @@
 // OTLP, Jaeger, and stdout exporters.
 package trace
 
-import "time"
+import (
+	"fmt"
+	"strings"
+	"time"
+)
 
 // Config represents the tracing configuration.
 type Config struct {
 	// Name is the service name for tracing.
 	Name string `yaml:"name,omitempty" json:"name,omitempty"`
 
+	// Version is the service version, recorded as service.version.
+	Version string `yaml:"version,omitempty" json:"version,omitempty"`
+
+	// Environment is the deployment environment (e.g. production, canary),
+	// recorded as deployment.environment.
+	Environment string `yaml:"environment,omitempty" json:"environment,omitempty"`
+
+	// Attributes are additional resource attributes added to every span.
+	Attributes map[string]string `yaml:"attributes,omitempty" json:"attributes,omitempty"`
+
 	// Endpoint is the collector endpoint URL.
 	// For OTLP: http://localhost:4318 or grpc://localhost:4317
 	// For Jaeger: http://localhost:14268/api/traces
@@ -23,6 +37,17 @@ type Config struct {
 	// Default: "http"
 	Protocol string `yaml:"protocol,omitempty" json:"protocol,omitempty"`
 
+	// Sampler selects the sampling strategy:
+	//   - "parentbased": follow the caller's sampling decision and sample
+	//     root spans at SampleRate
+	//   - "ratio": sample at SampleRate regardless of the caller
+	//   - "always" or "never"
+	// Spans started from a context marked with WithForceSample are sampled,
+	// and those started from a context marked with WithSuppressed dropped,
+	// with any of them.
+	// Default: "parentbased"
+	Sampler string `yaml:"sampler,omitempty" json:"sampler,omitempty"`
+
 	// SampleRate is the sampling rate (0.0 to 1.0).
 	// Default: 1.0 (sample everything)
 	SampleRate float64 `yaml:"sampleRate,omitempty" json:"sampleRate,omitempty"`
@@ -34,6 +59,11 @@ type Config struct {
 	// Insecure disables TLS for the connection.
 	Insecure bool `yaml:"insecure,omitempty" json:"insecure,omitempty"`
 
+	// FailOpen keeps the application running without tracing when the
+	// exporter cannot be created, instead of failing StartAgent.
+	// Default: false
+	FailOpen bool `yaml:"failOpen,omitempty" json:"failOpen,omitempty"`
+
 	// Headers are additional headers to send with traces.
 	Headers map[string]string `yaml:"headers,omitempty" json:"headers,omitempty"`
 
@@ -48,6 +78,24 @@ type Config struct {
 	// MaxExportBatchSize is the maximum number of spans to export in a batch.
 	// Default: 512
 	MaxExportBatchSize int `yaml:"maxExportBatchSize,omitempty" json:"maxExportBatchSize,omitempty"`
+
+	// MaxQueueSize is the maximum number of spans buffered for export;
+	// spans beyond it are dropped. It must be >= MaxExportBatchSize.
+	// Default: 2048, or MaxExportBatchSize if that is larger.
+	MaxQueueSize int `yaml:"maxQueueSize,omitempty" json:"maxQueueSize,omitempty"`
+
+	// Components overrides tracing per named tracer (component).
+	// A component mapped to false produces no spans; unlisted components
+	// follow the global settings.
+	Components map[string]bool `yaml:"components,omitempty" json:"components,omitempty"`
+}
+
+// IsComponentEnabled returns true if spans should be recorded for the named component.
+func (c Config) IsComponentEnabled(name string) bool {
+	if enabled, ok := c.Components[name]; ok {
+		return enabled
+	}
+	return true
 }
 
 // IsEnabled returns true if tracing should be enabled.
@@ -66,6 +114,9 @@ func (c *Config) SetDefaults() {
 	if c.Protocol == "" {
 		c.Protocol = "http"
 	}
+	if c.Sampler == "" {
+		c.Sampler = "parentbased"
+	}
 	if c.SampleRate == 0 {
 		c.SampleRate = 1.0
 	}
@@ -78,4 +129,44 @@ func (c *Config) SetDefaults() {
 	if c.MaxExportBatchSize == 0 {
 		c.MaxExportBatchSize = 512
 	}
+	if c.MaxQueueSize == 0 {
+		c.MaxQueueSize = max(2048, c.MaxExportBatchSize)
+	}
+}
+
+// Validate checks the configuration for invalid values.
+func (c *Config) Validate() error {
+	switch strings.ToLower(c.Exporter) {
+	case "", "otlp", "jaeger", "stdout":
+	default:
+		return fmt.Errorf("trace: unknown exporter %q (expected otlp, jaeger or stdout)", c.Exporter)
+	}
+	switch strings.ToLower(c.Protocol) {
+	case "", "grpc", "http":
+	default:
+		return fmt.Errorf("trace: unknown protocol %q (expected grpc or http)", c.Protocol)
+	}
+	switch strings.ToLower(c.Sampler) {
+	case "", "parentbased", "ratio", "always", "never":
+	default:
+		return fmt.Errorf("trace: unknown sampler %q (expected parentbased, ratio, always or never)", c.Sampler)
+	}
+	if c.SampleRate < 0 || c.SampleRate > 1 {
+		return fmt.Errorf("trace: sampleRate must be in [0, 1], got %g", c.SampleRate)
+	}
+	if c.BatchTimeout < 0 || c.ExportTimeout < 0 {
+		return fmt.Errorf("trace: batchTimeout and exportTimeout must be >= 0")
+	}
+	if c.MaxExportBatchSize < 0 {
+		return fmt.Errorf("trace: maxExportBatchSize must be >= 0, got %d", c.MaxExportBatchSize)
+	}
+	if c.MaxQueueSize < 0 {
+		return fmt.Errorf("trace: maxQueueSize must be >= 0, got %d", c.MaxQueueSize)
+	}
+	if c.MaxQueueSize > 0 && c.MaxExportBatchSize > c.MaxQueueSize {
+		return fmt.Errorf(
+			"trace: maxExportBatchSize (%d) must not exceed maxQueueSize (%d); batches can never be larger than the queue",
+			c.MaxExportBatchSize, c.MaxQueueSize)
+	}
+	return nil
 }
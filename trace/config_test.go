@@ -0,0 +1,60 @@
+package trace
+
+import (
+	"testing"
+	"time"
+)
+
+func TestConfigValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		cfg     Config
+		wantErr bool
+	}{
+		{name: "empty", cfg: Config{}},
+		{name: "valid", cfg: Config{Exporter: "OTLP", Protocol: "http", SampleRate: 0.5}},
+		{name: "unknown exporter", cfg: Config{Exporter: "zipkin"}, wantErr: true},
+		{name: "unknown protocol", cfg: Config{Protocol: "thrift"}, wantErr: true},
+		{name: "sample rate above one", cfg: Config{SampleRate: 1.5}, wantErr: true},
+		{name: "negative batch timeout", cfg: Config{BatchTimeout: -time.Second}, wantErr: true},
+		{name: "negative max export batch size", cfg: Config{MaxExportBatchSize: -1}, wantErr: true},
+		{name: "batch fills the queue", cfg: Config{MaxExportBatchSize: 2048, MaxQueueSize: 2048}},
+		{
+			name:    "batch larger than the queue",
+			cfg:     Config{MaxExportBatchSize: 4096, MaxQueueSize: 2048},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.cfg.Validate(); (err != nil) != tt.wantErr {
+				t.Errorf("Validate() = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestConfigSetDefaultsQueueSize(t *testing.T) {
+	tests := []struct {
+		name      string
+		cfg       Config
+		wantBatch int
+		wantQueue int
+	}{
+		{name: "defaults", wantBatch: 512, wantQueue: 2048},
+		{name: "large batch grows the queue", cfg: Config{MaxExportBatchSize: 4096}, wantBatch: 4096, wantQueue: 4096},
+		{name: "explicit queue", cfg: Config{MaxQueueSize: 8192}, wantBatch: 512, wantQueue: 8192},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := tt.cfg
+			cfg.SetDefaults()
+			if cfg.MaxExportBatchSize != tt.wantBatch || cfg.MaxQueueSize != tt.wantQueue {
+				t.Errorf("batch, queue = %d, %d, want %d, %d",
+					cfg.MaxExportBatchSize, cfg.MaxQueueSize, tt.wantBatch, tt.wantQueue)
+			}
+		})
+	}
+}
@@ -0,0 +1,73 @@
+package trace
+
+import (
+	"context"
+
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	oteltrace "go.opentelemetry.io/otel/trace"
+)
+
+// ForceSampleHeader is the default request header used to force sampling of
+// a request, see middleware.ForceTrace.
+const ForceSampleHeader = "X-Force-Trace"
+
+type forceSampleKey struct{}
+
+// WithForceSample marks ctx so that spans started from it, and the spans of
+// the same trace started in this process, are sampled whatever the
+// configured sampler decides. Transport middlewares call it, before the
+// tracing middleware, for requests that asked to be traced.
+func WithForceSample(ctx context.Context) context.Context {
+	return context.WithValue(ctx, forceSampleKey{}, true)
+}
+
+// IsForceSampled reports whether ctx was marked with WithForceSample.
+func IsForceSampled(ctx context.Context) bool {
+	forced, _ := ctx.Value(forceSampleKey{}).(bool)
+	return forced
+}
+
+type suppressKey struct{}
+
+// WithSuppressed marks ctx so that spans started from it are dropped, even
+// when the context is also marked with WithForceSample. Transport
+// middlewares call it for calls that opted out of tracing, such as health
+// pings, so that the tracing suites start non-recording spans.
+func WithSuppressed(ctx context.Context) context.Context {
+	return context.WithValue(ctx, suppressKey{}, true)
+}
+
+// IsSuppressed reports whether ctx was marked with WithSuppressed.
+func IsSuppressed(ctx context.Context) bool {
+	suppressed, _ := ctx.Value(suppressKey{}).(bool)
+	return suppressed
+}
+
+// forceSampler drops spans started from a context marked with
+// WithSuppressed, samples those started from a context marked with
+// WithForceSample and defers to base for all others.
+type forceSampler struct {
+	base sdktrace.Sampler
+}
+
+// ShouldSample implements sdktrace.Sampler.
+func (s forceSampler) ShouldSample(p sdktrace.SamplingParameters) sdktrace.SamplingResult {
+	if p.ParentContext != nil && IsSuppressed(p.ParentContext) {
+		return sdktrace.SamplingResult{
+			Decision:   sdktrace.Drop,
+			Tracestate: oteltrace.SpanContextFromContext(p.ParentContext).TraceState(),
+		}
+	}
+	if p.ParentContext != nil && IsForceSampled(p.ParentContext) {
+		return sdktrace.SamplingResult{
+			Decision:   sdktrace.RecordAndSample,
+			Tracestate: oteltrace.SpanContextFromContext(p.ParentContext).TraceState(),
+		}
+	}
+	return s.base.ShouldSample(p)
+}
+
+// Description implements sdktrace.Sampler.
+func (s forceSampler) Description() string {
+	return "ForceSampler{" + s.base.Description() + "}"
+}
@@ -0,0 +1,47 @@
+package trace
+
+import (
+	"context"
+	"testing"
+
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+)
+
+func TestNewSamplerForceSample(t *testing.T) {
+	tests := []struct {
+		name       string
+		cfg        Config
+		force      bool
+		suppress   bool
+		wantSample bool
+	}{
+		{name: "ratio 0", cfg: Config{Sampler: "ratio", SampleRate: 0}},
+		{name: "ratio 0 forced", cfg: Config{Sampler: "ratio", SampleRate: 0}, force: true, wantSample: true},
+		{name: "parentbased 0 forced", cfg: Config{Sampler: "parentbased", SampleRate: 0}, force: true, wantSample: true},
+		{name: "never forced", cfg: Config{Sampler: "never"}, force: true, wantSample: true},
+		{name: "always", cfg: Config{Sampler: "always"}, wantSample: true},
+		{name: "always suppressed", cfg: Config{Sampler: "always"}, suppress: true},
+		{name: "forced and suppressed", cfg: Config{Sampler: "always"}, force: true, suppress: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tp := sdktrace.NewTracerProvider(sdktrace.WithSampler(newSampler(tt.cfg)))
+			t.Cleanup(func() { _ = tp.Shutdown(context.Background()) })
+
+			ctx := context.Background()
+			if tt.force {
+				ctx = WithForceSample(ctx)
+			}
+			if tt.suppress {
+				ctx = WithSuppressed(ctx)
+			}
+			_, span := tp.Tracer("test").Start(ctx, "op")
+			defer span.End()
+
+			if got := span.SpanContext().IsSampled(); got != tt.wantSample {
+				t.Errorf("sampled = %v, want %v", got, tt.wantSample)
+			}
+		})
+	}
+}
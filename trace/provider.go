@@ -0,0 +1,40 @@
+package trace
+
+import (
+	"go.opentelemetry.io/otel/trace"
+	"go.opentelemetry.io/otel/trace/embedded"
+	"go.opentelemetry.io/otel/trace/noop"
+)
+
+// componentProvider wraps a TracerProvider and hands out no-op tracers for
+// components that have been disabled in the configuration.
+type componentProvider struct {
+	embedded.TracerProvider
+
+	provider trace.TracerProvider
+	config   Config
+}
+
+// newComponentProvider wraps provider so that tracers whose name is disabled
+// in cfg.Components produce no spans. If no component is disabled, provider
+// is returned unchanged.
+func newComponentProvider(provider trace.TracerProvider, cfg Config) trace.TracerProvider {
+	for name := range cfg.Components {
+		if !cfg.IsComponentEnabled(name) {
+			return &componentProvider{
+				provider: provider,
+				config:   cfg,
+			}
+		}
+	}
+	return provider
+}
+
+// Tracer returns a tracer for the named component.
+// Disabled components receive a no-op tracer.
+func (p *componentProvider) Tracer(name string, opts ...trace.TracerOption) trace.Tracer {
+	if !p.config.IsComponentEnabled(name) {
+		return noop.NewTracerProvider().Tracer(name, opts...)
+	}
+	return p.provider.Tracer(name, opts...)
+}
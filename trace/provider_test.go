@@ -0,0 +1,60 @@
+package trace
+
+import (
+	"context"
+	"testing"
+
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func TestComponentProvider(t *testing.T) {
+	overrides := map[string]bool{"orders": true, "audit": false}
+	tests := []struct {
+		name       string
+		components map[string]bool
+		tracer     string
+		wantSpans  int
+	}{
+		{name: "no overrides", tracer: "orders", wantSpans: 1},
+		{name: "enabled component", components: overrides, tracer: "orders", wantSpans: 1},
+		{name: "unlisted component", components: overrides, tracer: "billing", wantSpans: 1},
+		{name: "disabled component", components: overrides, tracer: "audit", wantSpans: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			exporter := tracetest.NewInMemoryExporter()
+			tp := sdktrace.NewTracerProvider(sdktrace.WithSyncer(exporter))
+			t.Cleanup(func() { _ = tp.Shutdown(context.Background()) })
+
+			provider := newComponentProvider(tp, Config{Components: tt.components})
+			_, span := provider.Tracer(tt.tracer).Start(context.Background(), "op")
+			span.End()
+
+			if got := len(exporter.GetSpans()); got != tt.wantSpans {
+				t.Errorf("exported spans = %d, want %d", got, tt.wantSpans)
+			}
+		})
+	}
+}
+
+func TestIsComponentEnabled(t *testing.T) {
+	cfg := Config{Components: map[string]bool{"orders": true, "audit": false}}
+	tests := []struct {
+		component string
+		want      bool
+	}{
+		{component: "orders", want: true},
+		{component: "audit", want: false},
+		{component: "unlisted", want: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.component, func(t *testing.T) {
+			if got := cfg.IsComponentEnabled(tt.component); got != tt.want {
+				t.Errorf("IsComponentEnabled(%q) = %v, want %v", tt.component, got, tt.want)
+			}
+		})
+	}
+}
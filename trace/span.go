@@ -0,0 +1,68 @@
+package trace
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// TracerName is the name of the tracer used by Start. Use it as the key in
+// Config.Components to turn application spans on or off.
+const TracerName = "github.com/ssgohq/goten-core/trace"
+
+// Span is an OpenTelemetry span.
+type Span = trace.Span
+
+// Attribute is a key-value pair attached to spans and events.
+type Attribute = attribute.KeyValue
+
+// Attribute constructors.
+var (
+	String  = attribute.String
+	Int     = attribute.Int
+	Int64   = attribute.Int64
+	Float64 = attribute.Float64
+	Bool    = attribute.Bool
+)
+
+// Start starts a span named name as a child of the span in ctx, using the
+// global tracer provider set up by StartAgent. End the span when done.
+//
+// Example:
+//
+//	ctx, span := trace.Start(ctx, "LoadProfile", trace.String("user.id", id))
+//	defer span.End()
+func Start(ctx context.Context, name string, attrs ...Attribute) (context.Context, Span) {
+	return otel.Tracer(TracerName).Start(ctx, name, trace.WithAttributes(attrs...))
+}
+
+// AddEvent adds an event to the span in ctx. It is a no-op without a
+// recording span.
+//
+// Example:
+//
+//	trace.AddEvent(ctx, "cache miss", trace.String("key", key))
+func AddEvent(ctx context.Context, name string, attrs ...Attribute) {
+	trace.SpanFromContext(ctx).AddEvent(name, trace.WithAttributes(attrs...))
+}
+
+// RecordError records err on the span in ctx and sets the span status to
+// Error. It is a no-op when err is nil or without a recording span.
+//
+// Example:
+//
+//	if err != nil {
+//	    trace.RecordError(ctx, err)
+//	    return err
+//	}
+func RecordError(ctx context.Context, err error) {
+	if err == nil {
+		return
+	}
+	span := trace.SpanFromContext(ctx)
+	span.RecordError(err)
+	span.SetStatus(codes.Error, err.Error())
+}
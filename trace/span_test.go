@@ -0,0 +1,97 @@
+package trace
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+// useInMemoryExporter installs a global tracer provider exporting to an
+// in-memory exporter for the duration of the test.
+func useInMemoryExporter(t *testing.T) *tracetest.InMemoryExporter {
+	t.Helper()
+	exporter := tracetest.NewInMemoryExporter()
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSyncer(exporter))
+	previous := otel.GetTracerProvider()
+	otel.SetTracerProvider(tp)
+	t.Cleanup(func() {
+		otel.SetTracerProvider(previous)
+		_ = tp.Shutdown(context.Background())
+	})
+	return exporter
+}
+
+func TestSpanHelpers(t *testing.T) {
+	errLoad := errors.New("profile not found")
+	tests := []struct {
+		name       string
+		run        func(ctx context.Context)
+		wantEvents []string
+		wantStatus codes.Code
+	}{
+		{name: "plain span", run: func(context.Context) {}},
+		{
+			name:       "event",
+			run:        func(ctx context.Context) { AddEvent(ctx, "cache miss", String("key", "user:1")) },
+			wantEvents: []string{"cache miss"},
+		},
+		{
+			name:       "error",
+			run:        func(ctx context.Context) { RecordError(ctx, errLoad) },
+			wantEvents: []string{"exception"},
+			wantStatus: codes.Error,
+		},
+		{name: "nil error", run: func(ctx context.Context) { RecordError(ctx, nil) }},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			exporter := useInMemoryExporter(t)
+
+			ctx, span := Start(context.Background(), "LoadProfile", String("user.id", "1"), Int("attempt", 2))
+			tt.run(ctx)
+			span.End()
+
+			spans := exporter.GetSpans()
+			if len(spans) != 1 {
+				t.Fatalf("exported spans = %d, want 1", len(spans))
+			}
+			got := spans[0]
+			if got.Name != "LoadProfile" {
+				t.Errorf("span name = %q, want LoadProfile", got.Name)
+			}
+			if got.InstrumentationScope.Name != TracerName {
+				t.Errorf("tracer = %q, want %q", got.InstrumentationScope.Name, TracerName)
+			}
+			wantAttrs := []attribute.KeyValue{String("user.id", "1"), Int("attempt", 2)}
+			if len(got.Attributes) != len(wantAttrs) {
+				t.Errorf("attributes = %v, want %v", got.Attributes, wantAttrs)
+			}
+			for i, want := range wantAttrs {
+				if i < len(got.Attributes) && got.Attributes[i] != want {
+					t.Errorf("attribute %d = %v, want %v", i, got.Attributes[i], want)
+				}
+			}
+
+			var events []string
+			for _, event := range got.Events {
+				events = append(events, event.Name)
+			}
+			if len(events) != len(tt.wantEvents) || (len(events) > 0 && events[0] != tt.wantEvents[0]) {
+				t.Errorf("events = %q, want %q", events, tt.wantEvents)
+			}
+			if got.Status.Code != tt.wantStatus {
+				t.Errorf("status = %v, want %v", got.Status.Code, tt.wantStatus)
+			}
+			if tt.wantStatus == codes.Error && got.Status.Description != errLoad.Error() {
+				t.Errorf("status description = %q, want %q", got.Status.Description, errLoad)
+			}
+		})
+	}
+}